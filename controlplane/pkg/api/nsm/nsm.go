@@ -106,4 +106,10 @@ type NetworkServiceEndpointManager interface {
 	CreateNSEClient(ctx context.Context, endpoint *registry.NSERegistration) (NetworkServiceClient, error)
 	IsLocalEndpoint(endpoint *registry.NSERegistration) bool
 	CheckUpdateNSE(ctx context.Context, reg *registry.NSERegistration) bool
+	// CheckUpdateNSEWithError - same as CheckUpdateNSE, returns error NSE client could not be created with.
+	CheckUpdateNSEWithError(ctx context.Context, reg *registry.NSERegistration) error
+	// ReportRTT - records the last measured round trip time to endpoint.
+	ReportRTT(endpoint registry.EndpointNSMName, rtt time.Duration)
+	// ReportSLAViolation - records that connection routed to endpoint violates its SLA.
+	ReportSLAViolation(endpointName, connectionID string)
 }
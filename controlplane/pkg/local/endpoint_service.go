@@ -16,6 +16,7 @@ package local
 
 import (
 	"context"
+	"time"
 
 	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
 
@@ -104,6 +105,7 @@ func (cce *endpointService) Request(ctx context.Context, request *networkservice
 	defer span.Finish()
 	span.LogObject("nse.request", message)
 
+	requestStart := time.Now()
 	nseConn, e := client.Request(ctx, message)
 	span.LogObject("nse.response", nseConn)
 	if e != nil {
@@ -111,6 +113,7 @@ func (cce *endpointService) Request(ctx context.Context, request *networkservice
 		span.LogError(e)
 		return nil, e
 	}
+	cce.nseManager.ReportRTT(endpoint.GetEndpointNSMName(), time.Since(requestStart))
 	// 7.2.6.2.2
 	if err = cce.updateConnectionContext(ctx, request.GetConnection(), nseConn); err != nil {
 		err = errors.Errorf("NSM:(7.2.6.2.2) failure Validating NSE Connection: %s", err)
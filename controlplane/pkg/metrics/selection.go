@@ -0,0 +1,195 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// SelectionTotal is counter name for "nsm_selection_total"
+	SelectionTotal = "nsm_selection_total"
+	// ShadowSelectionTotal is counter name for "nsm_shadow_selection_total"
+	ShadowSelectionTotal = "nsm_shadow_selection_total"
+	// SelectionFailuresTotal is counter name for "nsm_selection_failures_total"
+	SelectionFailuresTotal = "nsm_selection_failures_total"
+	// DiscoveryDurationSeconds is histogram name for "nsm_discovery_duration_seconds"
+	DiscoveryDurationSeconds = "nsm_discovery_duration_seconds"
+	// ClientCreationTotal is counter name for "nsm_client_creation_total"
+	ClientCreationTotal = "nsm_client_creation_total"
+	// RemoteDialDurationSeconds is histogram name for "nsm_remote_dial_duration_seconds"
+	RemoteDialDurationSeconds = "nsm_remote_dial_duration_seconds"
+	// EndpointResolutionDurationSeconds is histogram name for "nsm_endpoint_resolution_duration_seconds"
+	EndpointResolutionDurationSeconds = "nsm_endpoint_resolution_duration_seconds"
+
+	// ServiceKey is counter label for network service
+	ServiceKey = "service"
+	// ReasonKey is counter label for reason code of endpoint selection
+	ReasonKey = "reason"
+	// OutcomeKey is label for whether shadow selection agreed with active one, or whether endpoint was resolved
+	OutcomeKey = "outcome"
+	// CauseKey is counter label for cause of failed endpoint selection
+	CauseKey = "cause"
+	// LocalityKey is counter label for whether endpoint client is local or remote
+	LocalityKey = "locality"
+	// PathKey is histogram label for how endpoint was resolved
+	PathKey = "path"
+
+	// ShadowAgreed is outcome of shadow selection choosing the same endpoint as active selector
+	ShadowAgreed = "agreed"
+	// ShadowDiverged is outcome of shadow selection choosing another endpoint than active selector
+	ShadowDiverged = "diverged"
+
+	// FailureNoEndpoints is cause of failed selection when no endpoint of network service could be selected
+	FailureNoEndpoints = "no_endpoints"
+	// FailureSelectorNil is cause of failed selection when selector selected no endpoint although it was given candidates
+	FailureSelectorNil = "selector_nil"
+	// FailureTargetNotFound is cause of failed selection when endpoint request is targeted to is not found
+	FailureTargetNotFound = "target_not_found"
+
+	// LocalityLocal is locality of endpoint hosted by local NSM
+	LocalityLocal = "local"
+	// LocalityRemote is locality of endpoint hosted by remote NSM
+	LocalityRemote = "remote"
+
+	// PathTargeted is path of resolving endpoint request is targeted to
+	PathTargeted = "targeted"
+	// PathSelected is path of selecting endpoint among discovered ones
+	PathSelected = "selected"
+	// PathLocalOnly is path of selecting endpoint among local ones as discovery failed
+	PathLocalOnly = "local-only"
+
+	// OutcomeSuccess is outcome of resolution which returned endpoint
+	OutcomeSuccess = "success"
+	// OutcomeFailure is outcome of resolution which failed
+	OutcomeFailure = "failure"
+)
+
+// BuildSelectionCounter builds prometheus counter of endpoint
+// selections by network service and reason code, counter
+// already registered is reused
+func BuildSelectionCounter() *prometheus.CounterVec {
+	return registerCounterVec(prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SelectionTotal,
+			Help: "Endpoint selections by network service and reason code",
+		},
+		[]string{ServiceKey, ReasonKey},
+	))
+}
+
+// BuildShadowSelectionCounter builds prometheus counter of shadow
+// selections by network service and outcome, counter already
+// registered is reused
+func BuildShadowSelectionCounter() *prometheus.CounterVec {
+	return registerCounterVec(prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ShadowSelectionTotal,
+			Help: "Shadow endpoint selections by network service and outcome",
+		},
+		[]string{ServiceKey, OutcomeKey},
+	))
+}
+
+// BuildSelectionFailureCounter builds prometheus counter of failed
+// endpoint selections by network service and cause, counter
+// already registered is reused
+func BuildSelectionFailureCounter() *prometheus.CounterVec {
+	return registerCounterVec(prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: SelectionFailuresTotal,
+			Help: "Failed endpoint selections by network service and cause",
+		},
+		[]string{ServiceKey, CauseKey},
+	))
+}
+
+// BuildDiscoveryHistogram builds prometheus histogram of discovery
+// RPC latency by network service, histogram already registered is
+// reused
+func BuildDiscoveryHistogram() *prometheus.HistogramVec {
+	return registerHistogramVec(prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    DiscoveryDurationSeconds,
+			Help:    "Latency of network service discovery requests to registry by network service",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{ServiceKey},
+	))
+}
+
+// BuildClientCreationCounter builds prometheus counter of endpoint
+// clients created by network service and locality, counter already
+// registered is reused
+func BuildClientCreationCounter() *prometheus.CounterVec {
+	return registerCounterVec(prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: ClientCreationTotal,
+			Help: "Endpoint client creations by network service and locality of endpoint",
+		},
+		[]string{ServiceKey, LocalityKey},
+	))
+}
+
+// BuildRemoteDialHistogram builds prometheus histogram of latency of
+// dialing remote NSM by network service, histogram already registered
+// is reused
+func BuildRemoteDialHistogram() *prometheus.HistogramVec {
+	return registerHistogramVec(prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    RemoteDialDurationSeconds,
+			Help:    "Latency of dialing remote NSM for endpoint client by network service",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{ServiceKey},
+	))
+}
+
+// BuildEndpointResolutionHistogram builds prometheus histogram of
+// latency of resolving endpoint for request by network service, path
+// and outcome, histogram already registered is reused
+func BuildEndpointResolutionHistogram() *prometheus.HistogramVec {
+	return registerHistogramVec(prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    EndpointResolutionDurationSeconds,
+			Help:    "Latency of resolving endpoint for request by network service, resolution path and outcome",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{ServiceKey, PathKey, OutcomeKey},
+	))
+}
+
+func registerHistogramVec(histogramVec *prometheus.HistogramVec) *prometheus.HistogramVec {
+	if err := prometheus.Register(histogramVec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.HistogramVec)
+		}
+		logrus.Infof("failed to register vector %v, err: %v", histogramVec, err)
+	}
+	return histogramVec
+}
+
+func registerCounterVec(counterVec *prometheus.CounterVec) *prometheus.CounterVec {
+	if err := prometheus.Register(counterVec); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(*prometheus.CounterVec)
+		}
+		logrus.Infof("failed to register vector %v, err: %v", counterVec, err)
+	}
+	return counterVec
+}
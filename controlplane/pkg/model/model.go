@@ -13,6 +13,7 @@ import (
 
 type Model interface {
 	GetEndpointsByNetworkService(nsName string) []*Endpoint
+	GetAllEndpoints() []*Endpoint
 
 	AddEndpoint(ctx context.Context, endpoint *Endpoint)
 	GetEndpoint(name string) *Endpoint
@@ -119,11 +120,16 @@ func (m *model) ListenerCount() int {
 
 // NewModel returns new instance of Model
 func NewModel() Model {
+	return NewModelWithSelector(selector.NewMatchSelector())
+}
+
+// NewModelWithSelector creates model selecting endpoints with endpointSelector, e.g. selector.NewWeightedSelector()
+func NewModelWithSelector(endpointSelector selector.Selector) Model {
 	return &model{
 		clientConnectionDomain: newClientConnectionDomain(),
 		endpointDomain:         newEndpointDomain(),
 		forwarderDomain:        newForwarderDomain(),
-		selector:               selector.NewMatchSelector(),
+		selector:               endpointSelector,
 		listeners:              make(map[Listener]func()),
 	}
 }
@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+// ApprovalInvalidator - approval gate able to invalidate its earlier denials, e.g. when its policy changes.
+// Denials cached before InvalidatedAt are dropped and re-evaluated.
+type ApprovalInvalidator interface {
+	InvalidatedAt() time.Time
+}
+
+type approvalDenial struct {
+	reason   string
+	deniedAt time.Time
+	until    time.Time
+}
+
+// approvalDenials - approval gate denials keyed by client identity and endpoint identity, zero value is ready to use.
+type approvalDenials struct {
+	sync.Mutex
+	denials map[string]approvalDenial
+}
+
+func (d *approvalDenials) add(client, endpoint, reason string, timeout time.Duration) {
+	d.Lock()
+	defer d.Unlock()
+	if d.denials == nil {
+		d.denials = map[string]approvalDenial{}
+	}
+	now := time.Now()
+	d.denials[client+"|"+endpoint] = approvalDenial{
+		reason:   reason,
+		deniedAt: now,
+		until:    now.Add(timeout),
+	}
+}
+
+// get - returns reason of cached denial, denials expired or made before invalidatedAt are dropped.
+func (d *approvalDenials) get(client, endpoint string, invalidatedAt time.Time) (string, bool) {
+	d.Lock()
+	defer d.Unlock()
+	key := client + "|" + endpoint
+	denial, ok := d.denials[key]
+	if !ok {
+		return "", false
+	}
+	if time.Now().After(denial.until) || !denial.deniedAt.After(invalidatedAt) {
+		delete(d.denials, key)
+		return "", false
+	}
+	return denial.reason, true
+}
+
+// clientIdentity - identifies client of request by its pod, or by namespaced connection id if pod is not known.
+func (nsem *nseManager) clientIdentity(requestConnection *connection.Connection) string {
+	labels := requestConnection.GetLabels()
+	if pod := labels[connection.PodNameKey]; pod != "" {
+		return labels[connection.NamespaceKey] + "/" + pod
+	}
+	return nsem.connectionKey(requestConnection)
+}
+
+// deniedApproval - returns reason of cached approval denial of endpoint for client of request.
+func (nsem *nseManager) deniedApproval(requestConnection *connection.Connection, endpointKey string) (string, bool) {
+	if nsem.approvalGate == nil || nsem.props.ApprovalDenialCacheTTL <= 0 {
+		return "", false
+	}
+	var invalidatedAt time.Time
+	if invalidator, ok := nsem.approvalGate.(ApprovalInvalidator); ok {
+		invalidatedAt = invalidator.InvalidatedAt()
+	}
+	return nsem.denials.get(nsem.clientIdentity(requestConnection), endpointKey, invalidatedAt)
+}
+
+// cacheDenial - remembers approval denial of endpoint for client of request for properties.ApprovalDenialCacheTTL.
+func (nsem *nseManager) cacheDenial(requestConnection *connection.Connection, endpointKey, reason string) {
+	if nsem.props.ApprovalDenialCacheTTL > 0 {
+		nsem.denials.add(nsem.clientIdentity(requestConnection), endpointKey, reason, nsem.props.ApprovalDenialCacheTTL)
+	}
+}
@@ -0,0 +1,90 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+type invalidatingGateStub struct {
+	approvalGateStub
+	invalidatedAt time.Time
+}
+
+func (stub *invalidatingGateStub) InvalidatedAt() time.Time {
+	return stub.invalidatedAt
+}
+
+func TestApprovalDenials_Cached(t *testing.T) {
+	g := NewWithT(t)
+	gate := &approvalGateStub{denied: map[string]string{nse1Name: "tenant is not allowed"}}
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(gate)(data.nseManager)
+
+	for i := 0; i < 2; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(connection.NamespaceKey, "default"), withLabel(connection.PodNameKey, "client-1")), nil)
+		g.Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+		g.Expect(err.Error()).To(ContainSubstring("tenant is not allowed"))
+	}
+	g.Expect(gate.chosen).To(Equal([]string{nse1Name}))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(connection.NamespaceKey, "default"), withLabel(connection.PodNameKey, "client-2")), nil)
+	g.Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	g.Expect(gate.chosen).To(Equal([]string{nse1Name, nse1Name}))
+}
+
+func TestApprovalDenials_SkippedBySelection(t *testing.T) {
+	g := NewWithT(t)
+	gate := &approvalGateStub{denied: map[string]string{nse1Name: "tenant is not allowed"}}
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(gate)(data.nseManager)
+	data.nseManager.denials.add(data.nseManager.clientIdentity(newTestRequestConnection()), nse1Name+":", "tenant is not allowed", time.Minute)
+
+	for i := 0; i < 2; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+	g.Expect(gate.chosen).To(Equal([]string{nse2Name, nse2Name}))
+}
+
+func TestApprovalDenials_Expire(t *testing.T) {
+	g := NewWithT(t)
+	gate := &approvalGateStub{denied: map[string]string{nse1Name: "tenant is not allowed"}}
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(gate)(data.nseManager)
+	data.nseManager.props.ApprovalDenialCacheTTL = 50 * time.Millisecond
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(connection.NamespaceKey, "default"), withLabel(connection.PodNameKey, "client-1")), nil)
+	g.Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	<-time.After(100 * time.Millisecond)
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(connection.NamespaceKey, "default"), withLabel(connection.PodNameKey, "client-1")), nil)
+	g.Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	g.Expect(gate.chosen).To(Equal([]string{nse1Name, nse1Name}))
+}
+
+func TestApprovalDenials_Invalidated(t *testing.T) {
+	g := NewWithT(t)
+	gate := &invalidatingGateStub{
+		approvalGateStub: approvalGateStub{denied: map[string]string{nse1Name: "tenant is not allowed"}},
+	}
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(gate)(data.nseManager)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(connection.NamespaceKey, "default"), withLabel(connection.PodNameKey, "client-1")), nil)
+	g.Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+
+	gate.denied = nil
+	gate.invalidatedAt = time.Now()
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(connection.NamespaceKey, "default"), withLabel(connection.PodNameKey, "client-1")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(gate.chosen).To(Equal([]string{nse1Name, nse1Name}))
+}
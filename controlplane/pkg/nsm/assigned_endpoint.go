@@ -0,0 +1,62 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// assignedEndpoint - returns remote endpoint request is targeted to if the connection being re-requested is already
+// routed to it and NSE client to it could still be created, so it is kept without discovery. Nil is returned if
+// endpoint could not be reused, request goes through discovery then.
+func (nsem *nseManager) assignedEndpoint(ctx context.Context, span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, error) {
+	if !nsem.props.ReuseAssignedEndpoint {
+		return nil, nil
+	}
+	clientConnection := nsem.model.GetClientConnection(requestConnection.GetId())
+	if clientConnection == nil || clientConnection.Endpoint == nil {
+		return nil, nil
+	}
+	assigned := clientConnection.Endpoint
+	endpoint, manager := assigned.GetNetworkServiceEndpoint(), assigned.GetNetworkServiceManager()
+	if endpoint.GetName() != requestConnection.GetNetworkServiceEndpointName() ||
+		endpoint.GetNetworkServiceManagerName() != requestConnection.GetDestinationNetworkServiceManagerName() ||
+		assigned.GetNetworkService().GetName() != requestConnection.GetNetworkService() {
+		return nil, nil
+	}
+	key := nsem.identity.Key(endpoint, manager)
+	if nsem.isIgnored(endpoint, manager, ignoreEndpoints) || nsem.quarantine.contains(key) || nsem.unreachable.contains(key) ||
+		nsem.blacklist.contains(key) {
+		return nil, nil
+	}
+	if err := nsem.CheckUpdateNSEWithError(span.Context(), assigned); err != nil {
+		span.Logger().Warnf("Assigned endpoint %v could not be reused, discovering endpoints: %v", assigned.GetEndpointNSMName(), err)
+		return nil, nil
+	}
+	if err := nsem.approve(ctx, requestConnection, assigned); err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	span.LogValue("selector", "bypassed: "+SelectionReasonAssigned)
+	nsem.recordSelection(requestConnection, assigned, SelectionReasonAssigned)
+	return assigned, nil
+}
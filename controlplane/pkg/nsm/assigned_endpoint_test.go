@@ -0,0 +1,72 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestAssignedEndpoint_ReusedWithoutDiscovery(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection(historyConnectionID), withSelectionHistory(16))
+	data.nseManager.props.ReuseAssignedEndpoint = true
+	nse1 := data.endpoints[0]
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint).To(Equal(nse1))
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(0))
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+	g.Expect(data.nseManager.SelectionHistory(historyConnectionID)[0].Reason).To(Equal(SelectionReasonAssigned))
+}
+
+func TestAssignedEndpoint_CheckFailsFallsBackToDiscovery(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection(historyConnectionID), withSelectionHistory(16))
+	data.nseManager.props.ReuseAssignedEndpoint = true
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+	g.Expect(data.nseManager.SelectionHistory(historyConnectionID)[0].Reason).To(Equal(SelectionReasonPinned))
+}
+
+func TestAssignedEndpoint_OtherEndpointTargeted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection(historyConnectionID))
+	data.nseManager.props.ReuseAssignedEndpoint = true
+	requestConnection := newTargetedRequestConnection(nse1Name, remoteNSMName, withID(historyConnectionID))
+	requestConnection.NetworkServiceEndpointName = nse2Name
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
+
+func TestAssignedEndpoint_Ignored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection(historyConnectionID))
+	data.nseManager.props.ReuseAssignedEndpoint = true
+	nse1 := data.endpoints[0]
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withID(historyConnectionID)), data.ignores(nse1))
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
+
+func TestAssignedEndpoint_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection(historyConnectionID))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName, withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// CheckUpdateNSEBatch - checks NSE clients could be created for registrations concurrently, up to
+// properties.HealCheckConcurrency at once, each probe client is cleaned up. Returns errors keyed by endpoint NSM
+// name, nil for reachable endpoints. Endpoints not checked before ctx is done get ctx error. With
+// properties.HealCheckBudget probes share the budget instead of taking HealRequestConnectCheckTimeout each.
+func (nsem *nseManager) CheckUpdateNSEBatch(ctx context.Context, registrations []*registry.NSERegistration) map[registry.EndpointNSMName]error {
+	span := spanhelper.FromContext(ctx, "CheckUpdateNSEBatch")
+	defer span.Finish()
+	span.LogValue("endpoints", len(registrations))
+
+	check := nsem.CheckUpdateNSEWithError
+	if nsem.props.HealCheckBudget > 0 {
+		budget := newHealCheckBudget(nsem.props.HealCheckBudget, nsem.props.HealCheckMinTimeout, nsem.props.HealRequestConnectCheckTimeout,
+			nsem.props.HealCheckConcurrency, len(registrations))
+		check = func(ctx context.Context, registration *registry.NSERegistration) error {
+			return nsem.checkUpdateNSE(ctx, registration, budget.next())
+		}
+	}
+	result := make(map[registry.EndpointNSMName]error, len(registrations))
+	checkConcurrently(span.Context(), registrations, nsem.props.HealCheckConcurrency, check,
+		func(registration *registry.NSERegistration, err error) bool {
+			result[registration.GetEndpointNSMName()] = err
+			return false
+		})
+	for _, registration := range registrations {
+		if _, ok := result[registration.GetEndpointNSMName()]; !ok {
+			result[registration.GetEndpointNSMName()] = ctx.Err()
+		}
+	}
+	span.LogObject("results", result)
+	return result
+}
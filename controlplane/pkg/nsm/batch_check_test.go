@@ -0,0 +1,55 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func newBatchCheckEndpoints(data *nseManagerTestData, count int) []*registry.NSERegistration {
+	var registrations []*registry.NSERegistration
+	for i := 0; i < count; i++ {
+		registrations = append(registrations, data.createEndpoint(fmt.Sprintf("nse-%d", i), fmt.Sprintf("nsm-%d", i)))
+	}
+	return registrations
+}
+
+func TestCheckUpdateNSEBatch(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withUnreachableManagers("nsm-3"))
+	data.nseManager.props.HealCheckConcurrency = 3
+	data.serviceRegistry.dialDelay = 20 * time.Millisecond
+	registrations := newBatchCheckEndpoints(data, 10)
+
+	result := data.nseManager.CheckUpdateNSEBatch(context.Background(), registrations)
+	g.Expect(result).To(HaveLen(10))
+	for i, registration := range registrations {
+		if i == 3 {
+			g.Expect(result[registration.GetEndpointNSMName()]).NotTo(BeNil())
+			continue
+		}
+		g.Expect(result[registration.GetEndpointNSMName()]).To(BeNil())
+	}
+	g.Expect(data.serviceRegistry.maxActiveDials).To(BeNumerically("<=", 3))
+	g.Expect(data.serviceRegistry.maxActiveDials).To(BeNumerically(">", 1))
+}
+
+func TestCheckUpdateNSEBatch_Cancelled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.serviceRegistry.dialDelay = 20 * time.Millisecond
+	registrations := newBatchCheckEndpoints(data, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := data.nseManager.CheckUpdateNSEBatch(ctx, registrations)
+	g.Expect(result).To(HaveLen(3))
+	for _, err := range result {
+		g.Expect(err).To(Equal(context.Canceled))
+	}
+}
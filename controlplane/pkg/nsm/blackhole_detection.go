@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+)
+
+// DataPathProber - verifies traffic actually flows through connected endpoint, catching endpoints which accept
+// connections but drop traffic.
+type DataPathProber interface {
+	Probe(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) error
+}
+
+type noopDataPathProber struct{}
+
+func (noopDataPathProber) Probe(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) error {
+	return nil
+}
+
+// WithDataPathProber - probe data path of endpoints in CreateNegotiatedNSEClient, endpoints failed probe are
+// quarantined for properties.BlackholeQuarantine.
+func WithDataPathProber(prober DataPathProber) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.prober = prober
+	}
+}
+
+// endpointQuarantine - endpoints excluded from selection until deadline, keyed by endpoint identity,
+// zero value is ready to use.
+type endpointQuarantine struct {
+	sync.Mutex
+	until map[string]time.Time
+}
+
+func (q *endpointQuarantine) add(key string, timeout time.Duration) {
+	q.Lock()
+	defer q.Unlock()
+	if q.until == nil {
+		q.until = map[string]time.Time{}
+	}
+	q.until[key] = time.Now().Add(timeout)
+}
+
+func (q *endpointQuarantine) remove(key string) {
+	q.Lock()
+	defer q.Unlock()
+	delete(q.until, key)
+}
+
+func (q *endpointQuarantine) clear() {
+	q.Lock()
+	defer q.Unlock()
+	q.until = nil
+}
+
+func (q *endpointQuarantine) contains(key string) bool {
+	q.Lock()
+	defer q.Unlock()
+	until, ok := q.until[key]
+	if ok && time.Now().After(until) {
+		delete(q.until, key)
+		return false
+	}
+	return ok
+}
+
+// probeDataPath - probes data path of connected endpoint and quarantines it if probe fails.
+func (nsem *nseManager) probeDataPath(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) error {
+	err := nsem.prober.Probe(ctx, endpoint, client)
+	if err == nil {
+		return nil
+	}
+	logrus.Warnf("Endpoint %v failed data path probe, quarantining it for %v: %v", endpoint.GetEndpointNSMName(), nsem.props.BlackholeQuarantine, err)
+	if nsem.props.BlackholeQuarantine > 0 {
+		nsem.quarantine.add(nsem.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager()), nsem.props.BlackholeQuarantine)
+		nsem.observeReachability(endpoint, ReachabilityUnreachable)
+	}
+	return errors.Wrap(ErrDataPathProbeFailed, err.Error())
+}
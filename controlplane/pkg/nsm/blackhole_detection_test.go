@@ -0,0 +1,82 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+)
+
+type dataPathProberStub struct {
+	dead   map[string]bool
+	probes int
+}
+
+func (stub *dataPathProberStub) Probe(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) error {
+	stub.probes++
+	if stub.dead[endpoint.GetNetworkServiceEndpoint().GetName()] {
+		return errors.New("no reply")
+	}
+	return nil
+}
+
+func TestBlackholeDetection_DeadDataPathAvoided(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	prober := &dataPathProberStub{dead: map[string]bool{nse1Name: true}}
+	WithDataPathProber(prober)(data.nseManager)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	// Control plane of nse-1 is healthy, it accepts connection.
+	endpoint, client, err := data.nseManager.CreateNegotiatedNSEClient(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(client).NotTo(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+
+	// Quarantined endpoint is not selected again.
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestBlackholeDetection_NoQuarantine(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.BlackholeQuarantine = 0
+	WithDataPathProber(&dataPathProberStub{dead: map[string]bool{nse1Name: true}})(data.nseManager)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	_, _, err := data.nseManager.CreateNegotiatedNSEClient(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestBlackholeDetection_AttemptsAreBounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.CapabilityNegotiationAttempts = 2
+	prober := &dataPathProberStub{dead: map[string]bool{nse1Name: true, nse2Name: true, nse3Name: true}}
+	WithDataPathProber(prober)(data.nseManager)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	_, _, err := data.nseManager.CreateNegotiatedNSEClient(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrDataPathProbeFailed)).To(BeTrue())
+	g.Expect(prober.probes).To(Equal(2))
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// enrichCandidates - annotates endpoints with connection counts from model plus reserved slots, RTT from RTT store
+// (measured only if latency classes are configured) and load reports.
+func (nsem *nseManager) enrichCandidates(endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager) []*selector.Candidate {
+	result := make([]*selector.Candidate, 0, len(endpoints))
+	byKey := map[string]*selector.Candidate{}
+	for _, endpoint := range endpoints {
+		manager := managers[endpoint.GetNetworkServiceManagerName()]
+		candidate := &selector.Candidate{
+			Endpoint: endpoint,
+			Manager:  manager,
+		}
+		candidate.RTT, candidate.RTTMeasured = nsem.rttStore.load(registry.NewEndpointNSMName(endpoint, manager))
+		candidate.Load = nsem.loadReport(endpoint, manager)
+		key := nsem.identity.Key(endpoint, manager)
+		candidate.Connections = nsem.reservations.count(key)
+		byKey[key] = candidate
+		result = append(result, candidate)
+	}
+
+	for _, clientConnection := range nsem.model.GetAllClientConnections() {
+		registration := clientConnection.Endpoint
+		candidate := byKey[nsem.identity.Key(registration.GetNetworkServiceEndpoint(), registration.GetNetworkServiceManager())]
+		if candidate == nil {
+			continue
+		}
+		candidate.Connections++
+		if clientConnection.ConnectionState == model.ClientConnectionHealingBegin || clientConnection.ConnectionState == model.ClientConnectionHealing {
+			candidate.Healing++
+		}
+	}
+	return result
+}
+
+// scoreCandidates - scores candidates if selector is able to, preferring scoring with runtime state,
+// returns nil otherwise.
+func (nsem *nseManager) scoreCandidates(requestConnection *connection.Connection, ns *registry.NetworkService,
+	endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager) []float64 {
+	switch scorer := nsem.activeSelector(ns).(type) {
+	case selector.CandidateScorer:
+		return scorer.ScoreCandidates(requestConnection, ns, nsem.enrichCandidates(endpoints, managers))
+	case selector.Scorer:
+		return scorer.ScoreEndpoints(requestConnection, ns, endpoints)
+	default:
+		return nil
+	}
+}
+
+// selectCandidate - selects endpoint with selector, candidate selectors choose from endpoints with their
+// runtime state.
+func (nsem *nseManager) selectCandidate(endpointSelector selector.Selector, requestConnection *connection.Connection, ns *registry.NetworkService,
+	endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+	candidateSelector, ok := endpointSelector.(selector.CandidateSelector)
+	if !ok {
+		return endpointSelector.SelectEndpoint(requestConnection, ns, endpoints)
+	}
+	if candidate := candidateSelector.SelectCandidate(requestConnection, ns, nsem.enrichCandidates(endpoints, managers)); candidate != nil {
+		return candidate.Endpoint
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+func TestCandidateEnrichment(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withGoldLatencyClass)
+	scorer := &selectorStub{scoreCandidate: func(candidate *selector.Candidate) float64 {
+		return -float64(candidate.Connections)
+	}}
+	withSelector(scorer)(data)
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+	data.nseManager.ReportRTT(nse2.GetEndpointNSMName(), 5*time.Millisecond)
+
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+		ConnectionID:    "1",
+		Endpoint:        nse1,
+		ConnectionState: model.ClientConnectionReady,
+	})
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+		ConnectionID:    "2",
+		Endpoint:        nse1,
+		ConnectionState: model.ClientConnectionHealing,
+	})
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+		ConnectionID:    "3",
+		Endpoint:        nse2,
+		ConnectionState: model.ClientConnectionReady,
+	})
+
+	ctx := WithSelectionResult(context.Background(), &SelectionResult{})
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+
+	g.Expect(scorer.candidates).To(HaveLen(2))
+	g.Expect(scorer.candidates[0].Endpoint.GetName()).To(Equal(nse1Name))
+	g.Expect(scorer.candidates[0].Connections).To(Equal(2))
+	g.Expect(scorer.candidates[0].Healing).To(Equal(1))
+	g.Expect(scorer.candidates[0].RTTMeasured).To(BeFalse())
+	g.Expect(scorer.candidates[1].Endpoint.GetName()).To(Equal(nse2Name))
+	g.Expect(scorer.candidates[1].Connections).To(Equal(1))
+	g.Expect(scorer.candidates[1].Healing).To(Equal(0))
+	g.Expect(scorer.candidates[1].RTTMeasured).To(BeTrue())
+	g.Expect(scorer.candidates[1].RTT).To(Equal(5 * time.Millisecond))
+	g.Expect(scorer.candidates[1].Manager.GetName()).To(Equal(remoteNSMName))
+}
+
+func TestCandidateEnrichment_LexicographicSelector(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withGoldLatencyClass)
+	withSelector(selector.NewLexicographicSelector(selector.LeastConnectionsCriterion(), selector.LowestRTTCriterion()))(data)
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse3 := data.createEndpoint(nse3Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2, nse3)
+	data.nseManager.ReportRTT(nse2.GetEndpointNSMName(), 50*time.Millisecond)
+	data.nseManager.ReportRTT(nse3.GetEndpointNSMName(), 5*time.Millisecond)
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+		ConnectionID: "1",
+		Endpoint:     nse1,
+	})
+
+	result := &SelectionResult{}
+	endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+	g.Expect(result.Confidence).To(Equal(0.5))
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// candidateSelectable - status of discovered endpoint which survived filtering.
+const candidateSelectable = "candidate"
+
+// logCandidates - logs every discovered endpoint of request at debug level, with its manager, labels and whether it
+// is a candidate or why it was filtered out, for those scraping logs rather than traces. Nothing is done unless
+// debug logging is enabled.
+func (nsem *nseManager) logCandidates(ctx context.Context, requestConnection *connection.Connection, endpointResponse *registry.FindNetworkServiceResponse,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) {
+	if !logrus.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	report := RejectionReport{}
+	// Error only means all endpoints were filtered out, report tells why.
+	_, _ = nsem.filterEndpointsReported(nil, requestConnection, endpointResponse.GetNetworkServiceEndpoints(), endpointResponse.GetNetworkServiceManagers(), ignoreEndpoints, report)
+	for _, endpoint := range endpointResponse.GetNetworkServiceEndpoints() {
+		status, rejected := report[rejectionKey(endpoint)]
+		if !rejected {
+			status = candidateSelectable
+		}
+		logrus.WithFields(logrus.Fields{
+			"correlationId":  correlationIDFrom(ctx),
+			"networkService": requestConnection.GetNetworkService(),
+			"endpoint":       endpoint.GetName(),
+			"manager":        endpoint.GetNetworkServiceManagerName(),
+			"managerUrl":     endpointResponse.GetNetworkServiceManagers()[endpoint.GetNetworkServiceManagerName()].GetUrl(),
+			"labels":         endpoint.GetLabels(),
+			"status":         status,
+		}).Debug("Discovered endpoint")
+	}
+}
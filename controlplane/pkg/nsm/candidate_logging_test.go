@@ -0,0 +1,46 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+// loggedCandidates - runs GetEndpoint at log level, returns statuses of logged endpoints by name.
+func loggedCandidates(g *WithT, level logrus.Level) map[string]interface{} {
+	data := newNseManagerTestData()
+	ignored := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(ignored, data.createEndpoint(nse2Name, remoteNSMName))
+
+	hooks := logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+	defer logrus.StandardLogger().ReplaceHooks(hooks)
+	hook := test.NewGlobal()
+	defer logrus.SetLevel(logrus.GetLevel())
+	logrus.SetLevel(level)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(ignored))
+	g.Expect(err).To(BeNil())
+	statuses := map[string]interface{}{}
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Discovered endpoint" {
+			statuses[entry.Data["endpoint"].(string)] = entry.Data["status"]
+		}
+	}
+	return statuses
+}
+
+func TestLogCandidates_Debug(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(loggedCandidates(g, logrus.DebugLevel)).To(Equal(map[string]interface{}{
+		nse1Name: RejectedIgnored,
+		nse2Name: candidateSelectable,
+	}))
+}
+
+func TestLogCandidates_NotDebug(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(loggedCandidates(g, logrus.InfoLevel)).To(BeEmpty())
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// RequiredCapabilitiesLabel - request label with comma separated capabilities endpoint must support.
+const RequiredCapabilitiesLabel = "nsm/required-capabilities"
+
+// CapabilityNegotiator - queries connected endpoint for capabilities it actually supports,
+// which could diverge from the advertised ones.
+type CapabilityNegotiator interface {
+	Capabilities(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) ([]string, error)
+}
+
+// WithCapabilityNegotiator - negotiate capabilities with endpoints in CreateNegotiatedNSEClient.
+func WithCapabilityNegotiator(negotiator CapabilityNegotiator) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.capabilityNegotiator = negotiator
+	}
+}
+
+// CreateNegotiatedNSEClient - selects an endpoint, connects to it, negotiates capabilities required by request and
+// probes data path. If endpoint could not be connected to, does not satisfy them or data path probe fails, endpoint
+// is ignored for this request and another one is selected, up to properties.CapabilityNegotiationAttempts endpoints
+// are tried. Ignore map of caller is not modified.
+func (nsem *nseManager) CreateNegotiatedNSEClient(ctx context.Context, requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, nsm.NetworkServiceClient, error) {
+	span := spanhelper.FromContext(ctx, "CreateNegotiatedNSEClient")
+	defer span.Finish()
+
+	required := requiredCapabilities(requestConnection)
+	span.LogObject("requiredCapabilities", required)
+
+	budget := nsem.newSelectionBudget(span.Context(), requestConnection.GetNetworkService())
+	ignores := copyIgnores(ignoreEndpoints)
+	var lastErr error
+	for attempt := 0; attempt < nsem.props.CapabilityNegotiationAttempts; attempt++ {
+		endpoint, err := nsem.GetEndpoint(span.Context(), requestConnection, ignores)
+		if err != nil {
+			span.LogError(err)
+			return nil, nil, err
+		}
+		client, err := nsem.CreateNSEClient(span.Context(), endpoint)
+		if err != nil {
+			span.Logger().Warnf("Endpoint %v: %v", endpoint.GetEndpointNSMName(), err)
+			ignores[endpoint.GetEndpointNSMName()] = endpoint
+			lastErr = err
+			continue
+		}
+		err = budget.run(span.Context(), validationPhase, func(ctx context.Context) error {
+			return nsem.validateEndpoint(ctx, endpoint, client, required)
+		})
+		if err == nil {
+			return endpoint, client, nil
+		}
+		span.Logger().Warnf("Endpoint %v: %v", endpoint.GetEndpointNSMName(), err)
+		_ = client.Cleanup()
+		ignores[endpoint.GetEndpointNSMName()] = endpoint
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = ErrCapabilitiesNotSatisfied
+	}
+	err := errors.Wrapf(lastErr, "tried %d endpoints for %v", nsem.props.CapabilityNegotiationAttempts, required)
+	span.LogError(err)
+	return nil, nil, err
+}
+
+// validateEndpoint - checks connected endpoint supports required capabilities and passes data path probe.
+func (nsem *nseManager) validateEndpoint(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient, required []string) error {
+	if len(required) > 0 && nsem.capabilityNegotiator != nil {
+		if err := nsem.checkCapabilities(ctx, endpoint, client, required); err != nil {
+			return errors.Wrap(ErrCapabilitiesNotSatisfied, err.Error())
+		}
+	}
+	return nsem.probeDataPath(ctx, endpoint, client)
+}
+
+func (nsem *nseManager) checkCapabilities(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient, required []string) error {
+	capabilities, err := nsem.capabilityNegotiator.Capabilities(ctx, endpoint, client)
+	if err != nil {
+		return err
+	}
+	supported := map[string]bool{}
+	for _, capability := range capabilities {
+		supported[capability] = true
+	}
+	for _, capability := range required {
+		if !supported[capability] {
+			return errors.Errorf("capability %s is not supported", capability)
+		}
+	}
+	return nil
+}
+
+func requiredCapabilities(requestConnection *connection.Connection) []string {
+	var result []string
+	for _, capability := range strings.Split(requestConnection.GetLabels()[RequiredCapabilitiesLabel], ",") {
+		if capability = strings.TrimSpace(capability); capability != "" {
+			result = append(result, capability)
+		}
+	}
+	return result
+}
+
+func copyIgnores(ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) map[registry.EndpointNSMName]*registry.NSERegistration {
+	result := make(map[registry.EndpointNSMName]*registry.NSERegistration, len(ignoreEndpoints))
+	for name, endpoint := range ignoreEndpoints {
+		result[name] = endpoint
+	}
+	return result
+}
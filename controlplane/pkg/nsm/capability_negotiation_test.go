@@ -0,0 +1,72 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+)
+
+type capabilityNegotiatorStub struct {
+	capabilities map[string][]string
+}
+
+func (stub *capabilityNegotiatorStub) Capabilities(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) ([]string, error) {
+	return stub.capabilities[endpoint.GetNetworkServiceEndpoint().GetName()], nil
+}
+
+func TestCreateNegotiatedNSEClient_MismatchReselects(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	WithCapabilityNegotiator(&capabilityNegotiatorStub{
+		capabilities: map[string][]string{
+			nse1Name: {"ipv4"},
+			nse2Name: {"ipv4", "ipv6"},
+		},
+	})(data.nseManager)
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	ignores := data.ignores()
+	endpoint, client, err := data.nseManager.CreateNegotiatedNSEClient(context.Background(), newTestRequestConnection(withLabel(RequiredCapabilitiesLabel, "ipv4, ipv6")), ignores)
+	g.Expect(err).To(BeNil())
+	g.Expect(client).NotTo(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+	g.Expect(ignores).To(BeEmpty())
+}
+
+func TestCreateNegotiatedNSEClient_AttemptsAreBounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.CapabilityNegotiationAttempts = 2
+	WithCapabilityNegotiator(&capabilityNegotiatorStub{})(data.nseManager)
+
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	_, _, err := data.nseManager.CreateNegotiatedNSEClient(context.Background(), newTestRequestConnection(withLabel(RequiredCapabilitiesLabel, "ipv6")), nil)
+	g.Expect(errors.Is(err, ErrCapabilitiesNotSatisfied)).To(BeTrue())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+}
+
+func TestCreateNegotiatedNSEClient_ConnectFailureReselects(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withUnreachableManagers("nsm-1"), withSpreadEndpoints(nse1Name, nse2Name))
+
+	ignores := data.ignores()
+	endpoint, client, err := data.nseManager.CreateNegotiatedNSEClient(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(err).To(BeNil())
+	g.Expect(client).NotTo(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+	g.Expect(ignores).To(BeEmpty())
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
+)
+
+// chaosInjector - injects failures and delays when properties.ChaosEnabled is set, zero value is ready to use.
+// Failures are gRPC Unavailable errors, same as of unreachable registry or endpoint.
+type chaosInjector struct {
+	sync.Mutex
+	random *rand.Rand
+}
+
+func (c *chaosInjector) hit(props *properties.Properties, rate float64) bool {
+	if !props.ChaosEnabled || rate <= 0 {
+		return false
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.random == nil {
+		c.random = rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404 - chaos does not need secure random
+	}
+	return c.random.Float64() < rate
+}
+
+// fail - returns injected failure of operation with probability of rate.
+func (c *chaosInjector) fail(props *properties.Properties, rate float64, operation string) error {
+	if !c.hit(props, rate) {
+		return nil
+	}
+	logrus.Warnf("Chaos: injecting %s failure", operation)
+	return status.Errorf(codes.Unavailable, "chaos: injected %s failure", operation)
+}
+
+// delay - delays selection by properties.ChaosSelectionDelay with probability of properties.ChaosSelectionDelayRate.
+func (c *chaosInjector) delay(ctx context.Context, props *properties.Properties) error {
+	if props.DeterministicSelection || !c.hit(props, props.ChaosSelectionDelayRate) {
+		return nil
+	}
+	logrus.Warnf("Chaos: delaying selection by %v", props.ChaosSelectionDelay)
+	select {
+	case <-time.After(props.ChaosSelectionDelay):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
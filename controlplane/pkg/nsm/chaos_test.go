@@ -0,0 +1,85 @@
+package nsm
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const chaosTrials = 1000
+
+func withChaos(data *nseManagerTestData) {
+	data.nseManager.props.ChaosEnabled = true
+	data.nseManager.chaos.random = rand.New(rand.NewSource(1))
+}
+
+func countChaosFailures(g *WithT, call func() error) int {
+	failures := 0
+	for i := 0; i < chaosTrials; i++ {
+		if err := call(); err != nil {
+			g.Expect(status.Code(err)).To(Equal(codes.Unavailable))
+			failures++
+		}
+	}
+	return failures
+}
+
+func TestChaos_DiscoveryFailureRate(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withChaos, withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.ChaosDiscoveryFailureRate = 0.3
+
+	failures := countChaosFailures(g, func() error {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		return err
+	})
+	g.Expect(failures).To(BeNumerically("~", chaosTrials*0.3, chaosTrials*0.05))
+}
+
+func TestChaos_ClientFailureRate(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withChaos, withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.ChaosClientFailureRate = 0.5
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+
+	failures := countChaosFailures(g, func() error {
+		_, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+		return err
+	})
+	g.Expect(failures).To(BeNumerically("~", chaosTrials*0.5, chaosTrials*0.05))
+}
+
+func TestChaos_SelectionDelay(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withChaos, withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.ChaosSelectionDelayRate = 1
+	data.nseManager.props.ChaosSelectionDelay = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 50*time.Millisecond))
+}
+
+func TestChaos_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withChaos, withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.ChaosEnabled = false
+	data.nseManager.props.ChaosDiscoveryFailureRate = 1
+	data.nseManager.props.ChaosClientFailureRate = 1
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+
+	failures := countChaosFailures(g, func() error {
+		if _, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil); err != nil {
+			return err
+		}
+		_, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+		return err
+	})
+	g.Expect(failures).To(BeZero())
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"time"
+)
+
+// Clock - source of timeouts of heal checks and remote connects, replaced in tests to trigger timeouts without
+// waiting for them.
+type Clock interface {
+	// WithTimeout - same as context.WithTimeout.
+	WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc)
+}
+
+// realClock - default clock, uses context.WithTimeout.
+type realClock struct{}
+
+func (realClock) WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
+
+// WithClock - take timeouts from clock instead of real time.
+func WithClock(clock Clock) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.clock = clock
+	}
+}
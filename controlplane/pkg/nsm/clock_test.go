@@ -0,0 +1,114 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type fakeTimeoutContext struct {
+	context.Context
+	deadline time.Time
+	done     chan struct{}
+	once     sync.Once
+	mutex    sync.Mutex
+	err      error
+}
+
+func (ctx *fakeTimeoutContext) Deadline() (time.Time, bool) {
+	return ctx.deadline, true
+}
+
+func (ctx *fakeTimeoutContext) Done() <-chan struct{} {
+	return ctx.done
+}
+
+func (ctx *fakeTimeoutContext) Err() error {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+	return ctx.err
+}
+
+func (ctx *fakeTimeoutContext) cancel(err error) {
+	ctx.once.Do(func() {
+		ctx.mutex.Lock()
+		ctx.err = err
+		ctx.mutex.Unlock()
+		close(ctx.done)
+	})
+}
+
+// fakeClock - clock timeouts of which expire only when it is advanced.
+type fakeClock struct {
+	sync.Mutex
+	now    time.Time
+	timers []*fakeTimeoutContext
+}
+
+func (c *fakeClock) WithTimeout(parent context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	c.Lock()
+	defer c.Unlock()
+	ctx := &fakeTimeoutContext{Context: parent, deadline: c.now.Add(timeout), done: make(chan struct{})}
+	if timeout <= 0 {
+		ctx.cancel(context.DeadlineExceeded)
+	} else {
+		c.timers = append(c.timers, ctx)
+	}
+	go func() {
+		select {
+		case <-parent.Done():
+			ctx.cancel(parent.Err())
+		case <-ctx.done:
+		}
+	}()
+	return ctx, func() { ctx.cancel(context.Canceled) }
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	c.now = c.now.Add(d)
+	var pending []*fakeTimeoutContext
+	for _, timer := range c.timers {
+		if timer.deadline.After(c.now) {
+			pending = append(pending, timer)
+			continue
+		}
+		timer.cancel(context.DeadlineExceeded)
+	}
+	c.timers = pending
+}
+
+func (c *fakeClock) pending() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.timers)
+}
+
+func TestCheckUpdateNSE_GivesUpAtCheckTimeout(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withHangingDials)
+	clock := &fakeClock{}
+	defer close(data.serviceRegistry.hang)
+	data.nseManager.clock = clock
+	data.nseManager.props.HealCheckJitter = 0
+	checkTimeout := data.nseManager.props.HealRequestConnectCheckTimeout
+
+	result := make(chan error, 1)
+	go func() {
+		result <- data.nseManager.CheckUpdateNSEWithError(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	}()
+	// Ping timeout and connect timeout.
+	g.Eventually(clock.pending).Should(Equal(2))
+
+	clock.advance(checkTimeout - time.Nanosecond)
+	g.Expect(clock.pending()).To(Equal(2))
+	g.Consistently(result, 10*time.Millisecond).ShouldNot(Receive())
+
+	clock.advance(time.Nanosecond)
+	g.Expect(clock.pending()).To(Equal(1))
+	g.Eventually(result).Should(Receive(Equal(context.DeadlineExceeded)))
+}
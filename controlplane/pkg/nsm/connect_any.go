@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// ConnectToAnyEndpoint - selects an endpoint and connects to it, if connection fails, e.g. local endpoint is gone
+// while the network service is still served remotely, endpoint is ignored for this request and another one is
+// selected, up to properties.ConnectAttempts endpoints are tried. Manager of remote endpoint that could not be dialed
+// is ignored with all endpoints it hosts. Each attempt leaves properties.DialBudgetShare of time left until request
+// deadline for connecting. Callers should use it instead of retrying CreateNSEClient themselves, CreateNSEClient
+// fails with ErrLocalEndpointNotFound if local endpoint was deleted since it was selected. Error lists endpoints
+// tried with the reason each of them failed. Ignore map of caller is not modified.
+func (nsem *nseManager) ConnectToAnyEndpoint(ctx context.Context, requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, nsm.NetworkServiceClient, error) {
+	span := spanhelper.FromContext(ctx, "ConnectToAnyEndpoint")
+	defer span.Finish()
+
+	ignores := copyIgnores(ignoreEndpoints)
+	var failures []string
+	for attempt := 0; attempt < nsem.props.ConnectAttempts; attempt++ {
+		budget := nsem.newSelectionBudget(span.Context(), requestConnection.GetNetworkService())
+		selectCtx, cancel := budget.reserve(span.Context(), dialPhase)
+		endpoint, err := nsem.GetEndpoint(selectCtx, requestConnection, ignores)
+		cancel()
+		if err != nil {
+			if len(failures) == 0 {
+				span.LogError(err)
+				return nil, nil, err
+			}
+			failures = append(failures, err.Error())
+			break
+		}
+		var client nsm.NetworkServiceClient
+		err = budget.run(span.Context(), dialPhase, func(ctx context.Context) (err error) {
+			client, err = nsem.CreateNSEClient(ctx, endpoint)
+			return err
+		})
+		if err == nil {
+			span.LogValue("attempts", attempt+1)
+			return endpoint, client, nil
+		}
+		span.Logger().Warnf("Failed to connect to endpoint %v, selecting another one: %v", endpoint.GetEndpointNSMName(), err)
+		failures = append(failures, fmt.Sprintf("%s: %v", endpoint.GetEndpointNSMName(), err))
+		ignores[endpoint.GetEndpointNSMName()] = endpoint
+		if !nsem.IsLocalEndpoint(endpoint) && !errors.Is(err, ErrRemoteDialPreempted) {
+			// Remote client dials manager of endpoint, other endpoints hosted by it would fail the same way.
+			IgnoreManager(ignores, endpoint.GetNetworkServiceManager().GetName())
+		}
+	}
+	err := errors.Errorf("failed to connect to any endpoint of NetworkService %s: %s", requestConnection.GetNetworkService(), strings.Join(failures, "; "))
+	span.LogError(err)
+	return nil, nil, err
+}
@@ -0,0 +1,61 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+// withLocalScoredEndpoint - moves the most preferred of scored endpoints to the local manager.
+func withLocalScoredEndpoint(data *nseManagerTestData) {
+	data.endpoints[0] = data.createEndpoint(nse1Name, localNSMName)
+	data.setDiscoveredEndpoints(data.endpoints...)
+}
+
+func TestConnectToAnyEndpoint_LocalFailsRemoteConnects(t *testing.T) {
+	g := NewWithT(t)
+	// Local endpoint is discovered, but is not in model anymore.
+	data := newNseManagerTestData(withScoredEndpoints, withLocalScoredEndpoint)
+	ignores := data.ignores()
+
+	endpoint, client, err := data.nseManager.ConnectToAnyEndpoint(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(err).To(BeNil())
+	g.Expect(client).NotTo(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(ignores).To(BeEmpty())
+}
+
+func TestCreateNSEClient_LocalEndpointGone(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withLocalScoredEndpoint)
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, localNSMName))
+	g.Expect(errors.Is(err, ErrLocalEndpointNotFound)).To(BeTrue())
+	g.Expect(err.Error()).To(HavePrefix("Endpoint not found: "))
+}
+
+func TestConnectToAnyEndpoint_Exhausted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withLocalScoredEndpoint, withUnreachableManagers("nsm-2", "nsm-3"))
+	data.nseManager.props.ConnectAttempts = 5
+
+	_, _, err := data.nseManager.ConnectToAnyEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(HavePrefix("failed to connect to any endpoint of NetworkService " + networkServiceName + ": "))
+	for _, failure := range []string{"Endpoint not found", "nsm-2 is not reachable", "nsm-3 is not reachable", ErrCandidatesExhausted.Error()} {
+		g.Expect(err.Error()).To(ContainSubstring(failure))
+	}
+}
+
+func TestConnectToAnyEndpoint_Bounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withLocalScoredEndpoint, withUnreachableManagers("nsm-2", "nsm-3"))
+	data.nseManager.props.ConnectAttempts = 2
+
+	_, _, err := data.nseManager.ConnectToAnyEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).NotTo(ContainSubstring("nsm-3"))
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+}
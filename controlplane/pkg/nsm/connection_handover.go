@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+const (
+	// PreviousEndpointLabel - connection label with name of endpoint connection was routed to by NSMgr it is handed
+	// over from. Same endpoint is returned while it is discovered, so failover does not disrupt data plane.
+	PreviousEndpointLabel = "nsm/previous-endpoint"
+	// PreviousManagerLabel - connection label with name of NSM hosting previous endpoint, optional.
+	PreviousManagerLabel = "nsm/previous-nsm"
+)
+
+// endpointFromHint - re-resolves previous endpoint of connection among candidates, nil if connection has no hint or
+// endpoint is gone or filtered out.
+func (nsem *nseManager) endpointFromHint(span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	candidates func() []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	previousEndpoint := requestConnection.GetLabels()[PreviousEndpointLabel]
+	if previousEndpoint == "" {
+		return nil
+	}
+	previousManager := requestConnection.GetLabels()[PreviousManagerLabel]
+	endpoint := findEndpoint(candidates(), previousEndpoint, previousManager)
+	if endpoint == nil {
+		span.LogValue("previousEndpoint", "gone")
+		return nil
+	}
+	span.LogValue("previousEndpoint", "rehomed")
+	return endpoint
+}
+
+// findEndpoint - finds endpoint by name, hosted by manager if it is not empty.
+func findEndpoint(endpoints []*registry.NetworkServiceEndpoint, name, manager string) *registry.NetworkServiceEndpoint {
+	for _, candidate := range endpoints {
+		if candidate.GetName() == name && (manager == "" || candidate.GetNetworkServiceManagerName() == manager) {
+			return candidate
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestConnectionHandover_RehomedToSameEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(PreviousEndpointLabel, nse2Name), withLabel(PreviousManagerLabel, remoteNSMName)), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestConnectionHandover_MissingEndpointReselected(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(PreviousEndpointLabel, nse2Name), withLabel(PreviousManagerLabel, remoteNSMName)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+
+	// Same endpoint name on another NSM is another endpoint.
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(PreviousEndpointLabel, nse1Name), withLabel(PreviousManagerLabel, localNSMName)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+}
+
+func TestConnectionHandover_FilteredOutEndpointReselected(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(16))
+	data.nseManager.props.EndpointBlacklistCooldown = time.Hour
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName), nse2)
+	data.nseManager.blacklistEndpoint(nse2, errors.New("connection refused"))
+
+	request := newTestRequestConnection(withLabel(PreviousEndpointLabel, nse2Name), withLabel(PreviousManagerLabel, remoteNSMName))
+	request.Id = "handed-over"
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(data.nseManager.SelectionHistory("handed-over")[0].Reason).To(Equal(SelectionReasonSelected))
+}
+
+func TestConnectionHandover_ReusePathsFilterOnce(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createVersionedEndpoint(nse1Name, "latest"),
+		data.createVersionedEndpoint(nse2Name, "v3"))
+
+	hooks := logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+	defer logrus.StandardLogger().ReplaceHooks(hooks)
+	hook := test.NewGlobal()
+
+	requestConnection := newTestRequestConnection(withLabel(MinVersionLabel, "v2"))
+	requestConnection.Labels[PreferredEndpointLabel] = nse1Name
+	requestConnection.Labels[PreviousEndpointLabel] = nse1Name
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	// Malformed version is warned once by reuse paths and once by selection.
+	warned := 0
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warned++
+		}
+	}
+	g.Expect(warned).To(Equal(2))
+}
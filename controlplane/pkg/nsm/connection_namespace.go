@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+// TenantLabel - connection label with tenant of client, connection ids of different tenants never collide in
+// per-connection state of endpoint manager.
+const TenantLabel = "nsm/tenant"
+
+// ConnectionNamespace - returns namespace connection ids of request are unique within, "" for the global one.
+type ConnectionNamespace func(requestConnection *connection.Connection) string
+
+// tenantNamespace - default connection namespace, tenant of connection.
+func tenantNamespace(requestConnection *connection.Connection) string {
+	return requestConnection.GetLabels()[TenantLabel]
+}
+
+// WithConnectionNamespace - namespace connection ids with namespace instead of tenant label.
+func WithConnectionNamespace(namespace ConnectionNamespace) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.namespace = namespace
+	}
+}
+
+// namespacedID - returns connection id qualified with namespace, ids in the global namespace are kept as is.
+func namespacedID(namespace, connectionID string) string {
+	if namespace == "" || connectionID == "" {
+		return connectionID
+	}
+	return namespace + "/" + connectionID
+}
+
+// connectionKey - returns id of request connection qualified with its namespace, key of per-connection state.
+func (nsem *nseManager) connectionKey(requestConnection *connection.Connection) string {
+	return namespacedID(nsem.namespace(requestConnection), requestConnection.GetId())
+}
@@ -0,0 +1,66 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/networkservice"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
+)
+
+func TestConnectionNamespace_TenantsHistoriesIsolated(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(16))
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	data.setDiscoveredEndpoints(nse1)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID("1"), withLabel(TenantLabel, "tenant-a")), nil)
+	g.Expect(err).To(BeNil())
+	data.setDiscoveredEndpoints(nse2)
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID("1"), withLabel(TenantLabel, "tenant-b")), nil)
+	g.Expect(err).To(BeNil())
+
+	historyA := data.nseManager.NamespacedSelectionHistory("tenant-a", "1")
+	g.Expect(historyA).To(HaveLen(1))
+	g.Expect(historyA[0].Endpoint).To(Equal(nse1.GetEndpointNSMName()))
+	historyB := data.nseManager.NamespacedSelectionHistory("tenant-b", "1")
+	g.Expect(historyB).To(HaveLen(1))
+	g.Expect(historyB[0].Endpoint).To(Equal(nse2.GetEndpointNSMName()))
+	g.Expect(data.nseManager.SelectionHistory("1")).To(BeEmpty())
+
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+		ConnectionID: "1",
+		Request:      &networkservice.NetworkServiceRequest{Connection: newTestRequestConnection(withID("1"), withLabel(TenantLabel, "tenant-a"))},
+	})
+	data.model.DeleteClientConnection(context.Background(), "1")
+	g.Eventually(func() []SelectionRecord {
+		return data.nseManager.NamespacedSelectionHistory("tenant-a", "1")
+	}).Should(BeEmpty())
+	g.Expect(data.nseManager.NamespacedSelectionHistory("tenant-b", "1")).To(HaveLen(1))
+}
+
+func TestConnectionNamespace_Custom(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager = newNseManager(data.serviceRegistry, data.model, properties.NewNsmProperties(),
+		WithConnectionNamespace(func(requestConnection *connection.Connection) string {
+			return requestConnection.GetLabels()[connection.NamespaceKey]
+		}))
+	withSelectionHistory(16)(data)
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	request := newTestRequestConnection(withID("1"), withLabel(TenantLabel, "tenant-a"))
+	request.Labels[connection.NamespaceKey] = "ns-1"
+	g.Expect(data.nseManager.connectionKey(request)).To(Equal("ns-1/1"))
+	g.Expect(data.nseManager.clientIdentity(request)).To(Equal("ns-1/1"))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.NamespacedSelectionHistory("ns-1", "1")).To(HaveLen(1))
+	g.Expect(data.nseManager.NamespacedSelectionHistory("tenant-a", "1")).To(BeEmpty())
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// connectionRoutes - endpoint connections were routed to by namespaced connection id, route of connection is
+// dropped when connection or the endpoint it was routed to is deleted from model.
+type connectionRoutes struct {
+	model.ListenerImpl
+	sync.RWMutex
+	namespace ConnectionNamespace
+	routes    map[string]registry.EndpointNSMName
+}
+
+func newConnectionRoutes(m model.Model, namespace ConnectionNamespace) *connectionRoutes {
+	routes := &connectionRoutes{
+		namespace: namespace,
+		routes:    map[string]registry.EndpointNSMName{},
+	}
+	m.AddListener(routes)
+	return routes
+}
+
+func (r *connectionRoutes) route(connectionID string, endpoint *registry.NSERegistration) {
+	if connectionID == "" {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.routes[connectionID] = endpoint.GetEndpointNSMName()
+}
+
+// ClientConnectionDeleted - drops route of closed connection.
+func (r *connectionRoutes) ClientConnectionDeleted(ctx context.Context, clientConnection *model.ClientConnection) {
+	r.Lock()
+	defer r.Unlock()
+	delete(r.routes, namespacedID(r.namespace(clientConnection.Request.GetConnection()), clientConnection.GetID()))
+}
+
+// EndpointDeleted - drops routes of connections to deleted local endpoint.
+func (r *connectionRoutes) EndpointDeleted(_ context.Context, endpoint *model.Endpoint) {
+	name := endpoint.Endpoint.GetEndpointNSMName()
+	r.Lock()
+	defer r.Unlock()
+	for connectionID, routed := range r.routes {
+		if routed == name {
+			delete(r.routes, connectionID)
+		}
+	}
+}
+
+// ConnectionRoutes - returns snapshot of endpoints connections were routed to by GetEndpoint, by connection id
+// qualified with its namespace.
+func (nsem *nseManager) ConnectionRoutes() map[string]registry.EndpointNSMName {
+	nsem.routes.RLock()
+	defer nsem.routes.RUnlock()
+	snapshot := make(map[string]registry.EndpointNSMName, len(nsem.routes.routes))
+	for connectionID, endpoint := range nsem.routes.routes {
+		snapshot[connectionID] = endpoint
+	}
+	return snapshot
+}
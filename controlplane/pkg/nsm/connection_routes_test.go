@@ -0,0 +1,78 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func TestConnectionRoutes_RecordsLastEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.ConnectionRoutes()).To(Equal(map[string]registry.EndpointNSMName{
+		historyConnectionID: nse1.GetEndpointNSMName(),
+	}))
+
+	pinned := newTargetedRequestConnection(nse2Name, remoteNSMName, withID(historyConnectionID))
+	_, err = data.nseManager.GetEndpoint(context.Background(), pinned, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.ConnectionRoutes()).To(Equal(map[string]registry.EndpointNSMName{
+		historyConnectionID: nse2.GetEndpointNSMName(),
+	}))
+
+	// Connections without id are not routed.
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.ConnectionRoutes()).To(HaveLen(1))
+}
+
+func TestConnectionRoutes_SnapshotIsCopy(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	delete(data.nseManager.ConnectionRoutes(), historyConnectionID)
+	g.Expect(data.nseManager.ConnectionRoutes()).To(HaveKey(historyConnectionID))
+}
+
+func TestConnectionRoutes_DroppedOnClose(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: historyConnectionID})
+	data.model.DeleteClientConnection(context.Background(), historyConnectionID)
+	g.Eventually(data.nseManager.ConnectionRoutes).Should(BeEmpty())
+}
+
+func TestConnectionRoutes_DroppedOnEndpointDelete(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name))
+
+	first := newTestRequestConnection(withID(historyConnectionID))
+	_, err := data.nseManager.GetEndpoint(context.Background(), first, nil)
+	g.Expect(err).To(BeNil())
+	second := newTargetedRequestConnection(nse2Name, localNSMName, withID("2"))
+	_, err = data.nseManager.GetEndpoint(context.Background(), second, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.ConnectionRoutes()).To(HaveLen(2))
+
+	data.model.DeleteEndpoint(context.Background(), nse1Name)
+	g.Eventually(data.nseManager.ConnectionRoutes).Should(HaveLen(1))
+	g.Expect(data.nseManager.ConnectionRoutes()).To(HaveKey("2"))
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+const (
+	// CorrelationIDLabel - connection label with id correlating request across NSMgrs it passes.
+	CorrelationIDLabel = "nsm/correlation-id"
+	// CorrelationIDHeader - gRPC metadata key correlation id is sent to registry and remote NSMgrs with.
+	CorrelationIDHeader = "nsm-correlation-id"
+	correlationIDSize   = 16
+)
+
+func newCorrelationID() string {
+	id := make([]byte, correlationIDSize)
+	if _, err := rand.Read(id); err != nil {
+		logrus.Errorf("Failed to generate correlation id: %v", err)
+		return ""
+	}
+	return hex.EncodeToString(id)
+}
+
+func metadataValue(md metadata.MD, ok bool) string {
+	if values := md.Get(CorrelationIDHeader); ok && len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// correlationIDFrom - returns correlation id outgoing or incoming gRPC metadata of ctx carries, empty if none.
+func correlationIDFrom(ctx context.Context) string {
+	if id := metadataValue(metadata.FromOutgoingContext(ctx)); id != "" {
+		return id
+	}
+	return metadataValue(metadata.FromIncomingContext(ctx))
+}
+
+// withCorrelationID - returns ctx sending correlation id of request with outgoing gRPC calls. Id is taken from ctx,
+// from request connection label or generated, request connection is left intact.
+func withCorrelationID(ctx context.Context, requestConnection *connection.Connection) context.Context {
+	id := correlationIDFrom(ctx)
+	if id == "" {
+		id = requestConnection.GetLabels()[CorrelationIDLabel]
+	}
+	if id == "" {
+		id = newCorrelationID()
+	}
+	if id == "" {
+		return ctx
+	}
+	if id == metadataValue(metadata.FromOutgoingContext(ctx)) {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, CorrelationIDHeader, id)
+}
@@ -0,0 +1,92 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/networkservice"
+)
+
+// correlationIDs - returns correlation ids discovery calls were sent with.
+func (d *scriptedDiscovery) correlationIDs() []string {
+	var ids []string
+	for _, call := range d.received() {
+		ids = append(ids, call.correlationID)
+	}
+	return ids
+}
+
+func TestCorrelationID_FromIncomingMetadata(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery(discoveryStep{
+		response: data.createFindNetworkServiceResponse(data.createEndpoint(nse1Name, remoteNSMName)),
+	})
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CorrelationIDHeader, "incoming-id"))
+
+	requestConnection := newTestRequestConnection()
+	_, err := data.nseManager.GetEndpoint(ctx, requestConnection, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(discovery.correlationIDs()).To(Equal([]string{"incoming-id"}))
+	g.Expect(requestConnection.GetLabels()).To(BeEmpty())
+}
+
+func TestCorrelationID_FromLabel(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery(discoveryStep{
+		response: data.createFindNetworkServiceResponse(data.createEndpoint(nse1Name, remoteNSMName)),
+	})
+
+	requestConnection := newTestRequestConnection(withLabel(CorrelationIDLabel, "label-id"))
+	_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(discovery.correlationIDs()).To(Equal([]string{"label-id"}))
+}
+
+func TestCorrelationID_Generated(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery(discoveryStep{
+		response: data.createFindNetworkServiceResponse(data.createEndpoint(nse1Name, remoteNSMName)),
+	})
+
+	first, second := newTestRequestConnection(), newTestRequestConnection()
+	for _, requestConnection := range []*connection.Connection{first, second} {
+		_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Expect(discovery.correlationIDs()).To(HaveLen(2))
+	g.Expect(discovery.correlationIDs()[0]).NotTo(BeEmpty())
+	g.Expect(discovery.correlationIDs()[0]).NotTo(Equal(discovery.correlationIDs()[1]))
+	g.Expect(first.GetLabels()).To(BeEmpty())
+	g.Expect(second.GetLabels()).To(BeEmpty())
+}
+
+// correlatedClientStub - records correlation ids remote requests are sent with.
+type correlatedClientStub struct {
+	networkServiceClientStub
+	ids []string
+}
+
+func (stub *correlatedClientStub) Request(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*connection.Connection, error) {
+	md, _ := metadata.FromOutgoingContext(ctx)
+	stub.ids = append(stub.ids, md.Get(CorrelationIDHeader)...)
+	return in.GetConnection(), nil
+}
+
+func TestCorrelationID_RemoteRequest(t *testing.T) {
+	g := NewWithT(t)
+	remote := &correlatedClientStub{}
+	client := &nsmClient{client: remote}
+
+	requestConnection := newTestRequestConnection(withLabel(CorrelationIDLabel, "label-id"))
+	_, err := client.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: requestConnection})
+	g.Expect(err).To(BeNil())
+	g.Expect(remote.ids).To(Equal([]string{"label-id"}))
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// DataLocalityLabel - connection label with data identity, e.g. dataset id or pod uid of a workload with several
+// connections sharing state. Connections with the same hint in the same connection namespace, e.g. tenant, are routed
+// to the same endpoint of network service, where the data is cached.
+const DataLocalityLabel = "nsm/data-locality"
+
+type localityBinding struct {
+	key      string
+	endpoint string
+	manager  string
+	until    time.Time
+	// index - index of binding in expiry heap.
+	index int
+}
+
+// bindingsByExpiry - heap of bindings, the one expiring first on top.
+type bindingsByExpiry []*localityBinding
+
+func (h bindingsByExpiry) Len() int           { return len(h) }
+func (h bindingsByExpiry) Less(i, j int) bool { return h[i].until.Before(h[j].until) }
+func (h bindingsByExpiry) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *bindingsByExpiry) Push(x interface{}) {
+	binding := x.(*localityBinding)
+	binding.index = len(*h)
+	*h = append(*h, binding)
+}
+func (h *bindingsByExpiry) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// dataLocality - endpoints bound to data locality hints of network services, zero value is ready to use.
+type dataLocality struct {
+	sync.Mutex
+	bindings map[string]*localityBinding
+	expiry   bindingsByExpiry
+}
+
+func (l *dataLocality) get(key string, ttl time.Duration) (localityBinding, bool) {
+	l.Lock()
+	defer l.Unlock()
+	binding, ok := l.bindings[key]
+	if !ok {
+		return localityBinding{}, false
+	}
+	now := time.Now()
+	if now.After(binding.until) {
+		l.remove(binding)
+		return localityBinding{}, false
+	}
+	binding.until = now.Add(ttl)
+	heap.Fix(&l.expiry, binding.index)
+	return *binding, true
+}
+
+// bind - binds endpoint to key, if there are maxBindings bindings already, binding expiring first is dropped,
+// maxBindings 0 means no limit.
+func (l *dataLocality) bind(key string, endpoint *registry.NetworkServiceEndpoint, ttl time.Duration, maxBindings int) {
+	l.Lock()
+	defer l.Unlock()
+	now := time.Now()
+	if l.bindings == nil {
+		l.bindings = map[string]*localityBinding{}
+	}
+	for len(l.expiry) > 0 && now.After(l.expiry[0].until) {
+		l.remove(l.expiry[0])
+	}
+	binding, ok := l.bindings[key]
+	if !ok {
+		if maxBindings > 0 && len(l.bindings) >= maxBindings {
+			l.remove(l.expiry[0])
+		}
+		binding = &localityBinding{key: key}
+		l.bindings[key] = binding
+		heap.Push(&l.expiry, binding)
+	}
+	binding.endpoint = endpoint.GetName()
+	binding.manager = endpoint.GetNetworkServiceManagerName()
+	binding.until = now.Add(ttl)
+	heap.Fix(&l.expiry, binding.index)
+}
+
+func (l *dataLocality) remove(binding *localityBinding) {
+	heap.Remove(&l.expiry, binding.index)
+	delete(l.bindings, binding.key)
+}
+
+// localityKey - returns data locality hint of connection qualified with its network service and connection
+// namespace, "" if connection has no hint.
+func (nsem *nseManager) localityKey(requestConnection *connection.Connection) string {
+	hint := requestConnection.GetLabels()[DataLocalityLabel]
+	if hint == "" {
+		return ""
+	}
+	return namespacedID(nsem.namespace(requestConnection), requestConnection.GetNetworkService()+"|"+hint)
+}
+
+// endpointFromLocality - returns endpoint bound to data locality hint of connection, nil if connection has no hint,
+// hint is not bound yet or bound endpoint is gone or filtered out.
+func (nsem *nseManager) endpointFromLocality(span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	candidates func() []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	key := nsem.localityKey(requestConnection)
+	if key == "" || nsem.props.DataLocalityTTL <= 0 {
+		return nil
+	}
+	binding, ok := nsem.locality.get(key, nsem.props.DataLocalityTTL)
+	if !ok {
+		return nil
+	}
+	endpoint := findEndpoint(candidates(), binding.endpoint, binding.manager)
+	if endpoint == nil {
+		span.LogValue("dataLocality", "rebind")
+		return nil
+	}
+	span.LogValue("dataLocality", "bound")
+	return endpoint
+}
+
+// bindLocality - binds selected endpoint to data locality hint of connection.
+func (nsem *nseManager) bindLocality(requestConnection *connection.Connection, endpoint *registry.NetworkServiceEndpoint) {
+	if key := nsem.localityKey(requestConnection); key != "" && nsem.props.DataLocalityTTL > 0 {
+		nsem.locality.bind(key, endpoint, nsem.props.DataLocalityTTL, nsem.props.DataLocalityMaxBindings)
+	}
+}
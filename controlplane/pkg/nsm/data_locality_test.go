@@ -0,0 +1,133 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestDataLocality_SameHintSameEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.DataLocalityTTL = time.Minute
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	first, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1")), nil)
+	g.Expect(err).To(BeNil())
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1")), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(first.GetNetworkServiceEndpoint().GetName()))
+	}
+
+	other, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-2")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(other.GetNetworkServiceEndpoint().GetName()).NotTo(Equal(first.GetNetworkServiceEndpoint().GetName()))
+}
+
+func TestDataLocality_RebindsWhenEndpointIgnored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(16))
+	data.nseManager.props.DataLocalityTTL = time.Minute
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, data.createEndpoint(nse2Name, remoteNSMName))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1")), data.ignores(nse1))
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+
+	request := newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1"))
+	request.Id = "locality"
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	history := data.nseManager.SelectionHistory("locality")
+	g.Expect(history).To(HaveLen(1))
+	g.Expect(history[0].Reason).To(Equal(SelectionReasonLocality))
+}
+
+func TestDataLocality_Bounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.DataLocalityTTL = time.Minute
+	data.nseManager.props.DataLocalityMaxBindings = 2
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	for _, hint := range []string{"pod-1", "pod-2", "pod-3"} {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, hint)), nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Expect(data.nseManager.locality.bindings).To(HaveLen(2))
+	g.Expect(data.nseManager.locality.bindings).NotTo(HaveKey(networkServiceName + "|pod-1"))
+	g.Expect(data.nseManager.locality.bindings).To(HaveKey(networkServiceName + "|pod-3"))
+}
+
+func TestDataLocality_RebindsWhenEndpointFilteredOut(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.DataLocalityTTL = time.Minute
+	data.nseManager.props.EndpointBlacklistCooldown = time.Hour
+	nse2 := data.createEndpoint(nse2Name, "nsm-2")
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, "nsm-1"), nse2)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+
+	// Bound endpoint hosted by a manager request does not allow is not reused.
+	request := newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1"))
+	request.Labels[AllowedManagersLabel] = "nsm-2"
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+
+	// Hint is rebound to nse-2, which is not reused once blacklisted.
+	data.nseManager.blacklistEndpoint(nse2, errors.New("connection refused"))
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestDataLocality_HintsNamespaced(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.DataLocalityTTL = time.Minute
+
+	for _, tenant := range []string{"tenant-a", "tenant-b"} {
+		request := newTestRequestConnection(withLabel(DataLocalityLabel, "dataset-1"))
+		request.Labels[TenantLabel] = tenant
+		_, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Expect(data.nseManager.locality.bindings).To(HaveLen(2))
+	g.Expect(data.nseManager.locality.bindings).To(HaveKey("tenant-a/" + networkServiceName + "|dataset-1"))
+	g.Expect(data.nseManager.locality.bindings).To(HaveKey("tenant-b/" + networkServiceName + "|dataset-1"))
+}
+
+func TestDataLocality_ExpiredBindingsDropped(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.DataLocalityTTL = 10 * time.Millisecond
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "pod-1")), nil)
+	g.Expect(err).To(BeNil())
+	time.Sleep(20 * time.Millisecond)
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(DataLocalityLabel, "pod-2")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.locality.bindings).To(HaveLen(1))
+	g.Expect(data.nseManager.locality.expiry).To(HaveLen(1))
+	g.Expect(data.nseManager.locality.bindings).To(HaveKey(networkServiceName + "|pod-2"))
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sort"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// selectDeterministic - selects endpoint with deterministic model selector among endpoints ordered by identity,
+// so order of discovered endpoints does not matter. Candidate selectors choose from endpoints with runtime state.
+func (nsem *nseManager) selectDeterministic(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+	ordered := append([]*registry.NetworkServiceEndpoint(nil), endpoints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return nsem.identity.Key(ordered[i], managers[ordered[i].GetNetworkServiceManagerName()]) <
+			nsem.identity.Key(ordered[j], managers[ordered[j].GetNetworkServiceManagerName()])
+	})
+	endpointSelector := nsem.activeSelector(ns)
+	if _, ok := endpointSelector.(selector.CandidateSelector); ok {
+		return nsem.selectCandidate(endpointSelector, requestConnection, ns, ordered, managers)
+	}
+	return endpointSelector.(selector.DeterministicSelector).SelectDeterministic(requestConnection, ns, ordered)
+}
@@ -0,0 +1,61 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// withCapacityEndpoints - discovers endpoints with capacities growing in order of names.
+func withCapacityEndpoints(data *nseManagerTestData) {
+	withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name)(data)
+	for i, endpoint := range data.endpoints {
+		endpoint.NetworkServiceEndpoint.Labels = map[string]string{selector.CapacityLabel: fmt.Sprint(i + 1)}
+	}
+}
+
+func (data *nseManagerTestData) selectForID(g *WithT, id string) string {
+	request := newTestRequestConnection(withID(id))
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	return endpoint.GetNetworkServiceEndpoint().GetName()
+}
+
+func TestDeterministicSelection_SameInputsSameEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	for _, endpointSelector := range []selector.Selector{nil, selector.NewRoundRobinSelector(), selector.NewWeightedSelector()} {
+		options := []testDataOption{withCapacityEndpoints}
+		if endpointSelector != nil {
+			options = append(options, withSelector(endpointSelector))
+		}
+		data := newNseManagerTestData(options...)
+		data.nseManager.props.DeterministicSelection = true
+		endpoints := data.endpoints
+		selected := map[string]bool{}
+		for i := 0; i < 10; i++ {
+			id := fmt.Sprint(i)
+			data.setDiscoveredEndpoints(endpoints...)
+			expected := data.selectForID(g, id)
+			selected[expected] = true
+			for run := 0; run < 20; run++ {
+				data.setDiscoveredEndpoints(endpoints[run%3], endpoints[(run+1)%3], endpoints[(run+2)%3])
+				g.Expect(data.selectForID(g, id)).To(Equal(expected))
+			}
+		}
+		g.Expect(len(selected)).To(BeNumerically(">", 1))
+	}
+}
+
+func TestDeterministicSelection_NotDeterministicSelector(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withCapacityEndpoints, withSelector(&selectorStub{}))
+	data.nseManager.props.DeterministicSelection = true
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrSelectorNotDeterministic)).To(BeTrue())
+}
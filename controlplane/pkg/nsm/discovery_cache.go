@@ -0,0 +1,74 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type discoveryCacheEntry struct {
+	response *registry.FindNetworkServiceResponse
+	fetched  time.Time
+	until    time.Time
+}
+
+// discoveryCache - discovery responses keyed by network service name, zero value is ready to use.
+type discoveryCache struct {
+	sync.Mutex
+	entries map[string]discoveryCacheEntry
+}
+
+// get - returns cached response and when it was fetched from registry.
+func (c *discoveryCache) get(networkService string) (*registry.FindNetworkServiceResponse, time.Time, bool) {
+	c.Lock()
+	defer c.Unlock()
+	entry, ok := c.entries[networkService]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	if time.Now().After(entry.until) {
+		delete(c.entries, networkService)
+		return nil, time.Time{}, false
+	}
+	return entry.response, entry.fetched, true
+}
+
+// put - caches response fetched from registry for ttl, nothing is cached if ttl is not positive.
+func (c *discoveryCache) put(networkService string, response *registry.FindNetworkServiceResponse, fetched time.Time, ttl time.Duration) {
+	if ttl <= 0 {
+		return
+	}
+	c.Lock()
+	defer c.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]discoveryCacheEntry{}
+	}
+	c.entries[networkService] = discoveryCacheEntry{
+		response: response,
+		fetched:  fetched,
+		until:    fetched.Add(ttl),
+	}
+}
+
+func (c *discoveryCache) invalidate(networkService string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, networkService)
+}
@@ -0,0 +1,73 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func (data *nseManagerTestData) getEndpoints(count int) {
+	for i := 0; i < count; i++ {
+		_, _ = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	}
+}
+
+func TestDiscoveryCache_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+
+	data.getEndpoints(3)
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(3))
+}
+
+func TestDiscoveryCache_HitAndExpire(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.DiscoveryCacheTTL = 50 * time.Millisecond
+
+	data.getEndpoints(3)
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+
+	<-time.After(100 * time.Millisecond)
+	data.getEndpoints(1)
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(2))
+}
+
+func TestDiscoveryCache_InvalidatedOnConnectFailure(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.DiscoveryCacheTTL = time.Minute
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	data.getEndpoints(1)
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+
+	_, err = data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).NotTo(BeNil())
+	data.getEndpoints(1)
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(2))
+}
+
+func TestDiscoveryCache_KeepsFetchTime(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.DiscoveryCacheTTL = time.Minute
+
+	before := time.Now()
+	first := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), first), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(first.DiscoveredAt).To(BeTemporally(">=", before))
+	g.Expect(first.DiscoveredAt).To(BeTemporally("<=", time.Now()))
+
+	<-time.After(10 * time.Millisecond)
+	cached := &SelectionResult{}
+	_, err = data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), cached), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(cached.DiscoveredAt).To(Equal(first.DiscoveredAt))
+}
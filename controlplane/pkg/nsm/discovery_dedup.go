@@ -0,0 +1,111 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+type discoveryFlight struct {
+	done     chan struct{}
+	response *registry.FindNetworkServiceResponse
+	fetched  time.Time
+	err      error
+}
+
+// discoveryFlights - discovery lookups in flight keyed by network service and ignore set, zero value is ready to use.
+type discoveryFlights struct {
+	sync.Mutex
+	flights map[string]*discoveryFlight
+}
+
+// join - returns flight of key and true if caller started it and has to finish it.
+func (f *discoveryFlights) join(key string) (*discoveryFlight, bool) {
+	f.Lock()
+	defer f.Unlock()
+	if flight, ok := f.flights[key]; ok {
+		return flight, false
+	}
+	if f.flights == nil {
+		f.flights = map[string]*discoveryFlight{}
+	}
+	flight := &discoveryFlight{done: make(chan struct{})}
+	f.flights[key] = flight
+	return flight, true
+}
+
+func (f *discoveryFlights) finish(key string, flight *discoveryFlight) {
+	f.Lock()
+	defer f.Unlock()
+	if f.flights[key] == flight {
+		delete(f.flights, key)
+	}
+	close(flight.done)
+}
+
+// discoveryFlightKey - identifies discovery lookups GetEndpoint calls may share, calls ignoring different endpoints
+// never share one.
+func discoveryFlightKey(networkService string, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) string {
+	names := make([]string, 0, len(ignoreEndpoints))
+	for name := range ignoreEndpoints {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return networkService + "|" + strings.Join(names, ",")
+}
+
+// findNetworkServiceShared - findNetworkServiceFetched sharing one lookup between concurrent GetEndpoint calls of
+// network service with the same ignores, see properties.DeduplicateDiscovery. Each caller selects on shared
+// response on its own. Caller whose lookup failed only because ctx of the call it shared was done looks up again.
+func (nsem *nseManager) findNetworkServiceShared(ctx context.Context, span spanhelper.SpanHelper, networkService string,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.FindNetworkServiceResponse, time.Time, error) {
+	if !nsem.props.DeduplicateDiscovery {
+		return nsem.findNetworkServiceFetched(ctx, span, networkService)
+	}
+	key := discoveryFlightKey(networkService, ignoreEndpoints)
+	for {
+		flight, leader := nsem.discoveryFlights.join(key)
+		if leader {
+			flight.response, flight.fetched, flight.err = nsem.findNetworkServiceFetched(ctx, span, networkService)
+			nsem.discoveryFlights.finish(key, flight)
+			return flight.response, flight.fetched, flight.err
+		}
+		span.LogValue("discoveryShared", true)
+		select {
+		case <-flight.done:
+		case <-ctx.Done():
+			return nil, time.Time{}, errors.Wrapf(ctx.Err(), "waiting for shared discovery of NetworkService %s", networkService)
+		}
+		if isContextError(flight.err) && ctx.Err() == nil {
+			continue
+		}
+		return flight.response, flight.fetched, flight.err
+	}
+}
+
+func isContextError(err error) bool {
+	return errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)
+}
@@ -0,0 +1,85 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// withHeldDiscovery - discovery answers with endpoints discovered so far once proceed is closed, each call is
+// signalled on started.
+func (data *nseManagerTestData) withHeldDiscovery() (started chan struct{}, proceed chan struct{}) {
+	started, proceed = make(chan struct{}, 10), make(chan struct{})
+	discovery := data.withScriptedDiscovery(discoveryStep{response: data.serviceRegistry.discoveryClient.response})
+	discovery.block = heldUntil(started, proceed)
+	return started, proceed
+}
+
+func (data *nseManagerTestData) getEndpointAsync(ignores map[registry.EndpointNSMName]*registry.NSERegistration) chan *registry.NSERegistration {
+	result := make(chan *registry.NSERegistration, 1)
+	go func() {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+		if err != nil {
+			close(result)
+			return
+		}
+		result <- endpoint
+	}()
+	return result
+}
+
+func TestDiscoveryDedup_ConcurrentCallsShareLookup(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.DeduplicateDiscovery = true
+	started, proceed := data.withHeldDiscovery()
+
+	results := []chan *registry.NSERegistration{data.getEndpointAsync(data.ignores())}
+	<-started
+	for i := 0; i < 4; i++ {
+		results = append(results, data.getEndpointAsync(data.ignores()))
+	}
+	g.Consistently(started, 50*time.Millisecond).ShouldNot(Receive())
+	close(proceed)
+
+	for _, result := range results {
+		g.Eventually(result).Should(Receive(Not(BeNil())))
+	}
+	g.Expect(started).To(BeEmpty())
+}
+
+func TestDiscoveryDedup_DifferentIgnoresDoNotShare(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.DeduplicateDiscovery = true
+	started, proceed := data.withHeldDiscovery()
+	ignores := data.ignores(data.createEndpoint(nse1Name, remoteNSMName))
+
+	first := data.getEndpointAsync(data.ignores())
+	<-started
+	second := data.getEndpointAsync(ignores)
+	g.Eventually(started).Should(Receive())
+	close(proceed)
+
+	g.Eventually(first).Should(Receive(Not(BeNil())))
+	var endpoint *registry.NSERegistration
+	g.Eventually(second).Should(Receive(&endpoint))
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+}
+
+func TestDiscoveryDedupKey(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	g.Expect(discoveryFlightKey(networkServiceName, data.ignores(nse1, nse2))).To(
+		Equal(discoveryFlightKey(networkServiceName, data.ignores(nse2, nse1))))
+	g.Expect(discoveryFlightKey(networkServiceName, data.ignores(nse1))).NotTo(
+		Equal(discoveryFlightKey(networkServiceName, data.ignores(nse2))))
+	g.Expect(discoveryFlightKey(networkServiceName, nil)).To(Equal(discoveryFlightKey(networkServiceName, data.ignores())))
+}
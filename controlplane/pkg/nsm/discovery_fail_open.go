@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// localDiscoveryResponse - returns discovery response made of endpoints of network service registered at local NSM
+// to select from instead of failing with discoveryErr, nil if properties.DiscoveryFailOpen is not set, request is
+// cancelled or there are no such endpoints.
+func (nsem *nseManager) localDiscoveryResponse(ctx context.Context, span spanhelper.SpanHelper, networkService string, discoveryErr error) *registry.FindNetworkServiceResponse {
+	if !nsem.props.DiscoveryFailOpen || ctx.Err() != nil {
+		return nil
+	}
+	localEndpoints := nsem.localEndpointsOf(networkService)
+	if len(localEndpoints) == 0 {
+		return nil
+	}
+	response := &registry.FindNetworkServiceResponse{
+		NetworkServiceManagers: map[string]*registry.NetworkServiceManager{},
+	}
+	for _, endpoint := range localEndpoints {
+		registration := endpoint.Endpoint
+		if response.NetworkService == nil {
+			response.NetworkService = registration.GetNetworkService()
+		}
+		response.NetworkServiceEndpoints = append(response.NetworkServiceEndpoints, registration.GetNetworkServiceEndpoint())
+		response.NetworkServiceManagers[registration.GetNetworkServiceEndpoint().GetNetworkServiceManagerName()] = registration.GetNetworkServiceManager()
+	}
+	span.Logger().Warnf("Discovery of NetworkService %s failed: %v, selecting among %d local endpoints", networkService, discoveryErr, len(localEndpoints))
+	span.LogValue("discoveryFailOpen", len(localEndpoints))
+	return response
+}
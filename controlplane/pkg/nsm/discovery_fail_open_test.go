@@ -0,0 +1,47 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func withFailingDiscovery(data *nseManagerTestData) {
+	data.serviceRegistry.discoveryClient.error = errors.New("registry is unreachable")
+}
+
+func TestDiscoveryFailOpen_SelectsLocalEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailingDiscovery, withLocalEndpoints(nse1Name))
+	data.nseManager.props.DiscoveryFailOpen = true
+
+	result := &SelectionResult{}
+	endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(data.nseManager.IsLocalEndpoint(endpoint)).To(BeTrue())
+	g.Expect(result.LocalOnly).To(BeTrue())
+
+	// Local endpoints are filtered as discovered ones.
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(endpoint))
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+}
+
+func TestDiscoveryFailOpen_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailingDiscovery, withLocalEndpoints(nse1Name))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(Equal(data.serviceRegistry.discoveryClient.error))
+}
+
+func TestDiscoveryFailOpen_NoLocalEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailingDiscovery)
+	data.nseManager.props.DiscoveryFailOpen = true
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(Equal(data.serviceRegistry.discoveryClient.error))
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// discoveryGeneration - returns stable hash of identities of discovered endpoints, registry does not version
+// its responses.
+func (nsem *nseManager) discoveryGeneration(endpointResponse *registry.FindNetworkServiceResponse) string {
+	keys := make([]string, 0, len(endpointResponse.GetNetworkServiceEndpoints()))
+	for _, endpoint := range endpointResponse.GetNetworkServiceEndpoints() {
+		manager := endpointResponse.GetNetworkServiceManagers()[endpoint.GetNetworkServiceManagerName()]
+		keys = append(keys, nsem.identity.Key(endpoint, manager))
+	}
+	sort.Strings(keys)
+
+	hash := fnv.New64a()
+	for _, key := range keys {
+		_, _ = hash.Write([]byte(key))
+		_, _ = hash.Write([]byte{0})
+	}
+	return strconv.FormatUint(hash.Sum64(), 16)
+}
@@ -0,0 +1,43 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func (data *nseManagerTestData) getGeneration(g *WithT) string {
+	result := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Generation).NotTo(BeEmpty())
+	return result.Generation
+}
+
+func TestDiscoveryGeneration_StableForSameEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	data.setDiscoveredEndpoints(nse1, nse2)
+	generation := data.getGeneration(g)
+	g.Expect(data.getGeneration(g)).To(Equal(generation))
+
+	data.setDiscoveredEndpoints(nse2, nse1)
+	g.Expect(data.getGeneration(g)).To(Equal(generation))
+}
+
+func TestDiscoveryGeneration_ChangesWhenEndpointAdded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	data.setDiscoveredEndpoints(nse1, nse2)
+	generation := data.getGeneration(g)
+
+	data.setDiscoveredEndpoints(nse1, nse2, data.createEndpoint(nse3Name, remoteNSMName))
+	g.Expect(data.getGeneration(g)).NotTo(Equal(generation))
+}
@@ -0,0 +1,221 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
+)
+
+// discoveryStep - canned result of one FindNetworkService call.
+type discoveryStep struct {
+	response *registry.FindNetworkServiceResponse
+	err      error
+}
+
+// discoveryCall - FindNetworkService call received by scripted discovery.
+type discoveryCall struct {
+	service       string
+	correlationID string
+	deadline      time.Time
+	hasDeadline   bool
+}
+
+// scriptedDiscovery - discovery client answering FindNetworkService calls for network service with its steps in order,
+// the last step is repeated once steps run out. Services with no steps of their own are answered with the default
+// steps, or are not found if there are none. Each call is passed to block first, if it is set, which could hold it
+// and fail it with an error.
+type scriptedDiscovery struct {
+	sync.Mutex
+	steps    []discoveryStep
+	services map[string][]discoveryStep
+	block    func(ctx context.Context) error
+	calls    []discoveryCall
+}
+
+func (d *scriptedDiscovery) DiscoveryClient(ctx context.Context) (registry.NetworkServiceDiscoveryClient, error) {
+	return d, nil
+}
+
+func (d *scriptedDiscovery) FindNetworkService(ctx context.Context, in *registry.FindNetworkServiceRequest, opts ...grpc.CallOption) (*registry.FindNetworkServiceResponse, error) {
+	call := discoveryCall{service: in.GetNetworkServiceName()}
+	call.deadline, call.hasDeadline = ctx.Deadline()
+	md, _ := metadata.FromOutgoingContext(ctx)
+	if ids := md.Get(CorrelationIDHeader); len(ids) > 0 {
+		call.correlationID = ids[0]
+	}
+	d.Lock()
+	d.calls = append(d.calls, call)
+	step, ok := d.next(call.service)
+	block := d.block
+	d.Unlock()
+
+	if block != nil {
+		if err := block(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if !ok {
+		return nil, errors.Errorf("network service %s is not found", call.service)
+	}
+	return step.response, step.err
+}
+
+func (d *scriptedDiscovery) next(service string) (discoveryStep, bool) {
+	steps, own := d.services[service]
+	if !own {
+		steps = d.steps
+	}
+	if len(steps) == 0 {
+		return discoveryStep{}, false
+	}
+	if len(steps) > 1 {
+		if own {
+			d.services[service] = steps[1:]
+		} else {
+			d.steps = steps[1:]
+		}
+	}
+	return steps[0], true
+}
+
+// script - answers calls for service with steps instead of the default ones.
+func (d *scriptedDiscovery) script(service string, steps ...discoveryStep) {
+	d.Lock()
+	defer d.Unlock()
+	if d.services == nil {
+		d.services = map[string][]discoveryStep{}
+	}
+	d.services[service] = steps
+}
+
+// received - returns calls received so far.
+func (d *scriptedDiscovery) received() []discoveryCall {
+	d.Lock()
+	defer d.Unlock()
+	return append([]discoveryCall(nil), d.calls...)
+}
+
+// requests - returns network services of calls received so far.
+func (d *scriptedDiscovery) requests() []string {
+	var services []string
+	for _, call := range d.received() {
+		services = append(services, call.service)
+	}
+	return services
+}
+
+// withScriptedDiscovery - discovers endpoints with scripted discovery answering calls with steps by default.
+func (data *nseManagerTestData) withScriptedDiscovery(steps ...discoveryStep) *scriptedDiscovery {
+	discovery := &scriptedDiscovery{steps: steps}
+	WithDiscoveryClientProvider(discovery)(data.nseManager)
+	return discovery
+}
+
+// untilDone - block holding discovery calls until their context is done.
+func untilDone(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// heldUntil - returns block signalling each discovery call on started and holding it until proceed is closed.
+func heldUntil(started chan<- struct{}, proceed <-chan struct{}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		started <- struct{}{}
+		<-proceed
+		return nil
+	}
+}
+
+func TestDiscoveryProvider_Scripted(t *testing.T) {
+	unavailable := status.Error(codes.Unavailable, "registry is restarting")
+	notFound := status.Error(codes.NotFound, "network service is not found")
+
+	for _, test := range []struct {
+		name     string
+		steps    func(data *nseManagerTestData) []discoveryStep
+		endpoint string
+		err      error
+		calls    int
+	}{
+		{
+			name: "found",
+			steps: func(data *nseManagerTestData) []discoveryStep {
+				return []discoveryStep{{response: data.createFindNetworkServiceResponse(data.createEndpoint(nse1Name, remoteNSMName))}}
+			},
+			endpoint: nse1Name,
+			calls:    1,
+		},
+		{
+			name: "transient errors are retried",
+			steps: func(data *nseManagerTestData) []discoveryStep {
+				return []discoveryStep{{err: unavailable}, {err: unavailable},
+					{response: data.createFindNetworkServiceResponse(data.createEndpoint(nse2Name, remoteNSMName))}}
+			},
+			endpoint: nse2Name,
+			calls:    3,
+		},
+		{
+			name: "permanent error is not retried",
+			steps: func(data *nseManagerTestData) []discoveryStep {
+				return []discoveryStep{{err: notFound}}
+			},
+			err:   notFound,
+			calls: 1,
+		},
+		{
+			name: "no endpoints",
+			steps: func(data *nseManagerTestData) []discoveryStep {
+				return []discoveryStep{{response: data.createFindNetworkServiceResponse()}}
+			},
+			err:   ErrNoEndpointFound,
+			calls: 1,
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			data := newNseManagerTestData()
+			data.serviceRegistry.error = errors.New("service registry should not be used")
+			data.nseManager.props.DiscoveryRetryCount = 3
+			data.nseManager.props.DiscoveryRetryDelay = time.Millisecond
+			discovery := data.withScriptedDiscovery(test.steps(data)...)
+
+			endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+			if test.err != nil {
+				g.Expect(errors.Is(err, test.err)).To(BeTrue(), "%v", err)
+			} else {
+				g.Expect(err).To(BeNil())
+				g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(test.endpoint))
+			}
+			g.Expect(discovery.requests()).To(HaveLen(test.calls))
+		})
+	}
+}
+
+func TestDiscoveryProvider_FallbackService(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	props := properties.NewNsmProperties()
+	props.FallbackNetworkService = "fallback"
+	data.nseManager.props = props
+	fallback := data.createEndpoint(nse2Name, remoteNSMName)
+	fallback.NetworkService.Name = "fallback"
+	discovery := data.withScriptedDiscovery(
+		discoveryStep{response: data.createFindNetworkServiceResponse()},
+		discoveryStep{response: data.createFindNetworkServiceResponse(fallback)})
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(discovery.requests()).To(Equal([]string{networkServiceName, "fallback"}))
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// isTransientDiscoveryError - tells if discovery failed with error worth retrying.
+func isTransientDiscoveryError(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// findWithRetry - finds network service, retrying transient errors up to properties.DiscoveryRetryCount times with
+// exponential backoff while ctx is not done.
+func (nsem *nseManager) findWithRetry(ctx context.Context, span spanhelper.SpanHelper, discoveryClient registry.NetworkServiceDiscoveryClient,
+	nseRequest *registry.FindNetworkServiceRequest) (*registry.FindNetworkServiceResponse, error) {
+	delay := nsem.props.DiscoveryRetryDelay
+	for attempt := 1; ; attempt++ {
+		endpointResponse, err := nsem.findOnce(ctx, discoveryClient, nseRequest)
+		if err == nil || attempt > nsem.props.DiscoveryRetryCount || !isTransientDiscoveryError(err) || ctx.Err() != nil {
+			return endpointResponse, err
+		}
+		span.Logger().Warnf("Discovery attempt %d of %s failed, retrying in %v: %v", attempt, nseRequest.GetNetworkServiceName(), delay, err)
+		span.LogValue("discoveryRetry", attempt)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// findOnce - makes single discovery request bounded by properties.DiscoveryRequestTimeout, so slow registry
+// could not hold request with long deadline. Shorter deadline of ctx is kept.
+func (nsem *nseManager) findOnce(ctx context.Context, discoveryClient registry.NetworkServiceDiscoveryClient,
+	nseRequest *registry.FindNetworkServiceRequest) (*registry.FindNetworkServiceResponse, error) {
+	if nsem.props.DiscoveryRequestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, nsem.props.DiscoveryRequestTimeout)
+		defer cancel()
+	}
+	return discoveryClient.FindNetworkService(ctx, nseRequest)
+}
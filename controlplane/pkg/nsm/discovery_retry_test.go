@@ -0,0 +1,102 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// withFlakyDiscovery - retries discovery, first failures of which fail with code.
+func (data *nseManagerTestData) withFlakyDiscovery(failures int, code codes.Code) *scriptedDiscovery {
+	data.nseManager.props.DiscoveryRetryCount = 3
+	data.nseManager.props.DiscoveryRetryDelay = 10 * time.Millisecond
+	var steps []discoveryStep
+	for i := 0; i < failures; i++ {
+		steps = append(steps, discoveryStep{err: status.Error(code, "discovery failed")})
+	}
+	return data.withScriptedDiscovery(append(steps, discoveryStep{response: data.serviceRegistry.discoveryClient.response})...)
+}
+
+func TestDiscoveryRetry_Transient(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+	discovery := data.withFlakyDiscovery(2, codes.Unavailable)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(discovery.requests()).To(HaveLen(3))
+}
+
+func TestDiscoveryRetry_Exhausted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+	discovery := data.withFlakyDiscovery(10, codes.DeadlineExceeded)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(status.Code(err)).To(Equal(codes.DeadlineExceeded))
+	g.Expect(discovery.requests()).To(HaveLen(4))
+}
+
+func TestDiscoveryRetry_NotRetryable(t *testing.T) {
+	g := NewWithT(t)
+	for _, code := range []codes.Code{codes.NotFound, codes.InvalidArgument} {
+		data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+		discovery := data.withFlakyDiscovery(1, code)
+
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(status.Code(err)).To(Equal(code))
+		g.Expect(discovery.requests()).To(HaveLen(1))
+	}
+}
+
+func TestDiscoveryRetry_ContextCancelled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+	discovery := data.withFlakyDiscovery(10, codes.Unavailable)
+	data.nseManager.props.DiscoveryRetryDelay = time.Second
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	g.Expect(discovery.requests()).To(HaveLen(1))
+}
+
+func TestDiscoveryRequestTimeout(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+	data.nseManager.props.DiscoveryRequestTimeout = 100 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).To(Equal(context.DeadlineExceeded))
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	g.Expect(discovery.received()[0].deadline.Sub(start)).To(BeNumerically("~", 100*time.Millisecond, 50*time.Millisecond))
+}
+
+func TestDiscoveryRequestTimeout_ShorterRequestDeadline(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+	data.nseManager.props.DiscoveryRequestTimeout = time.Hour
+	data.nseManager.props.DiscoveryBudgetShare = 0
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(discovery.received()[0].deadline).To(Equal(deadline))
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// DrainNotifier - notified once when local endpoint starts draining, with ids of client connections routed to it, so
+// clients could be asked to reconnect gracefully. Endpoints drain while they are upgrading, see
+// EndpointUpgradingLabel, or drained with DrainEndpoint.
+type DrainNotifier interface {
+	EndpointDraining(endpoint *registry.NetworkServiceEndpoint, connectionIDs []string)
+}
+
+// WithDrainNotifier - notify about local endpoints which start draining, as observed by model updates.
+func WithDrainNotifier(notifier DrainNotifier) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.drainNotifier = notifier
+	}
+}
+
+// drainTracker - identities of local endpoints observed draining. Endpoint updates in model are observed, endpoint
+// is forgotten once it is deleted from model.
+type drainTracker struct {
+	model.ListenerImpl
+	sync.Mutex
+	nsem     *nseManager
+	draining map[string]bool
+}
+
+func newDrainTracker(nsem *nseManager) *drainTracker {
+	tracker := &drainTracker{
+		nsem:     nsem,
+		draining: map[string]bool{},
+	}
+	nsem.model.AddListener(tracker)
+	return tracker
+}
+
+// EndpointAdded - observes whether added local endpoint is draining.
+func (t *drainTracker) EndpointAdded(_ context.Context, endpoint *model.Endpoint) {
+	t.observe(endpoint)
+}
+
+// EndpointUpdated - observes whether updated local endpoint is draining.
+func (t *drainTracker) EndpointUpdated(_ context.Context, endpoint *model.Endpoint) {
+	t.observe(endpoint)
+}
+
+// EndpointDeleted - forgets deleted local endpoint.
+func (t *drainTracker) EndpointDeleted(_ context.Context, endpoint *model.Endpoint) {
+	registration := endpoint.Endpoint
+	t.transition(t.nsem.identity.Key(registration.GetNetworkServiceEndpoint(), registration.GetNetworkServiceManager()), false)
+}
+
+func (t *drainTracker) observe(endpoint *model.Endpoint) {
+	registration := endpoint.Endpoint
+	nse := registration.GetNetworkServiceEndpoint()
+	key := t.nsem.identity.Key(nse, registration.GetNetworkServiceManager())
+	t.nsem.observeDraining(nse, registration.GetNetworkServiceManager(), isEndpointUpgrading(nse) || t.nsem.drained.contains(key))
+}
+
+// transition - records whether endpoint is draining, returns true if it was not draining before.
+func (t *drainTracker) transition(key string, draining bool) bool {
+	t.Lock()
+	defer t.Unlock()
+	if !draining {
+		delete(t.draining, key)
+		return false
+	}
+	if t.draining[key] {
+		return false
+	}
+	t.draining[key] = true
+	return true
+}
+
+// observeDraining - notifies drain notifier when endpoint transitions to draining.
+func (nsem *nseManager) observeDraining(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager, draining bool) {
+	if nsem.drainNotifier == nil {
+		return
+	}
+	key := nsem.identity.Key(endpoint, manager)
+	if !nsem.draining.transition(key, draining) {
+		return
+	}
+	connectionIDs := []string{}
+	for _, clientConnection := range nsem.model.GetAllClientConnections() {
+		registration := clientConnection.Endpoint
+		if nsem.identity.Key(registration.GetNetworkServiceEndpoint(), registration.GetNetworkServiceManager()) == key {
+			connectionIDs = append(connectionIDs, clientConnection.GetID())
+		}
+	}
+	go nsem.drainNotifier.EndpointDraining(endpoint, connectionIDs)
+}
@@ -0,0 +1,71 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+type drainNotification struct {
+	endpoint      string
+	connectionIDs []string
+}
+
+type drainNotifierStub chan drainNotification
+
+func (stub drainNotifierStub) EndpointDraining(endpoint *registry.NetworkServiceEndpoint, connectionIDs []string) {
+	stub <- drainNotification{endpoint: endpoint.GetName(), connectionIDs: connectionIDs}
+}
+
+func TestDrainNotification_OncePerTransition(t *testing.T) {
+	g := NewWithT(t)
+	notifications := make(drainNotifierStub, 10)
+	data := newNseManagerTestData(withManagerOptions(WithDrainNotifier(notifications)), withLocalEndpoints(nse1Name, nse2Name))
+	nse1, nse2 := data.endpoints[0], data.endpoints[1]
+	for _, id := range []string{"1", "2"} {
+		data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: id, Endpoint: nse1})
+	}
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: "3", Endpoint: nse2})
+	g.Consistently(notifications, 50*time.Millisecond).ShouldNot(Receive())
+
+	setUpgrading := func(upgrading bool) {
+		registration := proto.Clone(nse1).(*registry.NSERegistration)
+		registration.NetworkServiceEndpoint.Labels = nil
+		if upgrading {
+			registration.NetworkServiceEndpoint.Labels = map[string]string{EndpointUpgradingLabel: "true"}
+		}
+		data.model.UpdateEndpoint(context.Background(), &model.Endpoint{Endpoint: registration})
+	}
+
+	setUpgrading(true)
+	var notification drainNotification
+	g.Eventually(notifications).Should(Receive(&notification))
+	g.Expect(notification.endpoint).To(Equal(nse1Name))
+	g.Expect(notification.connectionIDs).To(ConsistOf("1", "2"))
+
+	setUpgrading(true)
+	g.Consistently(notifications, 50*time.Millisecond).ShouldNot(Receive())
+
+	setUpgrading(false)
+	g.Consistently(notifications, 50*time.Millisecond).ShouldNot(Receive())
+	setUpgrading(true)
+	g.Eventually(notifications).Should(Receive(&notification))
+	g.Expect(notification.endpoint).To(Equal(nse1Name))
+}
+
+func TestDrainNotification_NotObservedBySelection(t *testing.T) {
+	g := NewWithT(t)
+	notifications := make(drainNotifierStub, 10)
+	data := newNseManagerTestData(withManagerOptions(WithDrainNotifier(notifications)), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: "1", Endpoint: data.endpoints[0]})
+
+	data.endpoints[0].NetworkServiceEndpoint.Labels = map[string]string{EndpointUpgradingLabel: "true"}
+	data.getEndpoints(2)
+	g.Consistently(notifications, 50*time.Millisecond).ShouldNot(Receive())
+}
@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// ClearEndpointBlacklist - makes all blacklisted endpoints selectable again.
+func (nsem *nseManager) ClearEndpointBlacklist() {
+	nsem.blacklist.clear()
+}
+
+// blacklistEndpoint - excludes endpoint client could not be created for from selection for
+// properties.EndpointBlacklistCooldown.
+func (nsem *nseManager) blacklistEndpoint(endpoint *registry.NSERegistration, err error) {
+	if nsem.props.EndpointBlacklistCooldown <= 0 {
+		return
+	}
+	logrus.Warnf("Failed to connect to endpoint %v, blacklisting it for %v: %v", endpoint.GetEndpointNSMName(), nsem.props.EndpointBlacklistCooldown, err)
+	nsem.blacklist.add(nsem.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager()), nsem.props.EndpointBlacklistCooldown)
+}
@@ -0,0 +1,79 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestEndpointBlacklist(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.EndpointBlacklistCooldown = time.Hour
+	failed := data.createEndpoint(nse1Name, remoteNSMName)
+
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+	_, err := data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).NotTo(BeNil())
+	data.serviceRegistry.remoteClientError = nil
+
+	g.Expect(data.selectedNames(3)).To(Equal([]string{nse2Name, nse2Name, nse2Name}))
+
+	data.nseManager.ClearEndpointBlacklist()
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestEndpointBlacklist_Expires(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.EndpointBlacklistCooldown = 50 * time.Millisecond
+
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(err).NotTo(BeNil())
+
+	g.Expect(data.selectedNames(2)).NotTo(ContainElement(nse1Name))
+	<-time.After(100 * time.Millisecond)
+	g.Expect(data.selectedNames(2)).To(ContainElement(nse1Name))
+}
+
+func TestEndpointBlacklist_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.EndpointBlacklistCooldown = 0
+
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(err).NotTo(BeNil())
+
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestEndpointBlacklist_Concurrent(t *testing.T) {
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.nseManager.props.EndpointBlacklistCooldown = time.Hour
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+	key := data.nseManager.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager())
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			data.nseManager.blacklistEndpoint(endpoint, errors.New("connection refused"))
+		}()
+		go func() {
+			defer wg.Done()
+			data.nseManager.blacklist.contains(key)
+		}()
+		go func() {
+			defer wg.Done()
+			data.nseManager.ClearEndpointBlacklist()
+		}()
+	}
+	wg.Wait()
+}
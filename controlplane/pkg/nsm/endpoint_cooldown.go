@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// EndpointCooldownLabel - endpoint label with duration, e.g. 500ms, endpoint is not selected for after it was
+// selected, overrides properties.SelectionCooldown.
+const EndpointCooldownLabel = "nsm/cooldown"
+
+// endpointCooldowns - ends of cooldowns of recently selected endpoints keyed by endpoint identity, zero value is
+// ready to use.
+type endpointCooldowns struct {
+	sync.Mutex
+	until map[string]time.Time
+}
+
+func (c *endpointCooldowns) start(key string, cooldown time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	if c.until == nil {
+		c.until = map[string]time.Time{}
+	}
+	c.until[key] = time.Now().Add(cooldown)
+}
+
+// cooling - returns end of endpoint cooldown if it is cooling down, expired cooldowns are forgotten.
+func (c *endpointCooldowns) cooling(key string) (time.Time, bool) {
+	c.Lock()
+	defer c.Unlock()
+	until, ok := c.until[key]
+	if !ok {
+		return time.Time{}, false
+	}
+	if !time.Now().Before(until) {
+		delete(c.until, key)
+		return time.Time{}, false
+	}
+	return until, true
+}
+
+// endpointCooldown - returns cooldown of endpoint from its label, defaultCooldown if there is no valid one.
+func endpointCooldown(endpoint *registry.NetworkServiceEndpoint, defaultCooldown time.Duration) time.Duration {
+	value, ok := endpoint.GetLabels()[EndpointCooldownLabel]
+	if !ok {
+		return defaultCooldown
+	}
+	cooldown, err := time.ParseDuration(value)
+	if err != nil {
+		logrus.Warnf("Endpoint %s has malformed %s label %q, ignoring it", endpoint.GetName(), EndpointCooldownLabel, value)
+		return defaultCooldown
+	}
+	return cooldown
+}
+
+// startCooldown - starts cooldown of just selected endpoint.
+func (nsem *nseManager) startCooldown(endpoint *registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager) {
+	if cooldown := endpointCooldown(endpoint, nsem.props.SelectionCooldown); cooldown > 0 {
+		nsem.cooldowns.start(nsem.identity.Key(endpoint, managers[endpoint.GetNetworkServiceManagerName()]), cooldown)
+	}
+}
+
+// filterCooldown - drops endpoints cooling down after they were selected, if all of them are only the one whose
+// cooldown ends first is kept.
+func (nsem *nseManager) filterCooldown(endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) []*registry.NetworkServiceEndpoint {
+	result := []*registry.NetworkServiceEndpoint{}
+	var soonest *registry.NetworkServiceEndpoint
+	var soonestUntil time.Time
+	for _, candidate := range endpoints {
+		until, cooling := nsem.cooldowns.cooling(nsem.identity.Key(candidate, managers[candidate.GetNetworkServiceManagerName()]))
+		if !cooling {
+			result = append(result, candidate)
+			continue
+		}
+		if soonest == nil || until.Before(soonestUntil) {
+			soonest, soonestUntil = candidate, until
+		}
+	}
+	if len(result) == 0 && soonest != nil {
+		return []*registry.NetworkServiceEndpoint{soonest}
+	}
+	return result
+}
@@ -0,0 +1,67 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSelectionCooldown_SkipsCoolingEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.SelectionCooldown = time.Hour
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	selected := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(selected).NotTo(HaveKey(endpoint.GetNetworkServiceEndpoint().GetName()))
+		selected[endpoint.GetNetworkServiceEndpoint().GetName()] = true
+	}
+}
+
+func TestSelectionCooldown_AllCoolingSelectsSoonestEnding(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.SelectionCooldown = time.Hour
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse2.NetworkServiceEndpoint.Labels = map[string]string{EndpointCooldownLabel: "1m"}
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	for i := 0; i < 2; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+	}
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestSelectionCooldown_Expires(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{EndpointCooldownLabel: "20ms"}
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse2.NetworkServiceEndpoint.Labels = map[string]string{EndpointCooldownLabel: "1h"}
+	data.setDiscoveredEndpoints(nse1, nse2)
+	data.nseManager.startCooldown(nse1.GetNetworkServiceEndpoint(), nil)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+
+	time.Sleep(50 * time.Millisecond)
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
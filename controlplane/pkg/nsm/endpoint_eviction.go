@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// EvictEndpoints - removes endpoints from model, e.g. all endpoints of dead NSM, the ones with fewest connections
+// first. Evictions are paced by properties.EvictionDelay so connections of evicted endpoints are re-homed
+// gradually instead of all at once stampeding the surviving endpoints. Returns ctx error if it is done before all
+// endpoints are evicted.
+func (nsem *nseManager) EvictEndpoints(ctx context.Context, endpoints []*model.Endpoint) error {
+	span := spanhelper.FromContext(ctx, "EvictEndpoints")
+	defer span.Finish()
+
+	ordered := nsem.evictionOrder(endpoints)
+	for i, endpoint := range ordered {
+		if i > 0 && nsem.props.EvictionDelay > 0 {
+			select {
+			case <-time.After(nsem.props.EvictionDelay):
+			case <-ctx.Done():
+				span.LogError(ctx.Err())
+				return ctx.Err()
+			}
+		}
+		span.LogValue("evicted", endpoint.EndpointName())
+		nsem.cleanupNSE(span.Context(), endpoint)
+	}
+	return nil
+}
+
+// evictionOrder - orders endpoints by count of client connections routed to them ascending, then by name.
+func (nsem *nseManager) evictionOrder(endpoints []*model.Endpoint) []*model.Endpoint {
+	connections := map[string]int{}
+	for _, clientConnection := range nsem.model.GetAllClientConnections() {
+		connections[clientConnection.Endpoint.GetNetworkServiceEndpoint().GetName()]++
+	}
+	ordered := append([]*model.Endpoint(nil), endpoints...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		left, right := connections[ordered[i].EndpointName()], connections[ordered[j].EndpointName()]
+		if left != right {
+			return left < right
+		}
+		return ordered[i].EndpointName() < ordered[j].EndpointName()
+	})
+	return ordered
+}
@@ -0,0 +1,92 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+type evictionRecordingModel struct {
+	model.Model
+	evicted []string
+	times   []time.Time
+}
+
+func (m *evictionRecordingModel) DeleteEndpoint(ctx context.Context, name string) {
+	m.evicted = append(m.evicted, name)
+	m.times = append(m.times, time.Now())
+	m.Model.DeleteEndpoint(ctx, name)
+}
+
+func withEvictionRecording(data *nseManagerTestData) {
+	data.nseManager.model = &evictionRecordingModel{Model: data.model}
+}
+
+// withConnections - adds client connections to discovered endpoints, given count per endpoint name.
+func withConnections(connections map[string]int) testDataOption {
+	return func(data *nseManagerTestData) {
+		for _, endpoint := range data.endpoints {
+			name := endpoint.GetNetworkServiceEndpoint().GetName()
+			for i := 0; i < connections[name]; i++ {
+				data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+					ConnectionID: fmt.Sprintf("%s-%d", name, i),
+					Endpoint:     endpoint,
+				})
+			}
+		}
+	}
+}
+
+func (data *nseManagerTestData) modelEndpoints() []*model.Endpoint {
+	var endpoints []*model.Endpoint
+	for _, endpoint := range data.endpoints {
+		endpoints = append(endpoints, &model.Endpoint{Endpoint: endpoint})
+	}
+	return endpoints
+}
+
+func TestEvictEndpoints_FewestConnectionsFirst(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEvictionRecording, withLocalEndpoints(nse1Name, nse2Name, nse3Name), withConnections(map[string]int{nse1Name: 2, nse3Name: 1}))
+	recording := data.nseManager.model.(*evictionRecordingModel)
+	endpoints := data.modelEndpoints()
+
+	g.Expect(data.nseManager.EvictEndpoints(context.Background(), endpoints)).To(BeNil())
+	g.Expect(recording.evicted).To(Equal([]string{nse2Name, nse3Name, nse1Name}))
+	for _, name := range recording.evicted {
+		g.Expect(data.model.GetEndpoint(name)).To(BeNil())
+	}
+}
+
+func TestEvictEndpoints_Paced(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEvictionRecording, withLocalEndpoints(nse1Name, nse2Name, nse3Name))
+	recording := data.nseManager.model.(*evictionRecordingModel)
+	endpoints := data.modelEndpoints()
+	data.nseManager.props.EvictionDelay = 50 * time.Millisecond
+
+	g.Expect(data.nseManager.EvictEndpoints(context.Background(), endpoints)).To(BeNil())
+	g.Expect(recording.evicted).To(Equal([]string{nse1Name, nse2Name, nse3Name}))
+	for i := 1; i < len(recording.times); i++ {
+		g.Expect(recording.times[i].Sub(recording.times[i-1])).To(BeNumerically(">=", 50*time.Millisecond))
+	}
+}
+
+func TestEvictEndpoints_Cancelled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEvictionRecording, withLocalEndpoints(nse1Name, nse2Name, nse3Name))
+	recording := data.nseManager.model.(*evictionRecordingModel)
+	endpoints := data.modelEndpoints()
+	data.nseManager.props.EvictionDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	g.Expect(data.nseManager.EvictEndpoints(ctx, endpoints)).To(Equal(context.DeadlineExceeded))
+	g.Expect(recording.evicted).To(Equal([]string{nse1Name}))
+	g.Expect(data.model.GetEndpoint(nse2Name)).NotTo(BeNil())
+}
@@ -0,0 +1,113 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type dialOutcome struct {
+	at     time.Time
+	failed bool
+}
+
+// endpointFailureRates - recent outcomes of creating NSE clients keyed by endpoint identity, zero value is ready
+// to use.
+type endpointFailureRates struct {
+	sync.Mutex
+	outcomes map[string][]dialOutcome
+}
+
+func (r *endpointFailureRates) add(key string, failed bool) {
+	r.Lock()
+	defer r.Unlock()
+	if r.outcomes == nil {
+		r.outcomes = map[string][]dialOutcome{}
+	}
+	r.outcomes[key] = append(r.outcomes[key], dialOutcome{at: time.Now(), failed: failed})
+}
+
+// rate - returns failed and total count of outcomes within window, older ones are forgotten.
+func (r *endpointFailureRates) rate(key string, window time.Duration) (failed, total int) {
+	r.Lock()
+	defer r.Unlock()
+	outcomes := r.outcomes[key]
+	deadline := time.Now().Add(-window)
+	i := 0
+	for i < len(outcomes) && !outcomes[i].at.After(deadline) {
+		i++
+	}
+	if i == len(outcomes) {
+		delete(r.outcomes, key)
+		return 0, 0
+	}
+	outcomes = outcomes[i:]
+	r.outcomes[key] = outcomes
+	for _, outcome := range outcomes {
+		if outcome.failed {
+			failed++
+		}
+	}
+	return failed, len(outcomes)
+}
+
+// recordClientOutcome - records whether NSE client to endpoint was created, if failure rate tracking is enabled.
+func (nsem *nseManager) recordClientOutcome(endpoint *registry.NSERegistration, err error) {
+	if nsem.props.FailureRateThreshold <= 0 {
+		return
+	}
+	nsem.failureRates.add(nsem.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager()), err != nil)
+}
+
+// filterFailureRate - drops endpoints failing more than properties.FailureRateThreshold of client creations within
+// properties.FailureRateWindow, endpoints with fewer than properties.FailureRateMinSamples outcomes are kept. If all
+// endpoints fail too often only the ones with the lowest failure rate are kept.
+func (nsem *nseManager) filterFailureRate(endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) []*registry.NetworkServiceEndpoint {
+	if nsem.props.FailureRateThreshold <= 0 {
+		return endpoints
+	}
+	result := []*registry.NetworkServiceEndpoint{}
+	var lowest []*registry.NetworkServiceEndpoint
+	lowestRate := 0.0
+	for _, candidate := range endpoints {
+		key := nsem.identity.Key(candidate, managers[candidate.GetNetworkServiceManagerName()])
+		failed, total := nsem.failureRates.rate(key, nsem.props.FailureRateWindow)
+		if total == 0 || total < nsem.props.FailureRateMinSamples {
+			result = append(result, candidate)
+			continue
+		}
+		rate := float64(failed) / float64(total)
+		if rate <= nsem.props.FailureRateThreshold {
+			result = append(result, candidate)
+			continue
+		}
+		switch {
+		case lowest == nil || rate < lowestRate:
+			lowest, lowestRate = []*registry.NetworkServiceEndpoint{candidate}, rate
+		case rate == lowestRate:
+			lowest = append(lowest, candidate)
+		}
+	}
+	if len(result) == 0 && lowest != nil {
+		return lowest
+	}
+	return result
+}
@@ -0,0 +1,108 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func withFailureRate(data *nseManagerTestData) {
+	data.nseManager.props.FailureRateThreshold = 0.5
+	data.nseManager.props.FailureRateWindow = 100 * time.Millisecond
+	data.nseManager.props.FailureRateMinSamples = 2
+}
+
+func (data *nseManagerTestData) recordOutcomes(endpoint *registry.NSERegistration, outcomes ...bool) {
+	for _, failed := range outcomes {
+		var err error
+		if failed {
+			err = errors.New("connection refused")
+		}
+		data.nseManager.recordClientOutcome(endpoint, err)
+	}
+}
+
+func TestFailureRate_ExcludedThenRecovers(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailureRate, withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	nse1 := data.endpoints[0]
+
+	data.recordOutcomes(nse1, true, false)
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+
+	data.recordOutcomes(nse1, true)
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse2Name, nse2Name}))
+
+	<-time.After(200 * time.Millisecond)
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestFailureRate_SuccessesLowerRate(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailureRate, withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	nse1 := data.endpoints[0]
+
+	data.recordOutcomes(nse1, true, true)
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse2Name, nse2Name}))
+
+	data.recordOutcomes(nse1, false, false)
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestFailureRate_MinSamples(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailureRate, withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	nse1 := data.endpoints[0]
+	data.nseManager.props.FailureRateMinSamples = 3
+
+	data.recordOutcomes(nse1, true, true)
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+
+	data.recordOutcomes(nse1, true)
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse2Name, nse2Name}))
+}
+
+func TestFailureRate_AllFailingKeepsLowest(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailureRate, withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	nse1, nse2 := data.endpoints[0], data.endpoints[1]
+
+	data.recordOutcomes(nse1, true, true, true)
+	data.recordOutcomes(nse2, true, true, false)
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse2Name, nse2Name}))
+}
+
+func TestFailureRate_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailureRate, withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	nse1 := data.endpoints[0]
+	data.nseManager.props.FailureRateThreshold = 0
+
+	data.recordOutcomes(nse1, true, true, true)
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestFailureRate_CreateNSEClientOutcomes(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailureRate, withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+	for i := 0; i < 2; i++ {
+		_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+		g.Expect(err).NotTo(BeNil())
+	}
+	data.serviceRegistry.remoteClientError = nil
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse2Name, nse2Name}))
+
+	for i := 0; i < 2; i++ {
+		client, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+		g.Expect(err).To(BeNil())
+		g.Expect(client.Cleanup()).To(Succeed())
+	}
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
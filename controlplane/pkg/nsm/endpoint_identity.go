@@ -0,0 +1,86 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// EndpointIdentity - identifies endpoints hosted by network service managers, endpoints with the same key are the
+// same endpoint for ignore matching and deduplication of discovered endpoints.
+type EndpointIdentity interface {
+	Key(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) string
+}
+
+// endpointNSMNameIdentity - default identity, endpoint name and manager URL, same as registry.EndpointNSMName.
+type endpointNSMNameIdentity struct{}
+
+func (endpointNSMNameIdentity) Key(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) string {
+	return endpoint.GetName() + ":" + manager.GetUrl()
+}
+
+// DeduplicationStrategy - tells which discovered endpoints are the same endpoint, e.g. registered under different
+// names, only the first discovered of endpoints with the same key is a candidate for selection.
+type DeduplicationStrategy interface {
+	DedupKey(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) string
+}
+
+// WithDeduplicationStrategy - deduplicate discovered endpoints with strategy instead of endpoint identity.
+func WithDeduplicationStrategy(strategy DeduplicationStrategy) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.dedup = strategy
+	}
+}
+
+// dedupKey - returns key discovered endpoints are deduplicated by, endpoint identity unless strategy is set.
+func (nsem *nseManager) dedupKey(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) string {
+	if nsem.dedup != nil {
+		return nsem.dedup.DedupKey(endpoint, manager)
+	}
+	return nsem.identity.Key(endpoint, manager)
+}
+
+// WithEndpointIdentity - identify endpoints with identity instead of endpoint name and manager URL.
+func WithEndpointIdentity(identity EndpointIdentity) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.identity = identity
+	}
+}
+
+// isIgnored - tells if endpoint hosted by manager has identity of any of ignored endpoints or its manager is ignored.
+func (nsem *nseManager) isIgnored(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) bool {
+	if isManagerIgnored(endpoint, ignoreEndpoints) {
+		return true
+	}
+	key := nsem.identity.Key(endpoint, manager)
+	for name, ignored := range ignoreEndpoints {
+		if isIgnoredManagerEntry(ignored) {
+			continue
+		}
+		if ignored == nil {
+			if string(name) == key {
+				return true
+			}
+			continue
+		}
+		if nsem.identity.Key(ignored.GetNetworkServiceEndpoint(), ignored.GetNetworkServiceManager()) == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package nsm
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+const replicaLabel = "replica"
+
+type replicaIdentity struct{}
+
+func (replicaIdentity) Key(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) string {
+	return endpoint.GetName() + "/" + endpoint.GetLabels()[replicaLabel] + ":" + manager.GetUrl()
+}
+
+// withReplicas - discovers two replicas of nse-1 distinguished only by label.
+func withReplicas(data *nseManagerTestData) {
+	replicaA := data.createEndpoint(nse1Name, remoteNSMName)
+	replicaA.NetworkServiceEndpoint.Labels = map[string]string{replicaLabel: "a"}
+	replicaB := data.createEndpoint(nse1Name, remoteNSMName)
+	replicaB.NetworkServiceEndpoint.Labels = map[string]string{replicaLabel: "b"}
+	data.setDiscoveredEndpoints(replicaA, replicaB)
+}
+
+func TestEndpointIdentity_Dedup(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withReplicas)
+	response := data.serviceRegistry.discoveryClient.response
+
+	_, candidates, err := data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, nil, data.nseManager.selectAndRecord)
+	g.Expect(err).To(BeNil())
+	g.Expect(candidates).To(HaveLen(1))
+
+	data = newNseManagerTestData(withReplicas, withManagerOptions(WithEndpointIdentity(replicaIdentity{})))
+	response = data.serviceRegistry.discoveryClient.response
+	_, candidates, err = data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, nil, data.nseManager.selectAndRecord)
+	g.Expect(err).To(BeNil())
+	g.Expect(candidates).To(HaveLen(2))
+}
+
+func TestEndpointIdentity_IgnoreMatching(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withReplicas)
+	replicaA := data.endpoints[0]
+	response := data.serviceRegistry.discoveryClient.response
+
+	_, _, err := data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, data.ignores(replicaA), data.nseManager.selectAndRecord)
+	g.Expect(err).NotTo(BeNil())
+
+	data = newNseManagerTestData(withReplicas, withManagerOptions(WithEndpointIdentity(replicaIdentity{})))
+	replicaA = data.endpoints[0]
+	response = data.serviceRegistry.discoveryClient.response
+	endpoint, _, err := data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, data.ignores(replicaA), data.nseManager.selectAndRecord)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetLabels()[replicaLabel]).To(Equal("b"))
+}
+
+type managerURLDeduplication struct{}
+
+func (managerURLDeduplication) DedupKey(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) string {
+	return manager.GetUrl()
+}
+
+func TestDeduplicationStrategy_SameURL(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+	response := data.serviceRegistry.discoveryClient.response
+	response.NetworkServiceManagers[remoteNSMName].Url = "10.0.0.1:5001"
+	nse1.NetworkServiceManager = response.NetworkServiceManagers[remoteNSMName]
+
+	_, candidates, err := data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, nil, data.nseManager.selectAndRecord)
+	g.Expect(err).To(BeNil())
+	g.Expect(candidates).To(HaveLen(2))
+
+	WithDeduplicationStrategy(managerURLDeduplication{})(data.nseManager)
+	_, candidates, err = data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, nil, data.nseManager.selectAndRecord)
+	g.Expect(err).To(BeNil())
+	g.Expect(candidates).To(HaveLen(1))
+	g.Expect(candidates[0].GetName()).To(Equal(nse1Name))
+
+	// Duplicates of ignored endpoint are the same endpoint, they are not selected either.
+	_, _, err = data.nseManager.selectEndpoint(nil, newTestRequestConnection(), response, data.ignores(nse1), data.nseManager.selectAndRecord)
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+}
@@ -0,0 +1,38 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestEndpointMetadata_OnlyAllowListed(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.ExportedEndpointLabels = []string{"backend-id", "shard-owner"}
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{
+		"backend-id": "b-42",
+		"secret":     "s3cr3t",
+	}
+	data.setDiscoveredEndpoints(nse1)
+
+	result := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Metadata).To(Equal(map[string]string{"backend-id": "b-42"}))
+}
+
+func TestEndpointMetadata_NothingAllowed(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{"backend-id": "b-42"}
+	data.setDiscoveredEndpoints(nse1)
+
+	result := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Metadata).To(BeEmpty())
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// EndpointRegisteredAtLabel - endpoint label with RFC 3339 time endpoint was registered at, endpoints registered
+// less than properties.EndpointMinAge ago are not selected.
+const EndpointRegisteredAtLabel = "nsm/registered-at"
+
+// endpointRegisteredAt - returns time endpoint was registered at from its label, false if there is no valid one.
+func endpointRegisteredAt(endpoint *registry.NetworkServiceEndpoint) (time.Time, bool) {
+	value, ok := endpoint.GetLabels()[EndpointRegisteredAtLabel]
+	if !ok {
+		return time.Time{}, false
+	}
+	registeredAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		logrus.Warnf("Endpoint %s has malformed %s label %q, ignoring it", endpoint.GetName(), EndpointRegisteredAtLabel, value)
+		return time.Time{}, false
+	}
+	return registeredAt, true
+}
+
+// filterMinAge - drops endpoints registered less than properties.EndpointMinAge ago, endpoints without registration
+// time are kept. If all endpoints are too young none is dropped.
+func (nsem *nseManager) filterMinAge(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	minAge := nsem.props.EndpointMinAge
+	if minAge <= 0 {
+		return endpoints
+	}
+	now := time.Now()
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if registeredAt, ok := endpointRegisteredAt(candidate); ok && now.Sub(registeredAt) < minAge {
+			continue
+		}
+		result = append(result, candidate)
+	}
+	if len(result) == 0 {
+		return endpoints
+	}
+	return result
+}
@@ -0,0 +1,65 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func (data *nseManagerTestData) createEndpointRegisteredAt(name, nsm string, registeredAt time.Time) *registry.NSERegistration {
+	reg := data.createEndpoint(name, nsm)
+	reg.NetworkServiceEndpoint.Labels = map[string]string{EndpointRegisteredAtLabel: registeredAt.Format(time.RFC3339)}
+	return reg
+}
+
+func TestEndpointMinAge_SkipsYoungEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.EndpointMinAge = time.Minute
+	data.setDiscoveredEndpoints(
+		data.createEndpointRegisteredAt(nse1Name, remoteNSMName, time.Now().Add(-59*time.Second)),
+		data.createEndpointRegisteredAt(nse2Name, remoteNSMName, time.Now().Add(-61*time.Second)),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse2Name, nse3Name))
+}
+
+func TestEndpointMinAge_AllYoungSelectsAmongThem(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.EndpointMinAge = time.Minute
+	data.setDiscoveredEndpoints(
+		data.createEndpointRegisteredAt(nse1Name, remoteNSMName, time.Now()),
+		data.createEndpointRegisteredAt(nse2Name, remoteNSMName, time.Now().Add(-30*time.Second)))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(BeElementOf(nse1Name, nse2Name))
+}
+
+func TestEndpointMinAge_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createEndpointRegisteredAt(nse1Name, remoteNSMName, time.Now()),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestEndpointMinAge_MalformedLabelIsIgnored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.EndpointMinAge = time.Minute
+	malformed := data.createEndpoint(nse1Name, remoteNSMName)
+	malformed.NetworkServiceEndpoint.Labels = map[string]string{EndpointRegisteredAtLabel: "yesterday"}
+	data.setDiscoveredEndpoints(
+		malformed,
+		data.createEndpointRegisteredAt(nse2Name, remoteNSMName, time.Now()))
+
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse1Name, nse1Name}))
+}
@@ -0,0 +1,56 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestEndpointPinning_PinFound(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTargetedRequestConnection(nse2Name, remoteNSMName, withLabel(UnpinOnFailureLabel, "true")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(result.Unpinned).To(BeFalse())
+}
+
+func TestEndpointPinning_PinMissingWithUnpin(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse2Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTargetedRequestConnection(nse1Name, remoteNSMName, withLabel(UnpinOnFailureLabel, "true")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(result.Unpinned).To(BeTrue())
+}
+
+func TestEndpointPinning_LocalPinMissingWithUnpin(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse2Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTargetedRequestConnection(nse1Name, localNSMName, withLabel(UnpinOnFailureLabel, "true")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(result.Unpinned).To(BeTrue())
+}
+
+func TestEndpointPinning_PinMissingWithoutUnpin(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse2Name, remoteNSMName))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("failed to find targeted NSE"))
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// selectReachable - selects endpoint for request, with properties.PrecheckEndpoints checks selected endpoint is
+// reachable with CheckUpdateNSE and reselects ignoring unreachable ones up to properties.DiscoveryRetryCount
+// times. Reselection reuses discovery response, ignoreEndpoints of caller are not modified.
+func (nsem *nseManager) selectReachable(ctx context.Context, span spanhelper.SpanHelper, budget *selectionBudget, requestConnection *connection.Connection,
+	endpointResponse *registry.FindNetworkServiceResponse, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NetworkServiceEndpoint, error) {
+	if !nsem.props.PrecheckEndpoints {
+		return nsem.selectForRequest(ctx, span, budget, requestConnection, endpointResponse, ignoreEndpoints)
+	}
+	ignores := ignoreEndpoints
+	for reselection := 0; ; reselection++ {
+		endpoint, err := nsem.selectForRequest(ctx, span, budget, requestConnection, endpointResponse, ignores)
+		if err != nil {
+			return nil, err
+		}
+		registration := newNSERegistration(endpointResponse, endpoint)
+		err = nsem.CheckUpdateNSEWithError(span.Context(), registration)
+		if err == nil {
+			span.LogValue("precheckReselections", reselection)
+			return endpoint, nil
+		}
+		if reselection >= nsem.props.DiscoveryRetryCount {
+			return nil, errors.Wrapf(err, "selected endpoint %s is not reachable after %d reselections", registration.GetEndpointNSMName(), reselection)
+		}
+		span.Logger().Infof("Selected endpoint %s is not reachable, reselecting: %v", registration.GetEndpointNSMName(), err)
+		if reselection == 0 {
+			ignores = copyIgnores(ignoreEndpoints)
+		}
+		ignores[registration.GetEndpointNSMName()] = registration
+	}
+}
@@ -0,0 +1,51 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// withScoredEndpoints - discovers endpoints on separate managers, selector prefers them in order of names.
+func withScoredEndpoints(data *nseManagerTestData) {
+	data.nseManager.props.DiscoveryRetryCount = 1
+	withSelector(&selectorStub{
+		scores: map[string]float64{nse1Name: 3, nse2Name: 2, nse3Name: 1},
+	})(data)
+	withSpreadEndpoints(nse1Name, nse2Name, nse3Name)(data)
+}
+
+func TestPrecheckEndpoints_Reselects(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withUnreachableManagers("nsm-1"))
+	data.nseManager.props.PrecheckEndpoints = true
+	ignores := data.ignores()
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(ignores).To(BeEmpty())
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+}
+
+func TestPrecheckEndpoints_Bounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withUnreachableManagers("nsm-1", "nsm-2", "nsm-3"))
+	data.nseManager.props.PrecheckEndpoints = true
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("is not reachable after 1 reselections"))
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+}
+
+func TestPrecheckEndpoints_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withUnreachableManagers("nsm-1"))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
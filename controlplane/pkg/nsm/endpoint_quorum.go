@@ -0,0 +1,162 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// QuorumLabel - endpoint label with number of reachable endpoints its network service requires before any of them
+// is selected, to avoid split-brain of stateful services. The largest value among endpoints of service is used.
+const QuorumLabel = "nsm/quorum"
+
+// ReachabilityChecker - checks endpoint could be reached, returns error if it could not.
+type ReachabilityChecker interface {
+	CheckReachable(ctx context.Context, endpoint *registry.NSERegistration) error
+}
+
+// WithReachabilityChecker - check reachability of endpoints when network service requires a quorum, without it
+// all ready endpoints are considered reachable.
+func WithReachabilityChecker(checker ReachabilityChecker) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.reachabilityChecker = checker
+	}
+}
+
+// serviceQuorum - returns quorum required by endpoints of network service, 0 if none.
+func serviceQuorum(endpoints []*registry.NetworkServiceEndpoint) int {
+	quorum := 0
+	for _, endpoint := range endpoints {
+		value, ok := endpoint.GetLabels()[QuorumLabel]
+		if !ok {
+			continue
+		}
+		endpointQuorum, err := strconv.Atoi(value)
+		if err != nil {
+			logrus.Warnf("Endpoint %s has malformed %s label %q, ignoring it", endpoint.GetName(), QuorumLabel, value)
+			continue
+		}
+		if endpointQuorum > quorum {
+			quorum = endpointQuorum
+		}
+	}
+	return quorum
+}
+
+// checkQuorum - checks network service quorum is met by ready and not ignored endpoints reachable within
+// validation phase budget.
+func (nsem *nseManager) checkQuorum(ctx context.Context, span spanhelper.SpanHelper, budget *selectionBudget,
+	endpointResponse *registry.FindNetworkServiceResponse, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) error {
+	quorum := serviceQuorum(endpointResponse.GetNetworkServiceEndpoints())
+	if quorum <= 0 {
+		return nil
+	}
+	var candidates []*registry.NSERegistration
+	for _, endpoint := range endpointResponse.GetNetworkServiceEndpoints() {
+		registration := newNSERegistration(endpointResponse, endpoint)
+		if !nsem.isIgnored(endpoint, registration.GetNetworkServiceManager(), ignoreEndpoints) && isEndpointReady(endpoint) {
+			candidates = append(candidates, registration)
+		}
+	}
+
+	reachable := 0
+	if len(candidates) >= quorum {
+		_ = budget.run(ctx, validationPhase, func(ctx context.Context) error {
+			reachable = nsem.countReachable(ctx, candidates, quorum)
+			return nil
+		})
+	}
+	span.LogValue("quorum", quorum)
+	span.LogValue("reachable", reachable)
+	if reachable < quorum {
+		return errors.Wrapf(ErrQuorumNotMet, "NetworkService %s has %d reachable endpoints, requires %d",
+			endpointResponse.GetNetworkService().GetName(), reachable, quorum)
+	}
+	return nil
+}
+
+// countReachable - checks candidates concurrently, stops checking when quorum is reached.
+func (nsem *nseManager) countReachable(ctx context.Context, candidates []*registry.NSERegistration, quorum int) int {
+	if nsem.reachabilityChecker == nil {
+		return len(candidates)
+	}
+	reachable := 0
+	nsem.checkReachable(ctx, candidates, func(candidate *registry.NSERegistration, err error) bool {
+		if err != nil {
+			logrus.Infof("Endpoint %s is not reachable: %v", candidate.GetEndpointNSMName(), err)
+			return false
+		}
+		reachable++
+		return reachable >= quorum
+	})
+	return reachable
+}
+
+// checkReachable - checks candidates concurrently, up to properties.QuorumCheckConcurrency at once. Results are
+// passed to onResult one at a time, checking stops when it returns true.
+func (nsem *nseManager) checkReachable(ctx context.Context, candidates []*registry.NSERegistration,
+	onResult func(candidate *registry.NSERegistration, err error) bool) {
+	checkConcurrently(ctx, candidates, nsem.props.QuorumCheckConcurrency, nsem.reachabilityChecker.CheckReachable, onResult)
+}
+
+// checkConcurrently - checks candidates concurrently, up to concurrency at once. Results are passed to onResult one
+// at a time, checking stops when it returns true or ctx is done, candidates not checked then get no result.
+func checkConcurrently(ctx context.Context, candidates []*registry.NSERegistration, concurrency int,
+	check func(ctx context.Context, candidate *registry.NSERegistration) error, onResult func(candidate *registry.NSERegistration, err error) bool) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	limit := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	mutex := sync.Mutex{}
+	stopped := false
+	for _, candidate := range candidates {
+		select {
+		case limit <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(candidate *registry.NSERegistration) {
+			defer func() {
+				<-limit
+				wg.Done()
+			}()
+			err := check(ctx, candidate)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if !stopped && onResult(candidate, err) {
+				stopped = true
+				cancel()
+			}
+		}(candidate)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,80 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type reachabilityCheckerStub struct {
+	sync.Mutex
+	unreachable map[string]bool
+	checked     int
+}
+
+func (c *reachabilityCheckerStub) CheckReachable(ctx context.Context, endpoint *registry.NSERegistration) error {
+	c.Lock()
+	defer c.Unlock()
+	c.checked++
+	if c.unreachable[endpoint.GetNetworkServiceEndpoint().GetName()] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func newReachabilityCheckerStub(unreachable ...string) *reachabilityCheckerStub {
+	checker := &reachabilityCheckerStub{unreachable: map[string]bool{}}
+	for _, name := range unreachable {
+		checker.unreachable[name] = true
+	}
+	return checker
+}
+
+// withQuorum - labels discovered endpoints with quorum.
+func withQuorum(quorum string) testDataOption {
+	return func(data *nseManagerTestData) {
+		for _, endpoint := range data.endpoints {
+			endpoint.NetworkServiceEndpoint.Labels = map[string]string{QuorumLabel: quorum}
+		}
+	}
+}
+
+func TestEndpointQuorum_Met(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, unreachable := range [][]string{nil, {nse3Name}} {
+		checker := newReachabilityCheckerStub(unreachable...)
+		data := newNseManagerTestData(withManagerOptions(WithReachabilityChecker(checker)), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name), withQuorum("2"))
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint).NotTo(BeNil())
+	}
+}
+
+func TestEndpointQuorum_NotMet(t *testing.T) {
+	g := NewWithT(t)
+
+	for _, unreachable := range [][]string{{nse3Name}, {nse1Name, nse3Name}, {nse1Name, nse2Name, nse3Name}} {
+		checker := newReachabilityCheckerStub(unreachable...)
+		data := newNseManagerTestData(withManagerOptions(WithReachabilityChecker(checker)), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name), withQuorum("3"))
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(errors.Is(err, ErrQuorumNotMet)).To(BeTrue())
+		g.Expect(checker.checked).To(Equal(3))
+	}
+}
+
+func TestEndpointQuorum_IgnoredEndpointsDoNotCount(t *testing.T) {
+	g := NewWithT(t)
+	checker := newReachabilityCheckerStub()
+	data := newNseManagerTestData(withManagerOptions(WithReachabilityChecker(checker)), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name), withQuorum("3"))
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1))
+	g.Expect(errors.Is(err, ErrQuorumNotMet)).To(BeTrue())
+	g.Expect(checker.checked).To(BeZero())
+}
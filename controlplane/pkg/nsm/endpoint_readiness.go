@@ -0,0 +1,46 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// EndpointReadyLabel - endpoint label to report readiness, endpoints labeled with "false" are alive but do not
+// accept new connections yet (warming caches, etc.). Same as Kubernetes readiness: targeted requests still resolve them.
+const EndpointReadyLabel = "nsm/ready"
+
+func isEndpointReady(endpoint *registry.NetworkServiceEndpoint) bool {
+	return endpoint.GetLabels()[EndpointReadyLabel] != "false"
+}
+
+// notReadyEmptied - counts endpoints rejected as not ready if readiness stage is the one which emptied candidates,
+// i.e. no endpoint was rejected by stages following it, and returns 0 otherwise.
+func notReadyEmptied(report RejectionReport) int {
+	notReady := 0
+	for _, reason := range report {
+		switch reason {
+		case RejectedNotReady:
+			notReady++
+		case RejectedDanglingManager, RejectedDuplicate, RejectedBlackhole, RejectedUnreachable, RejectedBlacklisted,
+			RejectedLocalQuarantine, RejectedDraining, RejectedApprovalDenied, RejectedIgnored, RejectedManagerIgnored:
+		default:
+			return 0
+		}
+	}
+	return notReady
+}
@@ -0,0 +1,71 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestGetEndpoint_SkipsNotReadyEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse2.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "true"}
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	for i := 0; i < 2; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestGetEndpoint_NoReadyEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse2))
+	g.Expect(errors.Is(err, ErrNoReadyEndpoints)).To(BeTrue())
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1, nse2))
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(errors.Is(err, ErrNoReadyEndpoints)).To(BeFalse())
+}
+
+func TestGetEndpoint_NotReadyAndFilteredOutEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	request := newTestRequestConnection(withLabel(AllowedManagersLabel, localNSMName))
+	_, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(errors.Is(err, ErrNoReadyEndpoints)).To(BeFalse())
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+}
+
+func TestGetEndpoint_TargetedNotReadyEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+	data.setDiscoveredEndpoints(nse1)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// EndpointSelector - selects endpoint for the request among endpoints left after filtering, nil if none fits.
+// Selectors implementing selector.Peeker, selector.Scorer, selector.CandidateSelector or
+// selector.DeterministicSelector are used for previews, scoring and deterministic selection as model selectors are.
+type EndpointSelector interface {
+	SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint
+}
+
+// modelEndpointSelector - default endpoint selector, selects with selector of the model of endpoint manager.
+type modelEndpointSelector struct {
+	nsem *nseManager
+}
+
+func (s modelEndpointSelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return s.nsem.model.GetSelector().SelectEndpoint(requestConnection, ns, networkServiceEndpoints)
+}
+
+// WithEndpointSelector - select endpoints with endpointSelector instead of model selector.
+func WithEndpointSelector(endpointSelector EndpointSelector) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.endpointSelector = endpointSelector
+	}
+}
+
+// activeSelector - returns selector endpoints of network service are selected with: selector overriding model
+// selector, selector of selection policy network service declares, or model selector, limited to the nearest NSMgrs
+// with properties.TopologyAwareSelection or balanced across NSMgrs with properties.ManagerFairSelection.
+func (nsem *nseManager) activeSelector(ns *registry.NetworkService) selector.Selector {
+	if _, ok := nsem.endpointSelector.(modelEndpointSelector); !ok {
+		return nsem.endpointSelector
+	}
+	if policySelector := nsem.policySelector(ns); policySelector != nil {
+		return policySelector
+	}
+	if nsem.props.TopologyAwareSelection {
+		return nsem.topology
+	}
+	if nsem.props.ManagerFairSelection {
+		return nsem.managerFair
+	}
+	return nsem.model.GetSelector()
+}
+
+// checkCandidate - checks selected endpoint is one of candidates selector was given, so misbehaving selector could
+// not route connection to filtered out endpoint.
+func checkCandidate(endpoint *registry.NetworkServiceEndpoint, candidates []*registry.NetworkServiceEndpoint) error {
+	for _, candidate := range candidates {
+		if candidate == endpoint {
+			return nil
+		}
+	}
+	logrus.Errorf("Selector returned endpoint %s (NSMgr=%s) which is not among %d candidates",
+		endpoint.GetName(), endpoint.GetNetworkServiceManagerName(), len(candidates))
+	return errors.Wrapf(ErrSelectedNotCandidate, "endpoint %s", endpoint.GetName())
+}
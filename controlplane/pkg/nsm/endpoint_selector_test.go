@@ -0,0 +1,70 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
+)
+
+func TestEndpointSelector_Injected(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	stub := &selectorStub{pick: lastEndpoint}
+	data.nseManager = newNseManager(data.serviceRegistry, data.model, properties.NewNsmProperties(), WithEndpointSelector(stub))
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse3 := data.createEndpoint(nse3Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2, nse3)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse3))
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(stub.offered).To(Equal([]string{nse1Name, nse2Name}))
+}
+
+func TestEndpointSelector_DefaultIsModelSelector(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	g.Expect(data.nseManager.endpointSelector).To(Equal(modelEndpointSelector{nsem: data.nseManager}))
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	expected := model.NewModel().GetSelector()
+	for i := 0; i < 4; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		ns := &registry.NetworkService{Name: networkServiceName}
+		want := expected.SelectEndpoint(newTestRequestConnection(), ns, []*registry.NetworkServiceEndpoint{nse1.GetNetworkServiceEndpoint(), nse2.GetNetworkServiceEndpoint()})
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(want.GetName()))
+	}
+}
+
+func TestEndpointSelector_SelectedNotCandidateRejected(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+	WithEndpointSelector(&selectorStub{pick: func([]*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+		return nse1.GetNetworkServiceEndpoint()
+	}})(data.nseManager)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1))
+	g.Expect(errors.Is(err, ErrSelectedNotCandidate)).To(BeTrue())
+
+	stale := *nse2.GetNetworkServiceEndpoint()
+	WithEndpointSelector(&selectorStub{pick: func([]*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+		return &stale
+	}})(data.nseManager)
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrSelectedNotCandidate)).To(BeTrue())
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// EndpointUpgradingLabel - endpoint label set to "true" while endpoint is being upgraded. Upgrading endpoints still
+// serve existing and targeted connections, but are not selected for new ones while there are other endpoints.
+const EndpointUpgradingLabel = "nsm/upgrading"
+
+func isEndpointUpgrading(endpoint *registry.NetworkServiceEndpoint) bool {
+	return endpoint.GetLabels()[EndpointUpgradingLabel] == "true"
+}
+
+// filterUpgrading - drops upgrading endpoints if there are others, if all of them are upgrading
+// properties.SelectUpgradingEndpoints decides whether to select among them or fail.
+func (nsem *nseManager) filterUpgrading(endpoints []*registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error) {
+	if len(endpoints) == 0 {
+		return endpoints, nil
+	}
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if !isEndpointUpgrading(candidate) {
+			result = append(result, candidate)
+		}
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+	if nsem.props.SelectUpgradingEndpoints {
+		return endpoints, nil
+	}
+	return nil, errors.Wrapf(ErrAllEndpointsUpgrading, "all %d endpoints", len(endpoints))
+}
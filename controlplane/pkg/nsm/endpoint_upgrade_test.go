@@ -0,0 +1,50 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func (data *nseManagerTestData) createUpgradingEndpoint(nse string) *registry.NSERegistration {
+	endpoint := data.createEndpoint(nse, remoteNSMName)
+	endpoint.NetworkServiceEndpoint.Labels = map[string]string{EndpointUpgradingLabel: "true"}
+	return endpoint
+}
+
+func TestEndpointUpgrade_AvoidedForNewConnections(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createUpgradingEndpoint(nse1Name),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createUpgradingEndpoint(nse3Name))
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestEndpointUpgrade_AllUpgrading(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createUpgradingEndpoint(nse1Name), data.createUpgradingEndpoint(nse2Name))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrAllEndpointsUpgrading)).To(BeTrue())
+
+	data.nseManager.props.SelectUpgradingEndpoints = true
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint).NotTo(BeNil())
+}
@@ -0,0 +1,105 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+const (
+	// EndpointVersionLabel - endpoint label with endpoint software version, e.g. v1.2.3
+	EndpointVersionLabel = "nsm/version"
+	// MinVersionLabel - request label with minimal endpoint version request accepts.
+	MinVersionLabel = "nsm/min-version"
+)
+
+// filterMinVersion - drops endpoints with version lower than requested by MinVersionLabel, endpoints without
+// version or with malformed one are treated as incompatible, only malformed versions are warned about.
+func filterMinVersion(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error) {
+	minVersion := requestConnection.GetLabels()[MinVersionLabel]
+	if minVersion == "" || len(endpoints) == 0 {
+		return endpoints, nil
+	}
+	required, err := parseVersion(minVersion)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %s label", MinVersionLabel)
+	}
+
+	result := []*registry.NetworkServiceEndpoint{}
+	available := []string{}
+	for _, candidate := range endpoints {
+		version, ok := candidate.GetLabels()[EndpointVersionLabel]
+		available = append(available, version)
+		if !ok {
+			// Unversioned endpoint could not be proven compatible.
+			continue
+		}
+		actual, err := parseVersion(version)
+		if err != nil {
+			logrus.Warnf("Endpoint %s has malformed version, treating it as incompatible: %v", candidate.GetName(), err)
+			continue
+		}
+		if compareVersions(actual, required) >= 0 {
+			result = append(result, candidate)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.Wrapf(ErrNoCompatibleVersion, "required %s, available %v", minVersion, available)
+	}
+	return result, nil
+}
+
+// parseVersion - parses semantic version major[.minor[.patch]] with optional "v" prefix,
+// pre-release and build metadata are ignored.
+func parseVersion(version string) ([3]int, error) {
+	var result [3]int
+	core := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if idx := strings.IndexAny(core, "-+"); idx >= 0 {
+		core = core[:idx]
+	}
+	parts := strings.Split(core, ".")
+	if core == "" || len(parts) > len(result) {
+		return result, errors.Errorf("malformed version %q", version)
+	}
+	for i, part := range parts {
+		value, err := strconv.Atoi(part)
+		if err != nil || value < 0 {
+			return result, errors.Errorf("malformed version %q", version)
+		}
+		result[i] = value
+	}
+	return result, nil
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,86 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func (data *nseManagerTestData) createVersionedEndpoint(nse, version string) *registry.NSERegistration {
+	endpoint := data.createEndpoint(nse, remoteNSMName)
+	endpoint.NetworkServiceEndpoint.Labels = map[string]string{EndpointVersionLabel: version}
+	return endpoint
+}
+
+func TestMinVersion_FiltersOlderEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createVersionedEndpoint(nse1Name, "v1.2.9"),
+		data.createVersionedEndpoint(nse2Name, "v1.10.0-rc1"))
+
+	for i := 0; i < 2; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(MinVersionLabel, "1.3")), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestMinVersion_NoCompatibleVersion(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createVersionedEndpoint(nse1Name, "v1.2.9"))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(MinVersionLabel, "v2.0.0")), nil)
+	g.Expect(errors.Is(err, ErrNoCompatibleVersion)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("required v2.0.0, available [v1.2.9]"))
+}
+
+func TestMinVersion_MalformedVersionIsIncompatible(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createVersionedEndpoint(nse1Name, "latest"),
+		data.createVersionedEndpoint(nse2Name, "v3"))
+
+	for i := 0; i < 2; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(MinVersionLabel, "v2.1")), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(MinVersionLabel, "2.x")), nil)
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestMinVersion_OnlyMalformedVersionIsWarned(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	unversioned := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(
+		unversioned,
+		data.createVersionedEndpoint(nse2Name, "latest"),
+		data.createVersionedEndpoint(nse3Name, "v3"))
+
+	hooks := logrus.StandardLogger().ReplaceHooks(logrus.LevelHooks{})
+	defer logrus.StandardLogger().ReplaceHooks(hooks)
+	hook := test.NewGlobal()
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(MinVersionLabel, "v2")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+	warned := []string{}
+	for _, entry := range hook.AllEntries() {
+		if entry.Level == logrus.WarnLevel {
+			warned = append(warned, entry.Message)
+		}
+	}
+	g.Expect(warned).To(HaveLen(1))
+	g.Expect(warned[0]).To(ContainSubstring(nse2Name))
+}
@@ -0,0 +1,128 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNoReadyEndpoints - all endpoints of network service which are available and not ignored report they are not
+	// ready, and no endpoint was filtered out by later stages.
+	ErrNoReadyEndpoints = errors.New("no ready endpoints")
+	// ErrNoEndpointMeetsSLO - no endpoint has RTT required by requested latency class.
+	ErrNoEndpointMeetsSLO = errors.New("no endpoint meets latency SLO")
+	// ErrCapabilitiesNotSatisfied - none of the tried endpoints negotiated capabilities required by request.
+	ErrCapabilitiesNotSatisfied = errors.New("required capabilities are not satisfied")
+	// ErrNoCompatibleVersion - no endpoint has version required by request.
+	ErrNoCompatibleVersion = errors.New("no endpoint with compatible version")
+	// ErrQuorumNotMet - network service has less reachable endpoints than quorum it requires.
+	ErrQuorumNotMet = errors.New("quorum of reachable endpoints is not met")
+	// ErrAllEndpointsUpgrading - all candidate endpoints are being upgraded and selecting them is not allowed.
+	ErrAllEndpointsUpgrading = errors.New("all endpoints are upgrading")
+	// ErrDataPathProbeFailed - endpoint accepted connection but data path probe through it failed.
+	ErrDataPathProbeFailed = errors.New("data path probe failed")
+	// ErrNSMNotInitialized - local NSM is not set in model yet, or is already removed from it.
+	ErrNSMNotInitialized = errors.New("local NSM is not initialized")
+	// ErrCandidatesExhausted - endpoints of network service are discovered, but all of them are ignored or excluded
+	// from selection.
+	ErrCandidatesExhausted = errors.New("all endpoints are ignored or excluded")
+	// ErrSelectorNotDeterministic - deterministic selection is required, but selector could not guarantee it.
+	ErrSelectorNotDeterministic = errors.New("selector is not deterministic")
+	// ErrSelectedNotCandidate - selector returned endpoint which is not among candidates it was given, e.g. a stale
+	// or ignored one.
+	ErrSelectedNotCandidate = errors.New("selected endpoint is not a candidate")
+	// ErrNoEndpointFound - selector selected no endpoint for network service.
+	ErrNoEndpointFound = errors.New("no endpoint found")
+	// ErrTargetEndpointNotFound - endpoint requested by name is not among discovered endpoints of network service.
+	ErrTargetEndpointNotFound = errors.New("target endpoint not found")
+	// ErrLocalEndpointNotFound - endpoint requested by name is not registered at local NSM, or is ignored, or local
+	// endpoint was deleted before client to it was created.
+	ErrLocalEndpointNotFound = errors.New("local endpoint not found")
+	// ErrRetryNotAllowed - retry budget of network service is exhausted, clients should back off instead of retrying.
+	ErrRetryNotAllowed = errors.New("retry not allowed")
+	// ErrIgnoresExhausted - request ignores more endpoints than allowed, retrying it with yet another ignored endpoint
+	// will not succeed.
+	ErrIgnoresExhausted = errors.New("ignored endpoints limit exceeded")
+	// ErrManagerCircuitOpen - remote NSMgr failed too many dials in a row and is not dialed until breaker cooldown
+	// passes.
+	ErrManagerCircuitOpen = errors.New("circuit of remote NSMgr is open")
+	// ErrRemoteDialPreempted - remote endpoint was not dialed as local endpoint of its network service appeared,
+	// selecting endpoint again prefers it.
+	ErrRemoteDialPreempted = errors.New("remote dial preempted by local endpoint")
+	// ErrInvalidRequest - request connection is malformed, endpoint could not be selected for it.
+	ErrInvalidRequest = errors.New("invalid request")
+	// ErrInconsistentManagers - registry discovered endpoints of network service, but none of the network service
+	// managers hosting them, so none of them could be connected to.
+	ErrInconsistentManagers = errors.New("discovered managers are inconsistent with endpoints")
+	// ErrPreviewNotSupported - selector of network service could not tell which endpoint it would select without
+	// advancing its state, so selections could not be previewed.
+	ErrPreviewNotSupported = errors.New("selector does not support preview")
+)
+
+// EndpointNotFoundError - error returned when endpoint could not be found for request, its message keeps the details
+// while Unwrap gives the kind, one of ErrNoEndpointFound, ErrTargetEndpointNotFound or ErrLocalEndpointNotFound,
+// to be checked with errors.Is.
+type EndpointNotFoundError struct {
+	Kind                  error
+	NetworkService        string
+	Endpoint              string
+	NetworkServiceManager string
+	// Checked - number of endpoints checked before giving up.
+	Checked int
+	message string
+}
+
+func newEndpointNotFoundError(kind error, networkService, endpoint, networkServiceManager string, checked int, format string, args ...interface{}) *EndpointNotFoundError {
+	return &EndpointNotFoundError{
+		Kind:                  kind,
+		NetworkService:        networkService,
+		Endpoint:              endpoint,
+		NetworkServiceManager: networkServiceManager,
+		Checked:               checked,
+		message:               fmt.Sprintf(format, args...),
+	}
+}
+
+func (e *EndpointNotFoundError) Error() string {
+	return e.message
+}
+
+// Unwrap - returns kind of the error.
+func (e *EndpointNotFoundError) Unwrap() error {
+	return e.Kind
+}
+
+// SelectorReturnedNilError - error returned when selector selected no endpoint although it was given candidates,
+// which usually means the selector is buggy, though e.g. match selector does so when no route matches. It is still
+// EndpointNotFoundError of ErrNoEndpointFound kind.
+type SelectorReturnedNilError struct {
+	// Candidates - number of candidates selector was given.
+	Candidates int
+	notFound   *EndpointNotFoundError
+}
+
+func (e *SelectorReturnedNilError) Error() string {
+	return e.notFound.Error()
+}
+
+// Unwrap - returns EndpointNotFoundError of the failure.
+func (e *SelectorReturnedNilError) Unwrap() error {
+	return e.notFound
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// selectFunc - returns function GetEndpoint selects endpoint of network service with, see
+// properties.DeterministicSelection, properties.OrderedFallback and properties.FairnessShare. Deterministic
+// selections are not subject to fairness guard.
+func (nsem *nseManager) selectFunc(ns *registry.NetworkService) (selectFunc, error) {
+	if nsem.props.DeterministicSelection {
+		if _, ok := nsem.activeSelector(ns).(selector.DeterministicSelector); !ok {
+			return nil, errors.Wrapf(ErrSelectorNotDeterministic, "selector %T", nsem.activeSelector(ns))
+		}
+		return nsem.selectDeterministic, nil
+	}
+	if nsem.props.OrderedFallback {
+		return nsem.fairSelect(nsem.selectBestScored), nil
+	}
+	return nsem.fairSelect(nsem.selectAndRecord), nil
+}
+
+// selectBestScored - selects the best scored endpoint, ties are broken by endpoint name so order is stable.
+// Endpoints which could not be scored are selected by model selector.
+func (nsem *nseManager) selectBestScored(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+	scores := nsem.scoreCandidates(requestConnection, ns, endpoints, managers)
+	if len(endpoints) == 0 || len(scores) != len(endpoints) {
+		return nsem.selectAndRecord(requestConnection, ns, endpoints, managers)
+	}
+	best := 0
+	for i := 1; i < len(endpoints); i++ {
+		if scores[i] > scores[best] || scores[i] == scores[best] && endpoints[i].GetName() < endpoints[best].GetName() {
+			best = i
+		}
+	}
+	nsem.recordSkew(ns, endpoints, endpoints[best], managers)
+	return endpoints[best]
+}
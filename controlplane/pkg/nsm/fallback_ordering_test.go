@@ -0,0 +1,44 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func healLoop(data *nseManagerTestData) ([]string, error) {
+	ignores := map[registry.EndpointNSMName]*registry.NSERegistration{}
+	names := []string{}
+	for {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, endpoint.GetNetworkServiceEndpoint().GetName())
+		ignores[endpoint.GetEndpointNSMName()] = endpoint
+	}
+}
+
+func TestOrderedFallback_DescendingScore(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.OrderedFallback = true
+	withSelector(&selectorStub{
+		pick:   roundRobin(),
+		scores: map[string]float64{nse1Name: 1, nse2Name: 3, nse3Name: 2},
+	})(data)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	for i := 0; i < 2; i++ {
+		names, err := healLoop(data)
+		g.Expect(names).To(Equal([]string{nse2Name, nse3Name, nse1Name}))
+		g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+type endpointFilter func(endpoints []*registry.NetworkServiceEndpoint, report RejectionReport) ([]*registry.NetworkServiceEndpoint, error)
+
+// filterStage - named step of endpoint filtering. Endpoints dropped by stage with reason are rejected for it, stages
+// without reason report rejections themselves.
+type filterStage struct {
+	name   string
+	reason string
+	filter endpointFilter
+}
+
+// filterStageEvent - span event of filter stage, how many endpoints it was given and let through.
+type filterStageEvent struct {
+	Stage  string `json:"stage"`
+	Before int    `json:"before"`
+	After  int    `json:"after"`
+}
+
+// runFilterStages - passes endpoints through stages in order, logging survivor count of each stage to span if it
+// is not nil. Filtering stops at the first stage failing.
+func runFilterStages(span spanhelper.SpanHelper, endpoints []*registry.NetworkServiceEndpoint, report RejectionReport,
+	stages []filterStage) ([]*registry.NetworkServiceEndpoint, error) {
+	for _, stage := range stages {
+		filtered, err := stage.filter(endpoints, report)
+		if stage.reason != "" {
+			filtered = report.filtered(stage.reason, endpoints, filtered)
+		}
+		if span != nil {
+			span.LogObject("filterStage", &filterStageEvent{Stage: stage.name, Before: len(endpoints), After: len(filtered)})
+		}
+		if err != nil {
+			return nil, err
+		}
+		endpoints = filtered
+	}
+	return endpoints, nil
+}
+
+// infallible - adapts filter which could not fail and does not report rejections itself.
+func infallible(filter func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint) endpointFilter {
+	return func(endpoints []*registry.NetworkServiceEndpoint, _ RejectionReport) ([]*registry.NetworkServiceEndpoint, error) {
+		return filter(endpoints), nil
+	}
+}
+
+// fallible - adapts filter which could fail and does not report rejections itself.
+func fallible(filter func(endpoints []*registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error)) endpointFilter {
+	return func(endpoints []*registry.NetworkServiceEndpoint, _ RejectionReport) ([]*registry.NetworkServiceEndpoint, error) {
+		return filter(endpoints)
+	}
+}
@@ -0,0 +1,143 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+type filterStageSpanStub struct {
+	spanhelper.SpanHelper
+	events []filterStageEvent
+}
+
+func (stub *filterStageSpanStub) LogObject(attribute string, value interface{}) {
+	if event, ok := value.(*filterStageEvent); ok && attribute == "filterStage" {
+		stub.events = append(stub.events, *event)
+	}
+}
+
+// legacyFilterEndpoints - filterEndpointsReported before it was split into stages.
+func (nsem *nseManager) legacyFilterEndpoints(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, report RejectionReport) ([]*registry.NetworkServiceEndpoint, error) {
+	result := []*registry.NetworkServiceEndpoint{}
+	seen := map[string]bool{}
+	for _, candidate := range endpoints {
+		manager := managers[candidate.NetworkServiceManagerName]
+		if manager == nil {
+			report.reject(candidate, RejectedDanglingManager)
+			continue
+		}
+		key := nsem.identity.Key(candidate, manager)
+		dedupKey := nsem.dedupKey(candidate, manager)
+		if seen[dedupKey] || nsem.quarantine.contains(key) || nsem.unreachable.contains(key) || nsem.blacklist.contains(key) ||
+			nsem.localQuarantine.contains(key) || nsem.drained.contains(key) {
+			if report != nil {
+				report.reject(candidate, nsem.unavailableReason(seen[dedupKey], key))
+			}
+			continue
+		}
+		if _, denied := nsem.deniedApproval(requestConnection, key); denied {
+			report.reject(candidate, RejectedApprovalDenied)
+			continue
+		}
+		seen[dedupKey] = true
+		if isManagerIgnored(candidate, ignoreEndpoints) {
+			report.reject(candidate, RejectedManagerIgnored)
+		} else if nsem.isIgnored(candidate, manager, ignoreEndpoints) {
+			report.reject(candidate, RejectedIgnored)
+		} else if !isEndpointReady(candidate) {
+			report.reject(candidate, RejectedNotReady)
+		} else {
+			result = append(result, candidate)
+		}
+	}
+	filtered, err := nsem.filterUpgrading(result)
+	result = report.filtered(RejectedUpgrading, result, filtered)
+	if err != nil {
+		return nil, err
+	}
+	filtered, err = filterMinVersion(requestConnection, result)
+	result = report.filtered(RejectedVersion, result, filtered)
+	if err != nil {
+		return nil, err
+	}
+	result = report.filtered(RejectedLabels, result, nsem.filterLabelMatches(requestConnection, result))
+	result = report.filtered(RejectedManagerNotAllowed, result, filterAllowedManagers(requestConnection, result))
+	result = report.filtered(RejectedMechanisms, result, filterMechanisms(requestConnection, result))
+	filtered, err = nsem.filterLatencyClass(requestConnection, result, managers)
+	result = report.filtered(RejectedLatencyClass, result, filtered)
+	if err != nil {
+		return nil, err
+	}
+	result = report.filtered(RejectedSLAViolations, result, nsem.filterSLAViolations(requestConnection.GetNetworkService(), result, managers))
+	result = report.filtered(RejectedFailureRate, result, nsem.filterFailureRate(result, managers))
+	result = report.filtered(RejectedTooYoung, result, nsem.filterMinAge(result))
+	result = report.filtered(RejectedCooldown, result, nsem.filterCooldown(result, managers))
+	sortEndpoints(result)
+	return report.filtered(RejectedNotLocal, result, nsem.preferLocal(result)), nil
+}
+
+func TestFilterStages_IgnoresOnlyMatchLegacy(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	random := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		var registrations []*registry.NSERegistration
+		ignores := data.ignores()
+		// Endpoints of different managers with the same name are duplicates, rejection report keys are unique.
+		for _, j := range random.Perm(18)[:random.Intn(9)] {
+			registration := data.createEndpoint(fmt.Sprintf("nse-%d", j%6), fmt.Sprintf("nsm-%d", j/6))
+			if random.Intn(4) == 0 {
+				registration.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+			}
+			switch random.Intn(4) {
+			case 0:
+				ignores[registration.GetEndpointNSMName()] = registration
+			case 1:
+				IgnoreManager(ignores, registration.GetNetworkServiceManager().GetName())
+			}
+			registrations = append(registrations, registration)
+		}
+		response := data.createFindNetworkServiceResponse(registrations...)
+		endpoints, managers := response.GetNetworkServiceEndpoints(), response.GetNetworkServiceManagers()
+
+		legacyReport, report := RejectionReport{}, RejectionReport{}
+		expected, expectedErr := data.nseManager.legacyFilterEndpoints(newTestRequestConnection(), append([]*registry.NetworkServiceEndpoint{}, endpoints...), managers, ignores, legacyReport)
+		actual, err := data.nseManager.filterEndpointsReported(nil, newTestRequestConnection(), append([]*registry.NetworkServiceEndpoint{}, endpoints...), managers, ignores, report)
+		g.Expect(expectedErr).To(BeNil())
+		g.Expect(err).To(BeNil())
+		g.Expect(actual).To(Equal(expected))
+		g.Expect(report).To(Equal(legacyReport))
+	}
+}
+
+func TestFilterStages_SurvivorCountsLogged(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse2.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+	response := data.createFindNetworkServiceResponse(nse1, nse2, data.createEndpoint(nse3Name, remoteNSMName))
+	span := &filterStageSpanStub{SpanHelper: spanhelper.FromContext(context.Background(), "test")}
+
+	endpoint, _, err := data.nseManager.selectEndpoint(span, newTestRequestConnection(), response, data.ignores(nse1), data.nseManager.selectAndRecord)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetName()).To(Equal(nse3Name))
+	g.Expect(span.events).To(HaveLen(14))
+	g.Expect(span.events[:4]).To(Equal([]filterStageEvent{
+		{Stage: "availability", Before: 3, After: 3},
+		{Stage: "ignores", Before: 3, After: 2},
+		{Stage: "readiness", Before: 2, After: 1},
+		{Stage: "upgrading", Before: 1, After: 1},
+	}))
+	g.Expect(span.events[13]).To(Equal(filterStageEvent{Stage: "locality", Before: 1, After: 1}))
+}
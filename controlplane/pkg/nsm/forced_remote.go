@@ -0,0 +1,36 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+)
+
+type forcedRemoteKey struct{}
+
+// WithForcedRemote - test and chaos hook, not for production use: asks CreateNSEClient to dial endpoints of local
+// NSM through RemoteNetworkServiceClient as if they were remote, to exercise cross-cluster dialing within one
+// cluster. Only the dial is affected, IsLocalEndpoint and endpoint selection still treat such endpoints as local.
+func WithForcedRemote(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forcedRemoteKey{}, true)
+}
+
+// forcedRemote - tells if WithForcedRemote was set on ctx.
+func forcedRemote(ctx context.Context) bool {
+	forced, _ := ctx.Value(forcedRemoteKey{}).(bool)
+	return forced
+}
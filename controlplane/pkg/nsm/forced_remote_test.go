@@ -0,0 +1,28 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func TestForcedRemote_DialsLocalEndpointRemotely(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, localNSMName)
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: nse1})
+
+	_, err := data.nseManager.CreateNSEClient(WithForcedRemote(context.Background()), nse1)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+	g.Expect(data.serviceRegistry.remoteDials[0].GetName()).To(Equal(localNSMName))
+	// Only the dial is forced remote.
+	g.Expect(data.nseManager.IsLocalEndpoint(nse1)).To(BeTrue())
+
+	_, err = data.nseManager.CreateNSEClient(context.Background(), nse1)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+)
+
+// healCheckBudget - splits properties.HealCheckBudget across probes of CheckUpdateNSEBatch, so checking many
+// endpoints does not take HealRequestConnectCheckTimeout per endpoint.
+type healCheckBudget struct {
+	sync.Mutex
+	start       time.Time
+	budget      time.Duration
+	floor       time.Duration
+	ceiling     time.Duration
+	concurrency int
+	remaining   int
+}
+
+func newHealCheckBudget(budget, floor, ceiling time.Duration, concurrency, candidates int) *healCheckBudget {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return &healCheckBudget{
+		start:       time.Now(),
+		budget:      budget,
+		floor:       floor,
+		ceiling:     ceiling,
+		concurrency: concurrency,
+		remaining:   candidates,
+	}
+}
+
+// next - returns timeout of next probe: budget left divided by number of rounds of concurrent probes left, but no
+// more than ceiling and no less than floor.
+func (b *healCheckBudget) next() time.Duration {
+	b.Lock()
+	defer b.Unlock()
+	rounds := (b.remaining + b.concurrency - 1) / b.concurrency
+	if b.remaining > 0 {
+		b.remaining--
+	}
+	if rounds <= 0 {
+		rounds = 1
+	}
+	timeout := (b.budget - time.Since(b.start)) / time.Duration(rounds)
+	if timeout > b.ceiling {
+		timeout = b.ceiling
+	}
+	if timeout < b.floor {
+		timeout = b.floor
+	}
+	return timeout
+}
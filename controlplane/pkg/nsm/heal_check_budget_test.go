@@ -0,0 +1,55 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestHealCheckBudget_SplitAcrossRounds(t *testing.T) {
+	g := NewWithT(t)
+	budget := newHealCheckBudget(time.Minute, time.Second, time.Minute, 2, 4)
+
+	// 4 candidates checked 2 at once take 2 rounds.
+	g.Expect(budget.next()).To(BeNumerically("~", 30*time.Second, time.Second))
+	g.Expect(budget.next()).To(BeNumerically("~", 30*time.Second, time.Second))
+	g.Expect(budget.next()).To(BeNumerically("~", time.Minute, time.Second))
+}
+
+func TestHealCheckBudget_Bounds(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(newHealCheckBudget(time.Minute, time.Second, 5*time.Second, 1, 2).next()).To(Equal(5 * time.Second))
+	g.Expect(newHealCheckBudget(time.Second, 500*time.Millisecond, 5*time.Second, 1, 10).next()).To(Equal(500 * time.Millisecond))
+	// Exhausted budget still gives floor.
+	g.Expect(newHealCheckBudget(0, 500*time.Millisecond, 5*time.Second, 1, 1).next()).To(Equal(500 * time.Millisecond))
+}
+
+func TestCheckUpdateNSEBatch_RespectsBudget(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withHangingDials)
+	defer close(data.serviceRegistry.hang)
+	data.nseManager.props.HealCheckJitter = 0
+	data.nseManager.props.HealCheckConcurrency = 1
+	data.nseManager.props.HealRequestConnectCheckTimeout = time.Second
+	data.nseManager.props.HealCheckBudget = 300 * time.Millisecond
+	data.nseManager.props.HealCheckMinTimeout = 10 * time.Millisecond
+	var registrations []*registry.NSERegistration
+	for i := 0; i < 5; i++ {
+		registrations = append(registrations, data.createEndpoint(fmt.Sprintf("nse-%d", i), remoteNSMName))
+	}
+
+	start := time.Now()
+	result := data.nseManager.CheckUpdateNSEBatch(context.Background(), registrations)
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	g.Expect(time.Since(start)).To(BeNumerically(">=", 250*time.Millisecond))
+	g.Expect(result).To(HaveLen(5))
+	for _, err := range result {
+		g.Expect(err).To(Equal(context.DeadlineExceeded))
+	}
+}
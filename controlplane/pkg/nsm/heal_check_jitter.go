@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// healCheckJitter - spreads heal check timeouts, so NSMgrs restarted together do not ping endpoints in lockstep,
+// zero value is ready to use.
+type healCheckJitter struct {
+	sync.Mutex
+	random *rand.Rand
+}
+
+// timeout - returns base timeout extended by random part of up to jitter fraction of it, in [base, base+base*jitter].
+func (j *healCheckJitter) timeout(base time.Duration, jitter float64) time.Duration {
+	if jitter <= 0 {
+		return base
+	}
+	j.Lock()
+	defer j.Unlock()
+	if j.random == nil {
+		j.random = rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404 - jitter does not need secure random
+	}
+	return base + time.Duration(j.random.Float64()*jitter*float64(base))
+}
@@ -0,0 +1,48 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func TestHealCheckJitter_Bounds(t *testing.T) {
+	g := NewWithT(t)
+	jitter := &healCheckJitter{}
+	base := time.Second
+
+	g.Expect(jitter.timeout(base, 0)).To(Equal(base))
+	spread := false
+	for i := 0; i < 1000; i++ {
+		timeout := jitter.timeout(base, 0.5)
+		g.Expect(timeout).To(BeNumerically(">=", base))
+		g.Expect(timeout).To(BeNumerically("<=", base+base/2))
+		spread = spread || timeout != base
+	}
+	g.Expect(spread).To(BeTrue())
+}
+
+func TestCheckUpdateNSE_PingTimeout(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.HealRequestConnectCheckTimeout = time.Second
+	data.nseManager.props.HealCheckJitter = 0.5
+	endpoint := data.createEndpoint(nse1Name, localNSMName)
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: endpoint})
+
+	start := time.Now()
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), endpoint)).To(BeTrue())
+	deadline, ok := data.serviceRegistry.endpointCtx.Deadline()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(deadline.Sub(start)).To(BeNumerically(">=", time.Second))
+	g.Expect(deadline.Sub(start)).To(BeNumerically("<=", 1500*time.Millisecond+50*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	data.nseManager.CheckUpdateNSE(ctx, endpoint)
+	g.Expect(data.serviceRegistry.endpointCtxErr).To(Equal(context.Canceled))
+}
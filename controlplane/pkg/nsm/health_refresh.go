@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// RefreshServiceHealth - checks reachability of all endpoints of network service, returns results keyed by
+// endpoint NSM name. Unreachable endpoints are not selected for properties.UnreachableQuarantine, reachable ones
+// become selectable again. Without reachability checker all endpoints are reachable.
+func (nsem *nseManager) RefreshServiceHealth(ctx context.Context, serviceName string) (map[string]bool, error) {
+	span := spanhelper.FromContext(ctx, "RefreshServiceHealth")
+	defer span.Finish()
+	endpointResponse, err := nsem.findNetworkService(span.Context(), span, serviceName)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*registry.NSERegistration, 0, len(endpointResponse.GetNetworkServiceEndpoints()))
+	for _, endpoint := range endpointResponse.GetNetworkServiceEndpoints() {
+		candidates = append(candidates, newNSERegistration(endpointResponse, endpoint))
+	}
+	result := make(map[string]bool, len(candidates))
+	onResult := func(candidate *registry.NSERegistration, err error) bool {
+		key := nsem.identity.Key(candidate.GetNetworkServiceEndpoint(), candidate.GetNetworkServiceManager())
+		result[string(candidate.GetEndpointNSMName())] = err == nil
+		if nsem.reachabilityChecker != nil {
+			nsem.observeReachability(candidate, reachabilityOf(err))
+		}
+		if err == nil || nsem.props.UnreachableQuarantine <= 0 {
+			nsem.unreachable.remove(key)
+			return false
+		}
+		span.Logger().Infof("Endpoint %s is not reachable: %v", candidate.GetEndpointNSMName(), err)
+		nsem.unreachable.add(key, nsem.props.UnreachableQuarantine)
+		return false
+	}
+	if nsem.reachabilityChecker == nil {
+		for _, candidate := range candidates {
+			onResult(candidate, nil)
+		}
+	} else {
+		nsem.checkReachable(span.Context(), candidates, onResult)
+	}
+	span.LogObject("health", result)
+	return result, nil
+}
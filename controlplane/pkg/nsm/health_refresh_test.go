@@ -0,0 +1,46 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func endpointNSMName(nse string) string {
+	return string(registry.NewEndpointNSMName(&registry.NetworkServiceEndpoint{Name: nse}, &registry.NetworkServiceManager{Name: remoteNSMName}))
+}
+
+func TestRefreshServiceHealth(t *testing.T) {
+	g := NewWithT(t)
+	checker := newReachabilityCheckerStub(nse2Name)
+	data := newNseManagerTestData(withManagerOptions(WithReachabilityChecker(checker)), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	health, err := data.nseManager.RefreshServiceHealth(context.Background(), networkServiceName)
+	g.Expect(err).To(BeNil())
+	g.Expect(checker.checked).To(Equal(3))
+	g.Expect(health).To(Equal(map[string]bool{
+		endpointNSMName(nse1Name): true,
+		endpointNSMName(nse2Name): false,
+		endpointNSMName(nse3Name): true,
+	}))
+
+	g.Expect(data.selectedNames(4)).NotTo(ContainElement(nse2Name))
+
+	checker.unreachable = nil
+	_, err = data.nseManager.RefreshServiceHealth(context.Background(), networkServiceName)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.selectedNames(3)).To(ConsistOf(nse1Name, nse2Name, nse3Name))
+}
+
+func TestRefreshServiceHealth_NoChecker(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	health, err := data.nseManager.RefreshServiceHealth(context.Background(), networkServiceName)
+	g.Expect(err).To(BeNil())
+	g.Expect(health).To(Equal(map[string]bool{endpointNSMName(nse1Name): true}))
+}
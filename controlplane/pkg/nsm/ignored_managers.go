@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// ignoredManagerKey - key of ignore map entry ignoring all endpoints hosted by network service manager, does not
+// collide with endpoint keys as they are never prefixed with ":".
+func ignoredManagerKey(managerName string) registry.EndpointNSMName {
+	return registry.EndpointNSMName(":" + managerName)
+}
+
+// IgnoreManager - adds network service manager to ignoreEndpoints, so all endpoints hosted by it are ignored along
+// with endpoints ignored one by one, e.g. once manager could not be reached. Entry is a registration of the manager
+// without endpoint.
+func IgnoreManager(ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, managerName string) {
+	ignoreEndpoints[ignoredManagerKey(managerName)] = &registry.NSERegistration{
+		NetworkServiceManager: &registry.NetworkServiceManager{Name: managerName},
+	}
+}
+
+// isIgnoredManagerEntry - tells if ignore map entry ignores a whole manager rather than an endpoint.
+func isIgnoredManagerEntry(ignored *registry.NSERegistration) bool {
+	return ignored != nil && ignored.GetNetworkServiceEndpoint() == nil && ignored.GetNetworkServiceManager().GetName() != ""
+}
+
+// isManagerIgnored - tells if endpoint is hosted by a manager ignored with IgnoreManager.
+func isManagerIgnored(endpoint *registry.NetworkServiceEndpoint, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) bool {
+	for _, ignored := range ignoreEndpoints {
+		if isIgnoredManagerEntry(ignored) && ignored.GetNetworkServiceManager().GetName() == endpoint.GetNetworkServiceManagerName() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+const nse4Name = "nse-4"
+
+func TestIgnoreManager_WithEndpointIgnores(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints("nsm-2", nse1Name, nse2Name), withEndpoints("nsm-3", nse3Name, nse4Name))
+	data.nseManager.props.SelectionRejectionReport = true
+	endpoints := data.endpoints
+	ignores := data.ignores(endpoints[2])
+	IgnoreManager(ignores, "nsm-2")
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse4Name))
+	}
+}
+
+func TestIgnoreManager_Exhausted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints("nsm-2", nse1Name, nse2Name), withEndpoints("nsm-3", nse3Name, nse4Name))
+	data.nseManager.props.SelectionRejectionReport = true
+	endpoints := data.endpoints
+	ignores := data.ignores(endpoints[3])
+	IgnoreManager(ignores, "nsm-2")
+	ignores[endpoints[2].GetEndpointNSMName()] = endpoints[2]
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+	report, ok := RejectionsFrom(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(report).To(Equal(RejectionReport{
+		nse1Name + ":nsm-2": RejectedManagerIgnored,
+		nse2Name + ":nsm-2": RejectedManagerIgnored,
+		nse3Name + ":nsm-3": RejectedIgnored,
+		nse4Name + ":nsm-3": RejectedIgnored,
+	}))
+}
+
+func TestIgnoreManager_OtherManagersSelected(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints("nsm-2", nse1Name, nse2Name), withEndpoints("nsm-3", nse3Name, nse4Name))
+	data.nseManager.props.SelectionRejectionReport = true
+	ignores := data.ignores()
+	IgnoreManager(ignores, "nsm-3")
+
+	names := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+		g.Expect(err).To(BeNil())
+		names[endpoint.GetNetworkServiceEndpoint().GetName()] = true
+	}
+	g.Expect(names).To(Equal(map[string]bool{nse1Name: true, nse2Name: true}))
+}
+
+func TestConnectToAnyEndpoint_IgnoresUnreachableManager(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withScoredEndpoints, withUnreachableManagers("nsm-2"))
+	data.nseManager.props.ConnectAttempts = 3
+	withSelector(&selectorStub{
+		scores: map[string]float64{nse1Name: 4, nse2Name: 3, nse4Name: 2, nse3Name: 1},
+	})(data)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, localNSMName),
+		data.createEndpoint(nse2Name, "nsm-2"),
+		data.createEndpoint(nse4Name, "nsm-2"),
+		data.createEndpoint(nse3Name, "nsm-3"))
+	ignores := data.ignores()
+
+	endpoint, _, err := data.nseManager.ConnectToAnyEndpoint(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+	g.Expect(ignores).To(BeEmpty())
+}
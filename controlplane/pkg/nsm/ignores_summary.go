@@ -0,0 +1,47 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sort"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// ignoresLogSampleSize - how many ignored endpoints are logged to span, heal loops could grow ignores a lot.
+const ignoresLogSampleSize = 5
+
+type ignoresSummary struct {
+	Count  int                        `json:"count"`
+	Sample []registry.EndpointNSMName `json:"sample,omitempty"`
+}
+
+// newIgnoresSummary - returns a bounded representation of ignore map for span logging.
+func newIgnoresSummary(ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) *ignoresSummary {
+	names := make([]registry.EndpointNSMName, 0, len(ignoreEndpoints))
+	for name := range ignoreEndpoints {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	if len(names) > ignoresLogSampleSize {
+		names = names[:ignoresLogSampleSize]
+	}
+	return &ignoresSummary{
+		Count:  len(ignoreEndpoints),
+		Sample: names,
+	}
+}
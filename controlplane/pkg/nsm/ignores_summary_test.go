@@ -0,0 +1,44 @@
+package nsm
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestIgnoresSummary_Bounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	ignores := map[registry.EndpointNSMName]*registry.NSERegistration{}
+	for i := 0; i < 1000; i++ {
+		nse := data.createEndpoint(fmt.Sprintf("nse-%04d", i), remoteNSMName)
+		ignores[nse.GetEndpointNSMName()] = nse
+	}
+
+	summary := newIgnoresSummary(ignores)
+	g.Expect(summary.Count).To(Equal(1000))
+	g.Expect(summary.Sample).To(Equal([]registry.EndpointNSMName{"nse-0000:", "nse-0001:", "nse-0002:", "nse-0003:", "nse-0004:"}))
+
+	bytes, err := json.Marshal(summary)
+	g.Expect(err).To(BeNil())
+	g.Expect(len(bytes)).To(BeNumerically("<", 200))
+}
+
+func TestIgnoresSummary_Small(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	summary := newIgnoresSummary(data.ignores(nse1))
+	g.Expect(summary.Count).To(Equal(1))
+	g.Expect(summary.Sample).To(Equal([]registry.EndpointNSMName{nse1.GetEndpointNSMName()}))
+
+	summary = newIgnoresSummary(nil)
+	g.Expect(summary.Count).To(Equal(0))
+	g.Expect(summary.Sample).To(BeEmpty())
+}
@@ -0,0 +1,48 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// labelsMatch - tells if endpoint labels do not contradict request labels, every label both of them have must have
+// the same value. Endpoints without labels match any request.
+func labelsMatch(requestLabels, endpointLabels map[string]string) bool {
+	for key, value := range requestLabels {
+		if endpointValue, ok := endpointLabels[key]; ok && endpointValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// filterLabelMatches - drops endpoints with labels not matching labels of request, see properties.MatchEndpointLabels.
+func (nsem *nseManager) filterLabelMatches(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	requestLabels := requestConnection.GetLabels()
+	if !nsem.props.MatchEndpointLabels || len(requestLabels) == 0 {
+		return endpoints
+	}
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if labelsMatch(requestLabels, candidate.GetLabels()) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
@@ -0,0 +1,45 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestLabelsMatch(t *testing.T) {
+	g := NewWithT(t)
+	g.Expect(labelsMatch(nil, map[string]string{"zone": "us-east"})).To(BeTrue())
+	g.Expect(labelsMatch(map[string]string{"zone": "us-east"}, nil)).To(BeTrue())
+	g.Expect(labelsMatch(map[string]string{"zone": "us-east", "app": "web"}, map[string]string{"zone": "us-east"})).To(BeTrue())
+	g.Expect(labelsMatch(map[string]string{"zone": "us-east"}, map[string]string{"zone": "us-east", "tier": "gold"})).To(BeTrue())
+	g.Expect(labelsMatch(map[string]string{"zone": "us-east", "app": "web"}, map[string]string{"zone": "us-east", "app": "db"})).To(BeFalse())
+}
+
+func TestGetEndpoint_MatchesEndpointLabels(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.MatchEndpointLabels = true
+	east := data.createEndpoint(nse1Name, remoteNSMName)
+	east.NetworkServiceEndpoint.Labels = map[string]string{"zone": "us-east", "tier": "gold"}
+	west := data.createEndpoint(nse2Name, remoteNSMName)
+	west.NetworkServiceEndpoint.Labels = map[string]string{"zone": "us-west"}
+	unlabeled := data.createEndpoint(nse3Name, remoteNSMName)
+	data.setDiscoveredEndpoints(east, west, unlabeled)
+
+	request := newTestRequestConnection()
+	request.Labels = map[string]string{"zone": "us-east"}
+	selected := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+		g.Expect(err).To(BeNil())
+		selected[endpoint.GetNetworkServiceEndpoint().GetName()] = true
+	}
+	g.Expect(selected).To(Equal(map[string]bool{nse1Name: true, nse3Name: true}))
+
+	request.Labels = map[string]string{"zone": "eu-central"}
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), request, data.ignores(unlabeled))
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+	g.Expect(endpoint).To(BeNil())
+}
@@ -0,0 +1,59 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// LatencyClassLabel - request label to ask for one of latency classes configured by properties.LatencyClasses.
+const LatencyClassLabel = "nsm/latency-class"
+
+// filterLatencyClass - drops endpoints with measured RTT above requested latency class threshold.
+// Endpoints without RTT measurements are kept since there is nothing to judge them by.
+func (nsem *nseManager) filterLatencyClass(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager) ([]*registry.NetworkServiceEndpoint, error) {
+	latencyClass := requestConnection.GetLabels()[LatencyClassLabel]
+	if latencyClass == "" || len(endpoints) == 0 {
+		return endpoints, nil
+	}
+	threshold, ok := nsem.props.LatencyClasses[latencyClass]
+	if !ok {
+		logrus.Warnf("Unknown latency class %s requested, ignoring it", latencyClass)
+		return endpoints, nil
+	}
+
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		rtt, measured := nsem.rttStore.load(registry.NewEndpointNSMName(candidate, managers[candidate.GetNetworkServiceManagerName()]))
+		if !measured || rtt <= threshold {
+			result = append(result, candidate)
+		}
+	}
+	if len(result) > 0 {
+		return result, nil
+	}
+	if nsem.props.LatencyClassRelaxed {
+		logrus.Warnf("No endpoint meets latency class %s (%v), relaxing", latencyClass, threshold)
+		return endpoints, nil
+	}
+	return nil, errors.Wrapf(ErrNoEndpointMeetsSLO, "latency class %s requires RTT <= %v, checked %d endpoints",
+		latencyClass, threshold, len(endpoints))
+}
@@ -0,0 +1,85 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func withGoldLatencyClass(data *nseManagerTestData) {
+	data.nseManager.props.LatencyClasses = map[string]time.Duration{
+		"gold": 10 * time.Millisecond,
+	}
+}
+
+func TestLatencyClass_SelectsEndpointMeetingSLO(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withGoldLatencyClass)
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+	data.nseManager.ReportRTT(nse1.GetEndpointNSMName(), 50*time.Millisecond)
+	data.nseManager.ReportRTT(nse2.GetEndpointNSMName(), 5*time.Millisecond)
+
+	for i := 0; i < 2; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(LatencyClassLabel, "gold")), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestLatencyClass_NoEndpointMeetsSLO(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withGoldLatencyClass)
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1)
+	data.nseManager.ReportRTT(nse1.GetEndpointNSMName(), 50*time.Millisecond)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(LatencyClassLabel, "gold")), nil)
+	g.Expect(errors.Is(err, ErrNoEndpointMeetsSLO)).To(BeTrue())
+}
+
+func TestLatencyClass_Relaxed(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withGoldLatencyClass)
+	data.nseManager.props.LatencyClassRelaxed = true
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1)
+	data.nseManager.ReportRTT(nse1.GetEndpointNSMName(), 50*time.Millisecond)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(LatencyClassLabel, "gold")), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestLatencyClass_RTTNotStoredWithoutClasses(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+
+	data.nseManager.ReportRTT(nse1.GetEndpointNSMName(), 50*time.Millisecond)
+	_, measured := data.nseManager.rttStore.load(nse1.GetEndpointNSMName())
+	g.Expect(measured).To(BeFalse())
+}
+
+func TestLatencyClass_RTTDroppedWithDeletedEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withGoldLatencyClass, withLocalEndpoints(nse1Name))
+	nse1 := data.endpoints[0]
+
+	data.nseManager.ReportRTT(nse1.GetEndpointNSMName(), 50*time.Millisecond)
+	_, measured := data.nseManager.rttStore.load(nse1.GetEndpointNSMName())
+	g.Expect(measured).To(BeTrue())
+
+	data.model.DeleteEndpoint(context.Background(), nse1Name)
+	g.Eventually(func() bool {
+		_, measured := data.nseManager.rttStore.load(nse1.GetEndpointNSMName())
+		return measured
+	}).Should(BeFalse())
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+type cachedEndpoint struct {
+	endpoint *model.Endpoint
+	expires  time.Time
+}
+
+// localEndpointCache - short-lived cache of local endpoints looked up in model by name. Entry is evicted when model
+// notifies endpoint is changed, and right away when endpoint is deleted by nseManager itself.
+type localEndpointCache struct {
+	model.ListenerImpl
+	sync.RWMutex
+	entries map[string]cachedEndpoint
+	// generation - changes on every eviction, so endpoint loaded before eviction is not cached after it.
+	generation uint64
+}
+
+func newLocalEndpointCache(m model.Model) *localEndpointCache {
+	cache := &localEndpointCache{
+		entries: map[string]cachedEndpoint{},
+	}
+	m.AddListener(cache)
+	return cache
+}
+
+func (c *localEndpointCache) get(name string) (*model.Endpoint, uint64, bool) {
+	c.RLock()
+	defer c.RUnlock()
+	entry, ok := c.entries[name]
+	if !ok || !time.Now().Before(entry.expires) {
+		return nil, c.generation, false
+	}
+	return entry.endpoint, c.generation, true
+}
+
+// put - caches endpoint loaded at generation, unless something was evicted since then.
+func (c *localEndpointCache) put(name string, endpoint *model.Endpoint, generation uint64, ttl time.Duration) {
+	c.Lock()
+	defer c.Unlock()
+	if c.generation == generation {
+		c.entries[name] = cachedEndpoint{endpoint: endpoint, expires: time.Now().Add(ttl)}
+	}
+}
+
+func (c *localEndpointCache) evict(name string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, name)
+	c.generation++
+}
+
+func (c *localEndpointCache) EndpointAdded(_ context.Context, endpoint *model.Endpoint) {
+	c.evict(endpoint.EndpointName())
+}
+
+func (c *localEndpointCache) EndpointUpdated(_ context.Context, endpoint *model.Endpoint) {
+	c.evict(endpoint.EndpointName())
+}
+
+func (c *localEndpointCache) EndpointDeleted(_ context.Context, endpoint *model.Endpoint) {
+	c.evict(endpoint.EndpointName())
+}
+
+// localEndpoint - returns local endpoint by name from model, cached for properties.LocalEndpointCacheTTL.
+func (nsem *nseManager) localEndpoint(name string) *model.Endpoint {
+	ttl := nsem.props.LocalEndpointCacheTTL
+	if ttl <= 0 {
+		return nsem.model.GetEndpoint(name)
+	}
+	endpoint, generation, ok := nsem.localEndpoints.get(name)
+	if ok {
+		return endpoint
+	}
+	if endpoint = nsem.model.GetEndpoint(name); endpoint != nil {
+		nsem.localEndpoints.put(name, endpoint, generation, ttl)
+	}
+	return endpoint
+}
@@ -0,0 +1,128 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// countingModel - model counting endpoint lookups.
+type countingModel struct {
+	model.Model
+	lookups int64
+}
+
+func (m *countingModel) GetEndpoint(name string) *model.Endpoint {
+	atomic.AddInt64(&m.lookups, 1)
+	return m.Model.GetEndpoint(name)
+}
+
+func (m *countingModel) lookupCount() int64 {
+	return atomic.LoadInt64(&m.lookups)
+}
+
+func withLocalEndpointCache(data *nseManagerTestData) {
+	data.nseManager.props.LocalEndpointCacheTTL = time.Second
+	data.nseManager.model = &countingModel{Model: data.model}
+}
+
+// awaitLocalEndpointCached - waits for the addition of nse-1 to reach the cache, so it does not evict entries cached by test.
+func (data *nseManagerTestData) awaitLocalEndpointCached(g *WithT) {
+	g.Eventually(func() uint64 {
+		_, generation, _ := data.nseManager.localEndpoints.get(nse1Name)
+		return generation
+	}).Should(Equal(uint64(1)))
+}
+
+func TestLocalEndpointCache(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpointCache, withLocalEndpoints(nse1Name))
+	data.awaitLocalEndpointCached(g)
+	counting := data.nseManager.model.(*countingModel)
+
+	for i := 0; i < 3; i++ {
+		g.Expect(data.nseManager.localEndpoint(nse1Name).EndpointName()).To(Equal(nse1Name))
+	}
+	g.Expect(counting.lookupCount()).To(Equal(int64(1)))
+
+	g.Expect(data.nseManager.localEndpoint(nse2Name)).To(BeNil())
+	g.Expect(data.nseManager.localEndpoint(nse2Name)).To(BeNil())
+	g.Expect(counting.lookupCount()).To(Equal(int64(3)))
+}
+
+func TestLocalEndpointCache_EvictedOnCleanup(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpointCache, withLocalEndpoints(nse1Name))
+	data.awaitLocalEndpointCached(g)
+
+	endpoint := data.nseManager.localEndpoint(nse1Name)
+	g.Expect(endpoint).NotTo(BeNil())
+	data.nseManager.cleanupNSE(context.Background(), endpoint)
+	g.Expect(data.nseManager.localEndpoint(nse1Name)).To(BeNil())
+}
+
+func TestLocalEndpointCache_EvictedOnModelChange(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpointCache, withLocalEndpoints(nse1Name))
+	data.awaitLocalEndpointCached(g)
+
+	g.Expect(data.nseManager.localEndpoint(nse1Name).Workspace).To(BeEmpty())
+	updated := data.model.GetEndpoint(nse1Name)
+	updated.Workspace = "updated"
+	data.model.UpdateEndpoint(context.Background(), updated)
+	g.Eventually(func() string {
+		return data.nseManager.localEndpoint(nse1Name).Workspace
+	}).Should(Equal("updated"))
+}
+
+func TestLocalEndpointCache_Expires(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpointCache, withLocalEndpoints(nse1Name))
+	data.awaitLocalEndpointCached(g)
+	counting := data.nseManager.model.(*countingModel)
+	data.nseManager.props.LocalEndpointCacheTTL = 10 * time.Millisecond
+
+	data.nseManager.localEndpoint(nse1Name)
+	<-time.After(20 * time.Millisecond)
+	data.nseManager.localEndpoint(nse1Name)
+	g.Expect(counting.lookupCount()).To(Equal(int64(2)))
+}
+
+func TestLocalEndpointCache_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpointCache, withLocalEndpoints(nse1Name))
+	data.awaitLocalEndpointCached(g)
+	counting := data.nseManager.model.(*countingModel)
+	data.nseManager.props.LocalEndpointCacheTTL = 0
+
+	data.nseManager.localEndpoint(nse1Name)
+	data.nseManager.localEndpoint(nse1Name)
+	g.Expect(counting.lookupCount()).To(Equal(int64(2)))
+}
+
+func BenchmarkLocalEndpointLookup(b *testing.B) {
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		b.Run(fmt.Sprintf("ttl=%v", ttl), func(b *testing.B) {
+			data := newNseManagerTestData()
+			data.nseManager.props.LocalEndpointCacheTTL = ttl
+			for i := 0; i < 64; i++ {
+				registration := data.createEndpoint(fmt.Sprintf("nse-%d", i), localNSMName)
+				data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: registration})
+			}
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					data.nseManager.localEndpoint(fmt.Sprintf("nse-%d", i%64))
+					i++
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// drainedEndpoints - local endpoints drained by DrainEndpoint, endpoint identity by endpoint name. Endpoint is
+// forgotten once it is deleted from model.
+type drainedEndpoints struct {
+	model.ListenerImpl
+	sync.RWMutex
+	keys map[string]string
+}
+
+func newDrainedEndpoints(m model.Model) *drainedEndpoints {
+	drained := &drainedEndpoints{
+		keys: map[string]string{},
+	}
+	m.AddListener(drained)
+	return drained
+}
+
+func (d *drainedEndpoints) add(name, key string) {
+	d.Lock()
+	defer d.Unlock()
+	d.keys[name] = key
+}
+
+func (d *drainedEndpoints) remove(name string) {
+	d.Lock()
+	defer d.Unlock()
+	delete(d.keys, name)
+}
+
+func (d *drainedEndpoints) contains(key string) bool {
+	d.RLock()
+	defer d.RUnlock()
+	for _, drainedKey := range d.keys {
+		if drainedKey == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *drainedEndpoints) names() []string {
+	d.RLock()
+	defer d.RUnlock()
+	names := make([]string, 0, len(d.keys))
+	for name := range d.keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (d *drainedEndpoints) EndpointDeleted(_ context.Context, endpoint *model.Endpoint) {
+	d.remove(endpoint.EndpointName())
+}
+
+// DrainEndpoint - stops selecting local endpoint for new connections, while connections already routed to it,
+// including healed and targeted ones, are still served until it is deleted from model. Drain notifier is notified
+// with connections to drain.
+func (nsem *nseManager) DrainEndpoint(name string) error {
+	endpoint := nsem.localEndpoint(name)
+	if endpoint == nil {
+		return errors.Wrapf(ErrLocalEndpointNotFound, "failed to drain endpoint %s", name)
+	}
+	logrus.Infof("NSM: Drain Endpoint... %v", endpoint)
+	nsem.drained.add(name, nsem.localEndpointKey(endpoint))
+	nsem.observeDraining(endpoint.Endpoint.GetNetworkServiceEndpoint(), endpoint.Endpoint.GetNetworkServiceManager(), true)
+	return nil
+}
+
+// DrainingEndpoints - returns names of local endpoints being drained, sorted.
+func (nsem *nseManager) DrainingEndpoints() []string {
+	return nsem.drained.names()
+}
@@ -0,0 +1,44 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestDrainEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name))
+	drained := data.endpoints[0]
+
+	g.Expect(data.nseManager.DrainEndpoint(nse1Name)).To(BeNil())
+	g.Expect(data.nseManager.DrainingEndpoints()).To(Equal([]string{nse1Name}))
+	g.Expect(data.selectedNames(3)).To(Equal([]string{nse2Name, nse2Name, nse2Name}))
+
+	// Connections already routed to drained endpoint are still served.
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, localNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	_, err = data.nseManager.CreateNSEClient(context.Background(), drained)
+	g.Expect(err).To(BeNil())
+}
+
+func TestDrainEndpoint_ForgottenOnDelete(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name))
+
+	g.Expect(data.nseManager.DrainEndpoint(nse1Name)).To(BeNil())
+	data.model.DeleteEndpoint(context.Background(), nse1Name)
+	g.Eventually(data.nseManager.DrainingEndpoints).Should(BeEmpty())
+}
+
+func TestDrainEndpoint_NotFound(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name))
+
+	err := data.nseManager.DrainEndpoint(nse3Name)
+	g.Expect(errors.Is(err, ErrLocalEndpointNotFound)).To(BeTrue())
+	g.Expect(data.nseManager.DrainingEndpoints()).To(BeEmpty())
+}
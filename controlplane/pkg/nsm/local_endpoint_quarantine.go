@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// endpointFailures - consecutive connection failures of local endpoints keyed by endpoint identity, zero value is
+// ready to use.
+type endpointFailures struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+// add - counts failure of endpoint, returns how many times in a row it failed.
+func (f *endpointFailures) add(key string) int {
+	f.Lock()
+	defer f.Unlock()
+	if f.counts == nil {
+		f.counts = map[string]int{}
+	}
+	f.counts[key]++
+	return f.counts[key]
+}
+
+func (f *endpointFailures) reset(key string) {
+	f.Lock()
+	defer f.Unlock()
+	delete(f.counts, key)
+}
+
+func (nsem *nseManager) localEndpointKey(endpoint *model.Endpoint) string {
+	return nsem.identity.Key(endpoint.Endpoint.GetNetworkServiceEndpoint(), endpoint.Endpoint.GetNetworkServiceManager())
+}
+
+// localEndpointFailed - handles failure to connect to local endpoint: removes endpoint from model right away, or,
+// with properties.LocalEndpointQuarantine, excludes it from selection for a while and removes it once it fails
+// properties.LocalEndpointFailureThreshold times in a row.
+func (nsem *nseManager) localEndpointFailed(ctx context.Context, endpoint *model.Endpoint) {
+	if nsem.props.LocalEndpointQuarantine <= 0 {
+		nsem.cleanupNSE(ctx, endpoint)
+		return
+	}
+	key := nsem.localEndpointKey(endpoint)
+	if failures := nsem.localFailures.add(key); failures < nsem.props.LocalEndpointFailureThreshold {
+		logrus.Infof("NSM: Quarantine Endpoint after %d failures... %v", failures, endpoint)
+		nsem.localQuarantine.add(key, nsem.props.LocalEndpointQuarantine)
+		nsem.observeReachability(endpoint.Endpoint, ReachabilityUnreachable)
+		return
+	}
+	nsem.localFailures.reset(key)
+	nsem.localQuarantine.remove(key)
+	nsem.cleanupNSE(ctx, endpoint)
+}
+
+// localEndpointConnected - forgets failures of local endpoint connected to.
+func (nsem *nseManager) localEndpointConnected(endpoint *model.Endpoint) {
+	if nsem.props.LocalEndpointQuarantine <= 0 {
+		return
+	}
+	key := nsem.localEndpointKey(endpoint)
+	nsem.localFailures.reset(key)
+	nsem.localQuarantine.remove(key)
+}
@@ -0,0 +1,68 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+// withLocalQuarantine - quarantines local endpoints connections to which fail.
+func withLocalQuarantine(quarantine time.Duration) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.props.LocalEndpointQuarantine = quarantine
+		data.nseManager.props.LocalEndpointFailureThreshold = 2
+		data.serviceRegistry.endpointError = errors.New("connection refused")
+	}
+}
+
+func TestLocalEndpointQuarantine(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name), withLocalQuarantine(time.Hour))
+	failed := data.endpoints[0]
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.model.GetEndpoint(nse1Name)).NotTo(BeNil())
+	g.Expect(data.selectedNames(3)).To(Equal([]string{nse2Name, nse2Name, nse2Name}))
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, localNSMName), nil)
+	g.Expect(errors.Is(err, ErrLocalEndpointNotFound)).To(BeTrue())
+
+	_, err = data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.model.GetEndpoint(nse1Name)).To(BeNil())
+}
+
+func TestLocalEndpointQuarantine_Expires(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name), withLocalQuarantine(50*time.Millisecond))
+	failed := data.endpoints[0]
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.selectedNames(2)).NotTo(ContainElement(nse1Name))
+
+	<-time.After(100 * time.Millisecond)
+	g.Expect(data.selectedNames(2)).To(ContainElement(nse1Name))
+
+	// Successful connection forgets failures.
+	data.serviceRegistry.endpointError = nil
+	_, err = data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).To(BeNil())
+	data.serviceRegistry.endpointError = errors.New("connection refused")
+	_, err = data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.model.GetEndpoint(nse1Name)).NotTo(BeNil())
+}
+
+func TestLocalEndpointQuarantine_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name), withLocalQuarantine(0))
+	failed := data.endpoints[0]
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), failed)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.model.GetEndpoint(nse1Name)).To(BeNil())
+}
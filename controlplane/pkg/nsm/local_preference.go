@@ -0,0 +1,39 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// preferLocal - keeps only endpoints hosted by local NSM if there are any, see properties.PreferLocalEndpoints.
+func (nsem *nseManager) preferLocal(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	localNsm := nsem.model.GetNsm()
+	if !nsem.props.PreferLocalEndpoints || localNsm == nil {
+		return endpoints
+	}
+	local := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if candidate.GetNetworkServiceManagerName() == localNsm.GetName() {
+			local = append(local, candidate)
+		}
+	}
+	if len(local) == 0 {
+		return endpoints
+	}
+	return local
+}
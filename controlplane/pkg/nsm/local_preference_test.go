@@ -0,0 +1,31 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestPreferLocalEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.PreferLocalEndpoints = true
+	remote := data.createEndpoint(nse1Name, remoteNSMName)
+	local1 := data.createEndpoint(nse2Name, localNSMName)
+	local2 := data.createEndpoint(nse3Name, localNSMName)
+	data.setDiscoveredEndpoints(remote, local1, local2)
+
+	selected := map[string]bool{}
+	for i := 0; i < 4; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		selected[endpoint.GetNetworkServiceEndpoint().GetName()] = true
+	}
+	g.Expect(selected).To(Equal(map[string]bool{nse2Name: true, nse3Name: true}))
+
+	// Remote endpoint is selected when local ones are ignored.
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(local1, local2))
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
@@ -0,0 +1,56 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strings"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// AllowedManagersLabel - request label with comma separated names of trusted NSMgrs, only endpoints hosted by them
+// are selected. Request without the label is not restricted.
+const AllowedManagersLabel = "nsm/allowed-managers"
+
+func allowedManagers(requestConnection *connection.Connection) map[string]bool {
+	var result map[string]bool
+	for _, name := range strings.Split(requestConnection.GetLabels()[AllowedManagersLabel], ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			if result == nil {
+				result = map[string]bool{}
+			}
+			result[name] = true
+		}
+	}
+	return result
+}
+
+// filterAllowedManagers - drops endpoints hosted by NSMgrs not allowed by request.
+func filterAllowedManagers(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	allowed := allowedManagers(requestConnection)
+	if len(allowed) == 0 {
+		return endpoints
+	}
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if allowed[candidate.GetNetworkServiceManagerName()] {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestAllowedManagers(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSpreadEndpoints(nse1Name, nse2Name, nse3Name))
+
+	for i := 0; i < 4; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(AllowedManagersLabel, "nsm-2, nsm-3")), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceManager().GetName()).To(BeElementOf("nsm-2", "nsm-3"))
+	}
+}
+
+func TestAllowedManagers_Empty(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSpreadEndpoints(nse1Name, nse2Name, nse3Name))
+
+	g.Expect(data.selectedNames(3)).To(ConsistOf(nse1Name, nse2Name, nse3Name))
+	var names []string
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(AllowedManagersLabel, " , ")), nil)
+		g.Expect(err).To(BeNil())
+		names = append(names, endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	g.Expect(names).To(ConsistOf(nse1Name, nse2Name, nse3Name))
+}
+
+func TestAllowedManagers_ExcludesAll(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSpreadEndpoints(nse1Name, nse2Name, nse3Name))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(AllowedManagersLabel, "nsm-untrusted")), nil)
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+	g.Expect(err.Error()).To(HavePrefix("failed to find NSE for NetworkService " + networkServiceName))
+}
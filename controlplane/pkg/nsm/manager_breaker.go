@@ -0,0 +1,78 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+)
+
+type managerCircuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// managerBreakers - circuit breakers of remote network service managers keyed by manager name, zero value is ready
+// to use. Circuit of manager opens after threshold consecutive dial failures, while it is open dials are not
+// attempted. Once cooldown passes a single probe dial is allowed, its success closes the circuit and its failure
+// opens it again.
+type managerBreakers struct {
+	sync.Mutex
+	circuits map[string]*managerCircuit
+}
+
+// allow - tells if manager could be dialed, takes the probe of half-open circuit.
+func (b *managerBreakers) allow(manager string) bool {
+	b.Lock()
+	defer b.Unlock()
+	circuit, ok := b.circuits[manager]
+	if !ok || circuit.openUntil.IsZero() {
+		return true
+	}
+	if circuit.probing || time.Now().Before(circuit.openUntil) {
+		return false
+	}
+	circuit.probing = true
+	return true
+}
+
+// success - closes circuit of manager.
+func (b *managerBreakers) success(manager string) {
+	b.Lock()
+	defer b.Unlock()
+	delete(b.circuits, manager)
+}
+
+// failure - counts dial failure of manager, opens its circuit for cooldown once threshold is reached or probe fails.
+func (b *managerBreakers) failure(manager string, threshold int, cooldown time.Duration) {
+	b.Lock()
+	defer b.Unlock()
+	if b.circuits == nil {
+		b.circuits = map[string]*managerCircuit{}
+	}
+	circuit, ok := b.circuits[manager]
+	if !ok {
+		circuit = &managerCircuit{}
+		b.circuits[manager] = circuit
+	}
+	circuit.failures++
+	if circuit.probing || circuit.failures >= threshold {
+		circuit.openUntil = time.Now().Add(cooldown)
+		circuit.probing = false
+	}
+}
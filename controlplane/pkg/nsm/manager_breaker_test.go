@@ -0,0 +1,80 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func withManagerBreaker(data *nseManagerTestData) {
+	data.nseManager.props.ManagerBreakerThreshold = 2
+	data.nseManager.props.ManagerBreakerCooldown = 50 * time.Millisecond
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+}
+
+func (data *nseManagerTestData) createRemoteClient() error {
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	return err
+}
+
+func TestManagerBreaker_Opens(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withManagerBreaker)
+
+	g.Expect(data.createRemoteClient()).To(Equal(data.serviceRegistry.remoteClientError))
+	g.Expect(data.createRemoteClient()).To(Equal(data.serviceRegistry.remoteClientError))
+	dials := len(data.serviceRegistry.remoteDials)
+
+	err := data.createRemoteClient()
+	g.Expect(errors.Is(err, ErrManagerCircuitOpen)).To(BeTrue())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(dials))
+}
+
+func TestManagerBreaker_ProbeFails(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withManagerBreaker)
+	data.nseManager.props.ManagerBreakerThreshold = 1
+
+	g.Expect(data.createRemoteClient()).NotTo(BeNil())
+	<-time.After(100 * time.Millisecond)
+	g.Expect(data.createRemoteClient()).To(Equal(data.serviceRegistry.remoteClientError))
+	g.Expect(errors.Is(data.createRemoteClient(), ErrManagerCircuitOpen)).To(BeTrue())
+}
+
+func TestManagerBreaker_ProbeSucceeds(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withManagerBreaker)
+
+	g.Expect(data.createRemoteClient()).NotTo(BeNil())
+	g.Expect(data.createRemoteClient()).NotTo(BeNil())
+	g.Expect(errors.Is(data.createRemoteClient(), ErrManagerCircuitOpen)).To(BeTrue())
+
+	<-time.After(100 * time.Millisecond)
+	data.serviceRegistry.remoteClientError = nil
+	g.Expect(data.createRemoteClient()).To(BeNil())
+	g.Expect(data.createRemoteClient()).To(BeNil())
+}
+
+func TestManagerBreakers_SingleProbe(t *testing.T) {
+	g := NewWithT(t)
+	breakers := managerBreakers{}
+
+	g.Expect(breakers.allow(remoteNSMName)).To(BeTrue())
+	breakers.failure(remoteNSMName, 1, 0)
+	g.Expect(breakers.allow(remoteNSMName)).To(BeTrue())
+	g.Expect(breakers.allow(remoteNSMName)).To(BeFalse())
+	g.Expect(breakers.allow(localNSMName)).To(BeTrue())
+
+	breakers.success(remoteNSMName)
+	g.Expect(breakers.allow(remoteNSMName)).To(BeTrue())
+	g.Expect(breakers.allow(remoteNSMName)).To(BeTrue())
+
+	// Closed circuit counts failures from scratch.
+	breakers.failure(remoteNSMName, 2, time.Hour)
+	g.Expect(breakers.allow(remoteNSMName)).To(BeTrue())
+	breakers.failure(remoteNSMName, 2, time.Hour)
+	g.Expect(breakers.allow(remoteNSMName)).To(BeFalse())
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// validateManagerReferences - cross-checks discovered managers against endpoints referencing them. Orphaned managers,
+// not referenced by any endpoint, are logged. Endpoints referencing absent managers can not be connected to and are
+// dropped from the returned response with a warning, endpointResponse is not modified.
+func validateManagerReferences(span spanhelper.SpanHelper, endpointResponse *registry.FindNetworkServiceResponse) *registry.FindNetworkServiceResponse {
+	managers := endpointResponse.GetNetworkServiceManagers()
+	referenced := map[string]bool{}
+	var endpoints []*registry.NetworkServiceEndpoint
+	var dangling []string
+	for _, endpoint := range endpointResponse.GetNetworkServiceEndpoints() {
+		if _, ok := managers[endpoint.GetNetworkServiceManagerName()]; !ok {
+			dangling = append(dangling, endpoint.GetName()+"@"+endpoint.GetNetworkServiceManagerName())
+			continue
+		}
+		referenced[endpoint.GetNetworkServiceManagerName()] = true
+		endpoints = append(endpoints, endpoint)
+	}
+	var orphaned []string
+	for name := range managers {
+		if !referenced[name] {
+			orphaned = append(orphaned, name)
+		}
+	}
+	sort.Strings(orphaned)
+	if len(orphaned) > 0 {
+		logrus.Infof("Network service %s discovery returned managers not referenced by any endpoint: %v", endpointResponse.GetNetworkService().GetName(), orphaned)
+	}
+	span.LogValue("referencedManagers", len(referenced))
+	span.LogValue("orphanedManagers", len(orphaned))
+	span.LogValue("danglingEndpoints", len(dangling))
+	if len(dangling) == 0 {
+		return endpointResponse
+	}
+	logrus.Warnf("Network service %s discovery returned %d endpoints referencing absent network service managers, dropping them: %v",
+		endpointResponse.GetNetworkService().GetName(), len(dangling), dangling)
+	return &registry.FindNetworkServiceResponse{
+		Payload:                 endpointResponse.GetPayload(),
+		NetworkService:          endpointResponse.GetNetworkService(),
+		NetworkServiceManagers:  managers,
+		NetworkServiceEndpoints: endpoints,
+	}
+}
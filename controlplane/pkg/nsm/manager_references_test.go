@@ -0,0 +1,118 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+func TestValidateManagerReferences_OrphanedManagers(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	response := data.createFindNetworkServiceResponse(data.createEndpoint(nse1Name, remoteNSMName))
+	response.NetworkServiceManagers["nsm-orphaned"] = &registry.NetworkServiceManager{Name: "nsm-orphaned"}
+
+	span := spanhelper.FromContext(context.Background(), "test")
+	defer span.Finish()
+	g.Expect(validateManagerReferences(span, response)).To(BeIdenticalTo(response))
+}
+
+func TestValidateManagerReferences_DanglingEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	response := data.createFindNetworkServiceResponse(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, "nsm-absent"),
+	)
+	delete(response.NetworkServiceManagers, "nsm-absent")
+
+	span := spanhelper.FromContext(context.Background(), "test")
+	defer span.Finish()
+	validated := validateManagerReferences(span, response)
+	g.Expect(validated.GetNetworkServiceEndpoints()).To(HaveLen(1))
+	g.Expect(validated.GetNetworkServiceEndpoints()[0].GetName()).To(Equal(nse1Name))
+	g.Expect(response.GetNetworkServiceEndpoints()).To(HaveLen(2))
+}
+
+func TestGetEndpoint_SkipsEndpointsReferencingAbsentManagers(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, "nsm-absent"), data.createEndpoint(nse2Name, remoteNSMName))
+	delete(data.serviceRegistry.discoveryClient.response.NetworkServiceManagers, "nsm-absent")
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+		g.Expect(endpoint.GetNetworkServiceManager().GetName()).To(Equal(remoteNSMName))
+	}
+}
+
+func TestFilterEndpoints_DanglingManagerReference(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	response := data.createFindNetworkServiceResponse(
+		data.createEndpoint(nse1Name, "nsm-absent"),
+		data.createEndpoint(nse2Name, remoteNSMName),
+	)
+	delete(response.NetworkServiceManagers, "nsm-absent")
+
+	result, err := data.nseManager.filterEndpoints(newTestRequestConnection(), response.GetNetworkServiceEndpoints(), response.GetNetworkServiceManagers(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpointNames(result)).To(Equal([]string{nse2Name}))
+}
+
+func TestGetEndpoint_TargetReferencingAbsentManager(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, "nsm-absent"), data.createEndpoint(nse2Name, remoteNSMName))
+	delete(data.serviceRegistry.discoveryClient.response.NetworkServiceManagers, "nsm-absent")
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, "nsm-absent"), nil)
+	g.Expect(errors.Is(err, ErrTargetEndpointNotFound)).To(BeTrue())
+}
+
+func TestGetEndpoint_AllEndpointsReferenceAbsentManagers(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, "nsm-absent"), data.createEndpoint(nse2Name, "nsm-gone"))
+	data.serviceRegistry.discoveryClient.response.NetworkServiceManagers = map[string]*registry.NetworkServiceManager{}
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrInconsistentManagers)).To(BeTrue())
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeFalse())
+	g.Expect(err.Error()).To(ContainSubstring("all 2 discovered endpoints of NetworkService " + networkServiceName))
+}
+
+func TestGetEndpoint_NoEndpointsDiscoveredIsNotInconsistent(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints()
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeTrue())
+	g.Expect(errors.Is(err, ErrInconsistentManagers)).To(BeFalse())
+}
+
+func TestGetEndpoint_InconsistentManagersNotCached(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.DiscoveryCacheTTL = time.Hour
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+	managers := data.serviceRegistry.discoveryClient.response.NetworkServiceManagers
+	data.serviceRegistry.discoveryClient.response.NetworkServiceManagers = nil
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrInconsistentManagers)).To(BeTrue())
+
+	data.serviceRegistry.discoveryClient.response.NetworkServiceManagers = managers
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// NetworkServiceManagerResolver - resolves network service manager remote endpoint is dialed through, e.g. to
+// rewrite advertised manager URL to a gateway, proxy or NAT mapping without changing registry contents.
+type NetworkServiceManagerResolver interface {
+	Resolve(endpoint *registry.NSERegistration) (*registry.NetworkServiceManager, error)
+}
+
+// registrationManagerResolver - default resolver, dials manager of registration as advertised.
+type registrationManagerResolver struct{}
+
+func (registrationManagerResolver) Resolve(endpoint *registry.NSERegistration) (*registry.NetworkServiceManager, error) {
+	return endpoint.GetNetworkServiceManager(), nil
+}
+
+// WithNetworkServiceManagerResolver - dial remote endpoints through managers returned by resolver.
+func WithNetworkServiceManagerResolver(resolver NetworkServiceManagerResolver) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.managerResolver = resolver
+	}
+}
@@ -0,0 +1,94 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type gatewayResolverStub struct {
+	gateway string
+	err     error
+}
+
+func (r *gatewayResolverStub) Resolve(endpoint *registry.NSERegistration) (*registry.NetworkServiceManager, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	manager := *endpoint.GetNetworkServiceManager()
+	manager.Url = r.gateway
+	return &manager, nil
+}
+
+func TestManagerResolver_RewritesDialURL(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	WithNetworkServiceManagerResolver(&gatewayResolverStub{gateway: "gateway:5001"})(data.nseManager)
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+	advertised := endpoint.GetNetworkServiceManager().GetUrl()
+
+	client, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).To(BeNil())
+	g.Expect(client).NotTo(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+	g.Expect(data.serviceRegistry.remoteDials[0].GetUrl()).To(Equal("gateway:5001"))
+	g.Expect(endpoint.GetNetworkServiceManager().GetUrl()).To(Equal(advertised))
+}
+
+func TestManagerResolver_Default(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(Equal([]*registry.NetworkServiceManager{endpoint.GetNetworkServiceManager()}))
+}
+
+func TestManagerResolver_Error(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	WithNetworkServiceManagerResolver(&gatewayResolverStub{err: errors.New("no route")})(data.nseManager)
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("no route"))
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
+
+const localNSMURL = "10.0.0.1:5001"
+
+func withLocalManagerURL(data *nseManagerTestData) {
+	data.model.SetNsm(&registry.NetworkServiceManager{Name: localNSMName, Url: localNSMURL})
+}
+
+func TestManagerResolver_ResolvedToSelf(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalManagerURL, withLocalEndpoints(nse1Name))
+	// Local NSM advertised under an alias.
+	endpoint := data.createEndpoint(nse1Name, "nsm-alias")
+	WithNetworkServiceManagerResolver(&gatewayResolverStub{gateway: localNSMURL})(data.nseManager)
+
+	g.Expect(data.nseManager.IsLocalEndpoint(endpoint)).To(BeFalse())
+	client, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).To(BeNil())
+	g.Expect(client).To(BeAssignableToTypeOf(&endpointClient{}))
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
+
+func TestManagerResolver_AdvertisedWithLocalURL(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalManagerURL, withLocalEndpoints(nse1Name))
+	// Local NSM advertised under an alias.
+	endpoint := data.createEndpoint(nse1Name, "nsm-alias")
+	endpoint.NetworkServiceManager.Url = localNSMURL
+
+	g.Expect(data.nseManager.IsLocalEndpoint(endpoint)).To(BeTrue())
+	_, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// propertiesLocator - locates NSMgrs by properties.ManagerLocations.
+type propertiesLocator struct {
+	nsem *nseManager
+}
+
+func (l propertiesLocator) LocalLocation() (selector.Location, bool) {
+	localNsm := l.nsem.model.GetNsm()
+	if localNsm == nil {
+		return selector.Location{}, false
+	}
+	return l.Location(localNsm.GetName())
+}
+
+func (l propertiesLocator) Location(managerName string) (selector.Location, bool) {
+	value, ok := l.nsem.props.ManagerLocations[managerName]
+	if !ok {
+		return selector.Location{}, false
+	}
+	location, ok := parseLocation(value)
+	if !ok {
+		logrus.Warnf("NSMgr %s has malformed location %q, ignoring it", managerName, value)
+	}
+	return location, ok
+}
+
+// parseLocation - parses location formatted as region or region/zone.
+func parseLocation(value string) (selector.Location, bool) {
+	parts := strings.Split(value, "/")
+	if len(parts) > 2 || parts[0] == "" {
+		return selector.Location{}, false
+	}
+	location := selector.Location{Region: parts[0]}
+	if len(parts) == 2 {
+		location.Zone = parts[1]
+	}
+	return location, true
+}
@@ -0,0 +1,83 @@
+package nsm
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// withManagerTopology - discovers endpoints on managers in the same zone, region and elsewhere.
+func withManagerTopology(locations map[string]string) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.props.TopologyAwareSelection = true
+		data.nseManager.props.ManagerLocations = locations
+		data.setDiscoveredEndpoints(
+			data.createEndpoint(nse1Name, "nsm-zone"),
+			data.createEndpoint(nse2Name, "nsm-region"),
+			data.createEndpoint(nse3Name, "nsm-remote"))
+	}
+}
+
+func TestManagerTopology_DistanceTiers(t *testing.T) {
+	locations := map[string]string{
+		localNSMName: "eu/eu-1",
+		"nsm-zone":   "eu/eu-1",
+		"nsm-region": "eu/eu-2",
+		"nsm-remote": "us/us-1",
+	}
+	for _, test := range []struct {
+		name     string
+		absent   []string
+		expected string
+	}{
+		{name: "same zone", expected: nse1Name},
+		{name: "same region", absent: []string{"nsm-zone"}, expected: nse2Name},
+		{name: "cross region", absent: []string{"nsm-zone", "nsm-region"}, expected: nse3Name},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			g := NewWithT(t)
+			tierLocations := map[string]string{}
+			for name, location := range locations {
+				tierLocations[name] = location
+			}
+			for _, name := range test.absent {
+				// Endpoints of managers with unknown location are the farthest.
+				delete(tierLocations, name)
+			}
+			data := newNseManagerTestData(withManagerTopology(tierLocations))
+			g.Expect(data.selectedNames(3)).To(Equal([]string{test.expected, test.expected, test.expected}))
+		})
+	}
+}
+
+func TestManagerTopology_Unlabeled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withManagerTopology(nil))
+
+	g.Expect(data.selectedNames(3)).To(ConsistOf(nse1Name, nse2Name, nse3Name))
+}
+
+func TestManagerTopology_LocalUnlabeled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withManagerTopology(map[string]string{"nsm-zone": "eu/eu-1", "nsm-region": "eu/eu-2"}))
+
+	g.Expect(data.selectedNames(3)).To(ConsistOf(nse1Name, nse2Name, nse3Name))
+}
+
+func TestParseLocation(t *testing.T) {
+	g := NewWithT(t)
+	for value, expected := range map[string]selector.Location{
+		"eu":      {Region: "eu"},
+		"eu/eu-1": {Region: "eu", Zone: "eu-1"},
+	} {
+		location, ok := parseLocation(value)
+		g.Expect(ok).To(BeTrue())
+		g.Expect(location).To(Equal(expected))
+	}
+	for _, value := range []string{"", "/eu-1", "eu/eu-1/rack"} {
+		_, ok := parseLocation(value)
+		g.Expect(ok).To(BeFalse())
+	}
+}
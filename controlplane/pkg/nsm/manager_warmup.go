@@ -0,0 +1,67 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// WarmupManagers - dials remote managers ahead of the first request, so CreateNSEClient finds their clients already
+// pooled. Managers are dialed concurrently, each given HealRequestConnectTimeout, and returned map holds dial
+// result of each manager by its name. Warmed clients stay pooled for RemoteClientIdleTimeout like any other released
+// client, so nothing is kept if it is not positive.
+func (nsem *nseManager) WarmupManagers(ctx context.Context, managers []*registry.NetworkServiceManager) map[string]error {
+	span := spanhelper.FromContext(ctx, "WarmupManagers")
+	defer span.Finish()
+	span.LogValue("managers", len(managers))
+
+	results := make(map[string]error, len(managers))
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	for _, manager := range managers {
+		wg.Add(1)
+		go func(manager *registry.NetworkServiceManager) {
+			defer wg.Done()
+			err := nsem.warmupManager(span.Context(), manager)
+			lock.Lock()
+			defer lock.Unlock()
+			results[manager.GetName()] = err
+		}(manager)
+	}
+	wg.Wait()
+
+	for name, err := range results {
+		if err != nil {
+			span.Logger().Warnf("Failed to warm up NSMgr %s: %v", name, err)
+		}
+	}
+	return results
+}
+
+func (nsem *nseManager) warmupManager(ctx context.Context, manager *registry.NetworkServiceManager) error {
+	ctx, cancel := nsem.clock.WithTimeout(ctx, nsem.props.HealRequestConnectTimeout)
+	defer cancel()
+	pooled, err := nsem.acquireRemoteClient(ctx, manager)
+	if err != nil {
+		return err
+	}
+	return nsem.remoteClients.release(pooled, nsem.props.RemoteClientIdleTimeout)
+}
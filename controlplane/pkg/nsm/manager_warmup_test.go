@@ -0,0 +1,36 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestWarmupManagers_PartialFailure(t *testing.T) {
+	g := NewWithT(t)
+	otherNSMName := "nsm-other"
+	data := newNseManagerTestData(withUnreachableManagers(otherNSMName))
+	data.nseManager.props.RemoteClientIdleTimeout = time.Minute
+
+	reachable := data.createEndpoint(nse1Name, remoteNSMName)
+	unreachable := data.createEndpoint(nse2Name, otherNSMName)
+	results := data.nseManager.WarmupManagers(context.Background(), []*registry.NetworkServiceManager{
+		reachable.GetNetworkServiceManager(), unreachable.GetNetworkServiceManager(),
+	})
+	g.Expect(results).To(HaveLen(2))
+	g.Expect(results[remoteNSMName]).To(BeNil())
+	g.Expect(results[otherNSMName]).NotTo(BeNil())
+
+	// Warmed client is reused, failed manager is dialed again.
+	_, err := data.nseManager.CreateNSEClient(context.Background(), reachable)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.dialCount(remoteNSMName)).To(Equal(1))
+
+	_, err = data.nseManager.CreateNSEClient(context.Background(), unreachable)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.serviceRegistry.dialCount(otherNSMName)).To(Equal(2))
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strings"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// MechanismsLabel - comma separated mechanism types, e.g. VXLAN,WIREGUARD. On request it lists mechanisms client
+// accepts, on endpoint the ones endpoint supports. Endpoints supporting none of accepted mechanisms are not selected,
+// requests and endpoints without the label are compatible with anything.
+const MechanismsLabel = "nsm/mechanisms"
+
+func mechanismTypes(labels map[string]string) map[string]bool {
+	var result map[string]bool
+	for _, mechanism := range strings.Split(labels[MechanismsLabel], ",") {
+		if mechanism = strings.ToUpper(strings.TrimSpace(mechanism)); mechanism != "" {
+			if result == nil {
+				result = map[string]bool{}
+			}
+			result[mechanism] = true
+		}
+	}
+	return result
+}
+
+// mechanismsCompatible - tells if endpoint supports any of accepted mechanisms or does not advertise them.
+func mechanismsCompatible(accepted map[string]bool, endpoint *registry.NetworkServiceEndpoint) bool {
+	supported := mechanismTypes(endpoint.GetLabels())
+	if len(supported) == 0 {
+		return true
+	}
+	for mechanism := range supported {
+		if accepted[mechanism] {
+			return true
+		}
+	}
+	return false
+}
+
+// filterMechanisms - drops endpoints not supporting any mechanism accepted by request.
+func filterMechanisms(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	accepted := mechanismTypes(requestConnection.GetLabels())
+	if len(accepted) == 0 {
+		return endpoints
+	}
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if mechanismsCompatible(accepted, candidate) {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
@@ -0,0 +1,72 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func (data *nseManagerTestData) createMechanismsEndpoint(name, mechanisms string) *registry.NSERegistration {
+	endpoint := data.createEndpoint(name, remoteNSMName)
+	if mechanisms != "" {
+		endpoint.NetworkServiceEndpoint.Labels = map[string]string{MechanismsLabel: mechanisms}
+	}
+	return endpoint
+}
+
+func (data *nseManagerTestData) selectedWith(requestConnection *connection.Connection, n int) ([]string, error) {
+	var names []string
+	for i := 0; i < n; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+		if err != nil {
+			return names, err
+		}
+		names = append(names, endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	return names, nil
+}
+
+func TestFilterMechanisms_IncompatibleOnly(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createMechanismsEndpoint(nse1Name, "VXLAN"),
+		data.createMechanismsEndpoint(nse2Name, "SRV6,VXLAN"))
+
+	_, err := data.selectedWith(newTestRequestConnection(withLabel(MechanismsLabel, "WIREGUARD")), 1)
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+}
+
+func TestFilterMechanisms_CompatibleOnly(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createMechanismsEndpoint(nse1Name, "VXLAN"),
+		data.createMechanismsEndpoint(nse2Name, "wireguard, VXLAN"))
+
+	names, err := data.selectedWith(newTestRequestConnection(withLabel(MechanismsLabel, "vxlan,WIREGUARD")), 2)
+	g.Expect(err).To(BeNil())
+	g.Expect(names).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestFilterMechanisms_Mixed(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createMechanismsEndpoint(nse1Name, "VXLAN"),
+		data.createMechanismsEndpoint(nse2Name, "WIREGUARD"),
+		data.createMechanismsEndpoint(nse3Name, ""))
+
+	names, err := data.selectedWith(newTestRequestConnection(withLabel(MechanismsLabel, "WIREGUARD")), 4)
+	g.Expect(err).To(BeNil())
+	g.Expect(names).To(ConsistOf(nse2Name, nse3Name, nse2Name, nse3Name))
+
+	names, err = data.selectedWith(newTestRequestConnection(), 3)
+	g.Expect(err).To(BeNil())
+	g.Expect(names).To(ConsistOf(nse1Name, nse2Name, nse3Name))
+}
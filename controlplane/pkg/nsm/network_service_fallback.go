@@ -0,0 +1,53 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// isEmptySelection - tells if selection failed because network service has no selectable endpoints, as opposed to
+// discovery or other errors.
+func isEmptySelection(err error) bool {
+	return errors.Is(err, ErrNoEndpointFound) || errors.Is(err, ErrCandidatesExhausted) || errors.Is(err, ErrNoReadyEndpoints)
+}
+
+// getFallbackEndpoint - selects endpoint of properties.FallbackNetworkService if requested network service has no
+// selectable endpoints, as err of its selection tells, otherwise returns err. Requests targeting endpoint by name are
+// not redirected.
+func (nsem *nseManager) getFallbackEndpoint(ctx context.Context, requestConnection *connection.Connection,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, err error) (*registry.NSERegistration, error) {
+	fallback := nsem.props.FallbackNetworkService
+	if fallback == "" || fallback == requestConnection.GetNetworkService() ||
+		requestConnection.GetNetworkServiceEndpointName() != "" || !isEmptySelection(err) {
+		return nil, err
+	}
+	logrus.Infof("No endpoint for NetworkService %s, falling back to %s: %v", requestConnection.GetNetworkService(), fallback, err)
+	fallbackConnection := requestConnection.Clone()
+	fallbackConnection.NetworkService = fallback
+	endpoint, err := nsem.getEndpoint(ctx, fallbackConnection, ignoreEndpoints)
+	if err != nil {
+		return nil, errors.Wrapf(err, "no endpoint for NetworkService %s nor for fallback", requestConnection.GetNetworkService())
+	}
+	return endpoint, nil
+}
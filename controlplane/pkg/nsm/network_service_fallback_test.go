@@ -0,0 +1,67 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+const (
+	sinkServiceName  = "sink-service"
+	emptyServiceName = "empty-service"
+)
+
+// withSinkFallback - endpoints are discovered for sink service only, other services fall back to it.
+func withSinkFallback(data *nseManagerTestData) {
+	discovery := data.discoverServices(sinkServiceName)
+	empty := data.createFindNetworkServiceResponse()
+	empty.NetworkService.Name = emptyServiceName
+	discovery.script(emptyServiceName, discoveryStep{response: empty})
+	data.nseManager.props.FallbackNetworkService = sinkServiceName
+}
+
+func TestNetworkServiceFallback(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSinkFallback)
+
+	requestConnection := &connection.Connection{NetworkService: emptyServiceName}
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkService().GetName()).To(Equal(sinkServiceName))
+	g.Expect(requestConnection.GetNetworkService()).To(Equal(emptyServiceName))
+}
+
+func TestNetworkServiceFallback_AllIgnored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSinkFallback)
+	data.nseManager.props.FallbackNetworkService = emptyServiceName
+
+	ignores := data.ignores(data.createEndpoint(nse1Name, remoteNSMName), data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+	_, err := data.nseManager.GetEndpoint(context.Background(), &connection.Connection{NetworkService: sinkServiceName}, ignores)
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeTrue())
+	g.Expect(err.Error()).To(HavePrefix("no endpoint for NetworkService " + sinkServiceName + " nor for fallback"))
+}
+
+func TestNetworkServiceFallback_DiscoveryError(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSinkFallback)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), &connection.Connection{NetworkService: "unknown"}, nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("network service unknown is not found"))
+}
+
+func TestNetworkServiceFallback_Targeted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSinkFallback)
+
+	requestConnection := newTargetedRequestConnection(nse1Name, remoteNSMName)
+	requestConnection.NetworkService = emptyServiceName
+	_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(errors.Is(err, ErrTargetEndpointNotFound)).To(BeTrue())
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strings"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// normalizeNetworkService - trims network service name, lowercases it with properties.CaseInsensitiveNetworkServices.
+func (nsem *nseManager) normalizeNetworkService(networkService string) string {
+	networkService = strings.TrimSpace(networkService)
+	if nsem.props.CaseInsensitiveNetworkServices {
+		networkService = strings.ToLower(networkService)
+	}
+	return networkService
+}
+
+// normalizeRequest - returns copy of request connection with normalized network service name, request connection
+// itself if the name is normalized already.
+func (nsem *nseManager) normalizeRequest(span spanhelper.SpanHelper, requestConnection *connection.Connection) *connection.Connection {
+	networkService := nsem.normalizeNetworkService(requestConnection.GetNetworkService())
+	if networkService == requestConnection.GetNetworkService() {
+		return requestConnection
+	}
+	span.LogValue("networkService", networkService)
+	normalized := requestConnection.Clone()
+	normalized.NetworkService = networkService
+	return normalized
+}
+
+// localEndpointsOf - returns endpoints registered at local NSM of normalized network service name.
+func (nsem *nseManager) localEndpointsOf(networkService string) []*model.Endpoint {
+	if !nsem.props.CaseInsensitiveNetworkServices {
+		return nsem.model.GetEndpointsByNetworkService(networkService)
+	}
+	var result []*model.Endpoint
+	for _, endpoint := range nsem.model.GetAllEndpoints() {
+		if nsem.normalizeNetworkService(endpoint.NetworkServiceName()) == networkService {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package nsm
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func TestNetworkServiceName_PaddedResolvesSameEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	request := newTestRequestConnection(withNetworkService(" " + networkServiceName + "\t"))
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	// Request of caller is not modified.
+	g.Expect(request.GetNetworkService()).To(Equal(" " + networkServiceName + "\t"))
+}
+
+func TestNetworkServiceName_MixedCase(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+	mixedCase := " " + strings.ToUpper(networkServiceName[:1]) + networkServiceName[1:]
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withNetworkService(mixedCase)), nil)
+	g.Expect(err).NotTo(BeNil())
+
+	data.nseManager.props.CaseInsensitiveNetworkServices = true
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withNetworkService(mixedCase)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestNetworkServiceName_MatchesLocalEndpointsCaseInsensitively(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailingDiscovery)
+	data.nseManager.props.DiscoveryFailOpen = true
+	data.nseManager.props.CaseInsensitiveNetworkServices = true
+	local := data.createEndpoint(nse1Name, localNSMName)
+	local.NetworkServiceEndpoint.NetworkServiceName = strings.ToUpper(networkServiceName)
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: local})
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withNetworkService(strings.ToUpper(networkServiceName))), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
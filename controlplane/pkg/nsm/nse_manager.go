@@ -2,6 +2,9 @@ package nsm
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"time"
 
 	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
 
@@ -9,166 +12,825 @@ import (
 
 	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sirupsen/logrus"
 
 	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
 	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
 	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/metrics"
 	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
 	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/serviceregistry"
 )
 
+// DiscoveryClientProvider - supplies a discovery client used to find endpoints of network services,
+// serviceregistry.ServiceRegistry is the default one.
+type DiscoveryClientProvider interface {
+	DiscoveryClient(ctx context.Context) (registry.NetworkServiceDiscoveryClient, error)
+}
+
+// WithDiscoveryClientProvider - find endpoints of network services with discovery clients supplied by provider
+// instead of service registry, e.g. canned responses in tests.
+func WithDiscoveryClientProvider(provider DiscoveryClientProvider) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.discoveryProvider = provider
+	}
+}
+
 type nseManager struct {
-	serviceRegistry serviceregistry.ServiceRegistry
-	model           model.Model
-	props           *properties.Properties
+	serviceRegistry   serviceregistry.ServiceRegistry
+	discoveryProvider DiscoveryClientProvider
+	model             model.Model
+	props             *properties.Properties
+	rttStore          *rttStore
+	skewMonitor       selectionSkewMonitor
+	scoresExporter    selectionScoresExporter
+	reachability      reachabilityEvents
+	fairness          selectionFairness
+	tokenKey          []byte
+	history           *selectionHistory
+	affinity          *sessionAffinity
+	routes            *connectionRoutes
+	dialPreemptions   *dialPreemptions
+	localEndpoints    *localEndpointCache
+	managerFair       selector.Selector
+	topology          selector.Selector
+	transform         RegistrationTransform
+	selectionSlots    selectionSlots
+	drained           *drainedEndpoints
+	selectionCounter  *prometheus.CounterVec
+	shadowCounter     *prometheus.CounterVec
+	failureCounter    *prometheus.CounterVec
+	discoveryDuration *prometheus.HistogramVec
+	clientCounter     *prometheus.CounterVec
+	dialDuration      *prometheus.HistogramVec
+	resolveDuration   *prometheus.HistogramVec
+
+	capabilityNegotiator CapabilityNegotiator
+	reachabilityChecker  ReachabilityChecker
+	identity             EndpointIdentity
+	namespace            ConnectionNamespace
+	dedup                DeduplicationStrategy
+	prober               DataPathProber
+	loadProvider         OrcaLoadProvider
+	quarantine           endpointQuarantine
+	drainNotifier        DrainNotifier
+	draining             *drainTracker
+	discoveryCache       discoveryCache
+	discoveryFlights     discoveryFlights
+	remoteClients        remoteClientPool
+	credentialsResolver  RemoteCredentialsResolver
+	breakers             managerBreakers
+	unreachable          endpointQuarantine
+	blacklist            endpointQuarantine
+	localQuarantine      endpointQuarantine
+	localFailures        endpointFailures
+	chaos                chaosInjector
+	approvalGate         ApprovalGate
+	endpointSelector     EndpointSelector
+	shadowSelector       selector.Selector
+	denials              approvalDenials
+	locality             dataLocality
+	retries              retryBudget
+	slaViolations        slaViolations
+	cooldowns            endpointCooldowns
+	failureRates         endpointFailureRates
+	reservations         reservationLedger
+	latencies            latencyReservoir
+	checkJitter          healCheckJitter
+	managerResolver      NetworkServiceManagerResolver
+	clock                Clock
+	selectionObserver    SelectionObserver
+	policies             *selector.PolicyRegistry
+}
+
+// NseManagerOption - an option to customize endpoint manager of NetworkServiceManager.
+type NseManagerOption func(nsem *nseManager)
+
+func newNseManager(serviceRegistry serviceregistry.ServiceRegistry, model model.Model, props *properties.Properties, options ...NseManagerOption) *nseManager {
+	nsem := &nseManager{
+		serviceRegistry:   serviceRegistry,
+		discoveryProvider: serviceRegistry,
+		model:             model,
+		props:             props,
+		tokenKey:          newSelectionTokenKey(),
+		identity:          endpointNSMNameIdentity{},
+		namespace:         tenantNamespace,
+		prober:            noopDataPathProber{},
+		loadProvider:      noopOrcaLoadProvider{},
+		managerResolver:   registrationManagerResolver{},
+		clock:             realClock{},
+		policies:          selector.NewPolicyRegistry(),
+	}
+	nsem.endpointSelector = modelEndpointSelector{nsem: nsem}
+	nsem.managerFair = selector.NewManagerFairSelector(modelEndpointSelector{nsem: nsem})
+	nsem.topology = selector.NewTopologySelector(propertiesLocator{nsem: nsem}, modelEndpointSelector{nsem: nsem})
+	for _, option := range options {
+		option(nsem)
+	}
+	nsem.history = newSelectionHistory(model, nsem.namespace)
+	nsem.affinity = newSessionAffinity(model, nsem.namespace)
+	nsem.routes = newConnectionRoutes(model, nsem.namespace)
+	nsem.rttStore = newRTTStore(model)
+	nsem.localEndpoints = newLocalEndpointCache(model)
+	nsem.drained = newDrainedEndpoints(model)
+	nsem.draining = newDrainTracker(nsem)
+	nsem.dialPreemptions = newDialPreemptions(model)
+	nsem.selectionCounter = metrics.BuildSelectionCounter()
+	nsem.shadowCounter = metrics.BuildShadowSelectionCounter()
+	nsem.failureCounter = metrics.BuildSelectionFailureCounter()
+	nsem.discoveryDuration = metrics.BuildDiscoveryHistogram()
+	nsem.clientCounter = metrics.BuildClientCreationCounter()
+	nsem.dialDuration = metrics.BuildRemoteDialHistogram()
+	nsem.resolveDuration = metrics.BuildEndpointResolutionHistogram()
+	return nsem
 }
 
 func (nsem *nseManager) GetEndpoint(ctx context.Context, requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, error) {
+	start := time.Now()
+	result := selectionResultFrom(ctx)
+	ctx = WithSelectionResult(ctx, result)
+	endpoint, err := nsem.getEndpoint(ctx, requestConnection, ignoreEndpoints)
+	if err != nil {
+		endpoint, err = nsem.getFallbackEndpoint(ctx, requestConnection, ignoreEndpoints, err)
+	}
+	if err == nil {
+		endpoint, err = nsem.transformRegistration(ctx, requestConnection, endpoint)
+	}
+	nsem.observeSelection(start, requestConnection, result, endpoint, err)
+	return endpoint, err
+}
+
+func (nsem *nseManager) getEndpoint(ctx context.Context, requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, error) {
+	ctx = withCorrelationID(ctx, requestConnection)
 	span := spanhelper.FromContext(ctx, "GetEndpoint")
 	defer span.Finish()
+	span.LogValue("correlationId", correlationIDFrom(ctx))
+	defer nsem.latencies.record(time.Now(), nsem.props.SelectionLatencyReservoirSize)
 	span.LogObject("request", requestConnection)
-	span.LogObject("ignores", ignoreEndpoints)
+	span.LogObject("ignores", newIgnoresSummary(ignoreEndpoints))
+	requestConnection = nsem.normalizeRequest(span, requestConnection)
 	// Handle case we are remote NSM and asked for particular endpoint to connect to.
 	targetEndpoint := requestConnection.GetNetworkServiceEndpointName()
-	myNsemName := nsem.model.GetNsm().GetName()
+	myNsemName, err := nsem.localNsmName()
+	if err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	if err = validateRequest(requestConnection, myNsemName); err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	release, err := nsem.acquireSelectionSlot(ctx, span, requestConnection.GetNetworkService())
+	if err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	defer release()
+	if err = nsem.checkIgnoresLimit(requestConnection, ignoreEndpoints); err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	if err = nsem.allowRetry(requestConnection, ignoreEndpoints); err != nil {
+		span.LogError(err)
+		return nil, err
+	}
 	targetNsemName := requestConnection.GetDestinationNetworkServiceManagerName()
 	span.LogObject("targetEndpoint", targetEndpoint)
 	span.LogObject("targetNsemName", targetNsemName)
-	if len(targetEndpoint) > 0 {
-		if len(targetNsemName) > 0 && myNsemName == targetNsemName {
-			endpoint := nsem.model.GetEndpoint(targetEndpoint)
-			if endpoint != nil && ignoreEndpoints[endpoint.Endpoint.GetEndpointNSMName()] == nil {
-				return endpoint.Endpoint, nil
-			} else {
-				return nil, errors.Errorf("Could not find endpoint with name: %s at local registry", targetEndpoint)
-			}
+	result := selectionResultFrom(ctx)
+	*result = SelectionResult{Confidence: 1, Breadth: 1}
+	pinned := len(targetEndpoint) > 0
+	if pinned && len(targetNsemName) > 0 && myNsemName == targetNsemName {
+		endpoint, err := nsem.getLocalTargetEndpoint(ctx, span, requestConnection, ignoreEndpoints)
+		if endpoint != nil {
+			logChoiceBreadth(span, result)
+		}
+		if endpoint != nil || err != nil {
+			return endpoint, err
+		}
+		pinned = false
+	} else if pinned && len(targetNsemName) > 0 {
+		endpoint, err := nsem.assignedEndpoint(ctx, span, requestConnection, ignoreEndpoints)
+		if endpoint != nil {
+			logChoiceBreadth(span, result)
+		}
+		if endpoint != nil || err != nil {
+			return endpoint, err
 		}
 	}
 
-	// Get endpoints, do it every time since we do not know if list are changed or not.
-	discoveryClient, err := nsem.serviceRegistry.DiscoveryClient(ctx)
+	budget := nsem.newSelectionBudget(ctx, requestConnection.GetNetworkService())
+	var endpointResponse *registry.FindNetworkServiceResponse
+	err = budget.run(ctx, discoveryPhase, func(ctx context.Context) (err error) {
+		endpointResponse, result.DiscoveredAt, err = nsem.findNetworkServiceShared(ctx, span, requestConnection.GetNetworkService(), ignoreEndpoints)
+		return err
+	})
 	if err != nil {
-		span.LogError(err)
-		return nil, err
-	}
-	nseRequest := &registry.FindNetworkServiceRequest{
-		NetworkServiceName: requestConnection.GetNetworkService(),
+		if endpointResponse = nsem.localDiscoveryResponse(ctx, span, requestConnection.GetNetworkService(), err); endpointResponse == nil {
+			return nil, err
+		}
+		result.LocalOnly = true
 	}
-	span.LogObject("nseRequest", nseRequest)
-	endpointResponse, err := discoveryClient.FindNetworkService(ctx, nseRequest)
-	span.LogObject("nseResponse", endpointResponse)
-	if err != nil {
+	result.Generation = nsem.discoveryGeneration(endpointResponse)
+	span.LogValue("generation", result.Generation)
+	span.LogValue("discoveredAt", result.DiscoveredAt)
+	nsem.logCandidates(ctx, requestConnection, endpointResponse, ignoreEndpoints)
+	if err = nsem.checkQuorum(ctx, span, budget, endpointResponse, ignoreEndpoints); err != nil {
 		span.LogError(err)
 		return nil, err
 	}
 	var endpoint *registry.NetworkServiceEndpoint
-	if len(targetEndpoint) > 0 {
+	if pinned {
 		endpoint = nsem.getTargetEndpoint(endpointResponse.GetNetworkServiceEndpoints(), targetEndpoint, targetNsemName)
-		if endpoint == nil {
-			err = errors.Errorf("failed to find targeted NSE %s (NSMgr=%s) for NetworkService %s. Checked: %d endpoints",
-				targetEndpoint, targetNsemName, requestConnection.GetNetworkService(), len(endpointResponse.GetNetworkServiceEndpoints()))
+		if endpoint == nil && !unpinOnFailure(requestConnection) {
+			nsem.countFailure(requestConnection.GetNetworkService(), metrics.FailureTargetNotFound)
+			checked := len(endpointResponse.GetNetworkServiceEndpoints())
+			err = newEndpointNotFoundError(ErrTargetEndpointNotFound, requestConnection.GetNetworkService(), targetEndpoint, targetNsemName, checked,
+				"failed to find targeted NSE %s (NSMgr=%s) for NetworkService %s. Checked: %d endpoints",
+				targetEndpoint, targetNsemName, requestConnection.GetNetworkService(), checked)
 			span.LogError(err)
 			return nil, err
 		}
+		pinned = endpoint != nil
+	}
+	reason := SelectionReasonPinned
+	if pinned {
+		span.LogValue("selector", "bypassed: "+reason)
 	} else {
-		endpoints := nsem.filterEndpoints(endpointResponse.GetNetworkServiceEndpoints(), endpointResponse.NetworkServiceManagers, ignoreEndpoints)
+		if endpoint, reason = nsem.reusableEndpoint(span, requestConnection, endpointResponse, ignoreEndpoints); endpoint != nil {
+			span.LogValue("selector", "bypassed: "+reason)
+		} else {
+			reason = SelectionReasonSelected
+			endpoint, err = nsem.selectReachable(ctx, span, budget, requestConnection, endpointResponse, ignoreEndpoints)
+			if err != nil {
+				if report, ok := RejectionsFrom(err); ok {
+					span.LogObject("rejections", report)
+				}
+				span.LogError(err)
+				return nil, err
+			}
+		}
+		result.Unpinned = len(targetEndpoint) > 0
+		span.LogValue("unpinned", result.Unpinned)
+		if result.Unpinned {
+			reason = SelectionReasonUnpinned
+		}
+	}
+	result.Token = nsem.issueSelectionToken(requestConnection.GetNetworkService(), endpoint)
+	result.Metadata = nsem.exportedMetadata(endpoint)
+	span.LogObject("endpoint", endpoint)
+	registration := newNSERegistration(endpointResponse, endpoint)
+	if err = nsem.approve(ctx, requestConnection, registration); err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	nsem.recordSelection(requestConnection, registration, reason)
+	nsem.stickEndpoint(requestConnection, endpoint)
+	logChoiceBreadth(span, result)
+	return registration, nil
+}
 
-		if len(endpoints) == 0 {
-			err = errors.Errorf("failed to find NSE for NetworkService %s. Checked: %d of total NSEs: %d",
-				requestConnection.GetNetworkService(), len(ignoreEndpoints), len(endpoints))
-			span.LogError(err)
-			return nil, err
+// reusableEndpoint - returns endpoint connection could keep without full selection, by soft preference of request,
+// by selection token, by previous endpoint hint of connection handed over from another NSMgr, by session affinity or
+// by data locality hint, and reason code of reuse. Reused endpoint must survive filtering as any selected endpoint
+// would, so reuse never overrides drain, quarantine, manager allowlist or other filters. Endpoints are filtered at
+// most once, when the first reuse path has an endpoint to look for.
+func (nsem *nseManager) reusableEndpoint(span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	endpointResponse *registry.FindNetworkServiceResponse, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NetworkServiceEndpoint, string) {
+	var viable []*registry.NetworkServiceEndpoint
+	filtered := false
+	candidates := func() []*registry.NetworkServiceEndpoint {
+		if !filtered {
+			viable, _ = nsem.filterEndpoints(requestConnection, endpointResponse.GetNetworkServiceEndpoints(), endpointResponse.GetNetworkServiceManagers(), ignoreEndpoints)
+			filtered = true
 		}
+		return viable
+	}
+	if endpoint := nsem.endpointFromPreference(span, requestConnection, candidates); endpoint != nil {
+		return endpoint, SelectionReasonPreferred
+	}
+	if endpoint := nsem.endpointFromToken(span, requestConnection, candidates); endpoint != nil {
+		return endpoint, SelectionReasonToken
+	}
+	if endpoint := nsem.endpointFromHint(span, requestConnection, candidates); endpoint != nil {
+		return endpoint, SelectionReasonRehomed
+	}
+	if endpoint := nsem.endpointFromAffinity(span, requestConnection, candidates); endpoint != nil {
+		return endpoint, SelectionReasonSticky
+	}
+	if endpoint := nsem.endpointFromLocality(span, requestConnection, candidates); endpoint != nil {
+		return endpoint, SelectionReasonLocality
+	}
+	return nil, ""
+}
 
-		endpoint = nsem.model.GetSelector().SelectEndpoint(requestConnection, endpointResponse.GetNetworkService(), endpoints)
-		if endpoint == nil {
-			err = errors.Errorf("failed to find NSE for NetworkService %s. Checked: %d of total NSEs: %d",
-				requestConnection.GetNetworkService(), len(ignoreEndpoints), len(endpoints))
+// getLocalTargetEndpoint - returns endpoint of local NSM request is targeted to, nil endpoint and error if it could
+// not be found and request allows unpinning.
+func (nsem *nseManager) getLocalTargetEndpoint(ctx context.Context, span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, error) {
+	targetEndpoint := requestConnection.GetNetworkServiceEndpointName()
+	endpoint := nsem.localEndpoint(targetEndpoint)
+	if endpoint != nil && !nsem.isIgnored(endpoint.Endpoint.GetNetworkServiceEndpoint(), endpoint.Endpoint.GetNetworkServiceManager(), ignoreEndpoints) &&
+		!nsem.localQuarantine.contains(nsem.localEndpointKey(endpoint)) {
+		if err := nsem.approve(ctx, requestConnection, endpoint.Endpoint); err != nil {
 			span.LogError(err)
 			return nil, err
 		}
+		span.LogValue("selector", "bypassed: "+SelectionReasonPinned)
+		nsem.recordSelection(requestConnection, endpoint.Endpoint, SelectionReasonPinned)
+		return endpoint.Endpoint, nil
 	}
-	span.LogObject("endpoint", endpoint)
+	if !unpinOnFailure(requestConnection) {
+		nsem.countFailure(requestConnection.GetNetworkService(), metrics.FailureTargetNotFound)
+		return nil, newEndpointNotFoundError(ErrLocalEndpointNotFound, requestConnection.GetNetworkService(), targetEndpoint, "", 1,
+			"Could not find endpoint with name: %s at local registry", targetEndpoint)
+	}
+	return nil, nil
+}
+
+// selectForRequest - selects one of discovered endpoints within selection phase budget and reports selection confidence.
+func (nsem *nseManager) selectForRequest(ctx context.Context, span spanhelper.SpanHelper, budget *selectionBudget, requestConnection *connection.Connection,
+	endpointResponse *registry.FindNetworkServiceResponse, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NetworkServiceEndpoint, error) {
+	var endpoint *registry.NetworkServiceEndpoint
+	var candidates []*registry.NetworkServiceEndpoint
+	err := budget.run(ctx, selectionPhase, func(ctx context.Context) (err error) {
+		if err = nsem.chaos.delay(ctx, nsem.props); err != nil {
+			return err
+		}
+		selectSpan := spanhelper.FromContext(ctx, "SelectEndpoint")
+		defer selectSpan.Finish()
+		selectSpan.LogValue("selector", fmt.Sprintf("%T", nsem.activeSelector(endpointResponse.GetNetworkService())))
+		selectFn, err := nsem.selectFunc(endpointResponse.GetNetworkService())
+		if err != nil {
+			selectSpan.LogError(err)
+			return err
+		}
+		endpoint, candidates, err = nsem.selectAndReserve(selectSpan, requestConnection, endpointResponse, ignoreEndpoints, selectFn)
+		if err != nil {
+			selectSpan.LogError(err)
+			cause := metrics.FailureNoEndpoints
+			var selectorNil *SelectorReturnedNilError
+			if errors.As(err, &selectorNil) {
+				selectSpan.LogValue("candidateCount", selectorNil.Candidates)
+				cause = metrics.FailureSelectorNil
+			}
+			nsem.countFailure(requestConnection.GetNetworkService(), cause)
+			return err
+		}
+		selectSpan.LogValue("candidateCount", len(candidates))
+		selectSpan.LogObject("candidates", endpointNames(candidates))
+		selectSpan.LogValue("selected", endpoint.GetName())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := selectionResultFrom(ctx)
+	scores := nsem.scoreCandidates(requestConnection, endpointResponse.GetNetworkService(), candidates, endpointResponse.GetNetworkServiceManagers())
+	result.Confidence = selectionConfidence(scores, candidates, endpoint)
+	span.LogValue("confidence", result.Confidence)
+	result.Selected, result.Breadth = true, len(candidates)
+	nsem.exportScores(requestConnection, endpointResponse.GetNetworkService(), candidates, endpoint, scores)
+	nsem.bindLocality(requestConnection, endpoint)
+	nsem.startCooldown(endpoint, endpointResponse.GetNetworkServiceManagers())
+	result.ShadowEndpoint = nsem.shadowSelect(requestConnection, endpointResponse.GetNetworkService(), candidates, endpointResponse.GetNetworkServiceManagers(), endpoint)
+	return endpoint, nil
+}
+
+// findNetworkService - asks registry for endpoints of network service.
+func (nsem *nseManager) findNetworkService(ctx context.Context, span spanhelper.SpanHelper, networkService string) (*registry.FindNetworkServiceResponse, error) {
+	endpointResponse, _, err := nsem.findNetworkServiceFetched(ctx, span, networkService)
+	return endpointResponse, err
+}
+
+// findNetworkServiceFetched - findNetworkService also returning when response was fetched from registry, responses
+// served from discovery cache keep the time they were fetched at.
+func (nsem *nseManager) findNetworkServiceFetched(ctx context.Context, span spanhelper.SpanHelper, networkService string) (*registry.FindNetworkServiceResponse, time.Time, error) {
+	if endpointResponse, fetched, ok := nsem.discoveryCache.get(networkService); ok {
+		span.LogValue("discoveryCache", "hit")
+		return endpointResponse, fetched, nil
+	}
+	if err := nsem.chaos.fail(nsem.props, nsem.props.ChaosDiscoveryFailureRate, "discovery"); err != nil {
+		span.LogError(err)
+		return nil, time.Time{}, err
+	}
+	// Get endpoints, do it every time cache is disabled or expired since we do not know if list are changed or not.
+	discoveryClient, err := nsem.discoveryProvider.DiscoveryClient(ctx)
+	if err != nil {
+		span.LogError(err)
+		return nil, time.Time{}, err
+	}
+	nseRequest := &registry.FindNetworkServiceRequest{
+		NetworkServiceName: networkService,
+	}
+	span.LogObject("nseRequest", nseRequest)
+	span.LogValue("correlationId", correlationIDFrom(ctx))
+	start := time.Now()
+	endpointResponse, err := nsem.findWithRetry(ctx, span, discoveryClient, nseRequest)
+	nsem.discoveryDuration.WithLabelValues(networkService).Observe(time.Since(start).Seconds())
+	span.LogObject("nseResponse", endpointResponse)
+	if err != nil {
+		span.LogError(err)
+		return nil, time.Time{}, err
+	}
+	fetched := time.Now()
+	discovered := len(endpointResponse.GetNetworkServiceEndpoints())
+	if endpointResponse = validateManagerReferences(span, endpointResponse); discovered > 0 && len(endpointResponse.GetNetworkServiceEndpoints()) == 0 {
+		err = errors.Wrapf(ErrInconsistentManagers, "all %d discovered endpoints of NetworkService %s reference managers absent from discovery response",
+			discovered, networkService)
+		span.LogError(err)
+		return nil, time.Time{}, err
+	}
+	nsem.discoveryCache.put(networkService, endpointResponse, fetched, nsem.props.DiscoveryCacheTTL)
+	return endpointResponse, fetched, nil
+}
+
+type selectFunc func(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint
+
+// selectEndpoint - filters out ignored endpoints of discovery response and selects one of the rest using selectFn,
+// returns selected endpoint and candidates it was selected from. Filter stages are logged to span, if it is not nil.
+func (nsem *nseManager) selectEndpoint(span spanhelper.SpanHelper, requestConnection *connection.Connection, endpointResponse *registry.FindNetworkServiceResponse,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, selectFn selectFunc) (*registry.NetworkServiceEndpoint, []*registry.NetworkServiceEndpoint, error) {
+	// Rejections are always collected to tell which stage emptied candidates, but are attached to errors only if
+	// rejection report is enabled.
+	rejections := RejectionReport{}
+	endpoints, err := nsem.filterEndpointsReported(span, requestConnection, endpointResponse.GetNetworkServiceEndpoints(), endpointResponse.NetworkServiceManagers, ignoreEndpoints, rejections)
+	report := nsem.newRejectionReport()
+	if report != nil {
+		report = rejections
+	}
+	if err != nil {
+		return nil, nil, withRejections(err, report)
+	}
+
+	discovered := len(endpointResponse.GetNetworkServiceEndpoints())
+	if len(endpoints) == 0 {
+		if notReady := notReadyEmptied(rejections); notReady > 0 {
+			return nil, nil, withRejections(errors.Wrapf(ErrNoReadyEndpoints, "NetworkService %s has %d not ready endpoints",
+				requestConnection.GetNetworkService(), notReady), report)
+		}
+		if discovered > 0 {
+			return nil, nil, withRejections(errors.Wrapf(ErrCandidatesExhausted, "failed to find NSE for NetworkService %s. Total NSEs: %d, candidates: 0, ignored: %d",
+				requestConnection.GetNetworkService(), discovered, len(ignoreEndpoints)), report)
+		}
+		return nil, nil, newEndpointNotFoundError(ErrNoEndpointFound, requestConnection.GetNetworkService(), "", "", len(ignoreEndpoints),
+			"failed to find NSE for NetworkService %s. Total NSEs: 0, candidates: 0, ignored: %d",
+			requestConnection.GetNetworkService(), len(ignoreEndpoints))
+	}
+
+	endpoints = nsem.capCandidates(requestConnection, endpoints)
+	endpoint := selectFn(requestConnection, endpointResponse.GetNetworkService(), endpoints, endpointResponse.GetNetworkServiceManagers())
+	if endpoint == nil {
+		logrus.Warnf("Selector returned no endpoint among %d candidates of NetworkService %s", len(endpoints), requestConnection.GetNetworkService())
+		return nil, nil, withRejections(&SelectorReturnedNilError{
+			Candidates: len(endpoints),
+			notFound: newEndpointNotFoundError(ErrNoEndpointFound, requestConnection.GetNetworkService(), "", "", len(ignoreEndpoints),
+				"failed to find NSE for NetworkService %s. Total NSEs: %d, candidates: %d, ignored: %d",
+				requestConnection.GetNetworkService(), discovered, len(endpoints), len(ignoreEndpoints)),
+		}, report)
+	}
+	if err := checkCandidate(endpoint, endpoints); err != nil {
+		return nil, nil, err
+	}
+	return endpoint, endpoints, nil
+}
+
+func newNSERegistration(endpointResponse *registry.FindNetworkServiceResponse, endpoint *registry.NetworkServiceEndpoint) *registry.NSERegistration {
 	return &registry.NSERegistration{
 		NetworkServiceManager:  endpointResponse.GetNetworkServiceManagers()[endpoint.GetNetworkServiceManagerName()],
 		NetworkServiceEndpoint: endpoint,
 		NetworkService:         endpointResponse.GetNetworkService(),
-	}, nil
+	}
 }
 
 /**
 ctx - we assume it is big enought to perform connection.
 */
 func (nsem *nseManager) CreateNSEClient(ctx context.Context, endpoint *registry.NSERegistration) (nsm.NetworkServiceClient, error) {
+	defer nsem.settleReservation(endpoint)
 	span := spanhelper.FromContext(ctx, "createNSEClient")
 	defer span.Finish()
+	span.LogValue("correlationId", correlationIDFrom(ctx))
 	logger := span.Logger()
-	if nsem.IsLocalEndpoint(endpoint) {
-		modelEp := nsem.model.GetEndpoint(endpoint.GetNetworkServiceEndpoint().GetName())
+	if err := nsem.chaos.fail(nsem.props, nsem.props.ChaosClientFailureRate, "client creation"); err != nil {
+		span.LogError(err)
+		return nil, err
+	}
+	isLocal, localNsmName, endpointNsmName := nsem.EndpointLocality(endpoint)
+	span.LogValue("localNsm", localNsmName)
+	span.LogValue("endpointNsm", endpointNsmName)
+	forced := forcedRemote(ctx)
+	if forced && isLocal {
+		logger.Warnf("Dialing local endpoint %v remotely as forced by context", endpoint.GetEndpointNSMName())
+		span.LogValue("forcedRemote", true)
+		isLocal = false
+	}
+	var manager *registry.NetworkServiceManager
+	if !isLocal {
+		var err error
+		if manager, err = nsem.managerResolver.Resolve(endpoint); err != nil {
+			span.LogError(err)
+			return nil, errors.Wrapf(err, "failed to resolve NSMgr of endpoint %v", endpoint.GetEndpointNSMName())
+		}
+		// Endpoint advertised by an alias of local NSM is connected to locally instead of dialing ourselves.
+		if isLocal = !forced && nsem.isLocalManager(manager); isLocal {
+			logger.Warnf("NSMgr %s of endpoint %v resolves to local NSM %s", endpointNsmName, endpoint.GetEndpointNSMName(), localNsmName)
+		}
+	}
+	span.LogValue("isLocal", isLocal)
+	if isLocal {
+		span.LogValue("locality", metrics.LocalityLocal)
+		nsem.clientCounter.WithLabelValues(endpoint.GetNetworkService().GetName(), metrics.LocalityLocal).Inc()
+		modelEp := nsem.localEndpoint(endpoint.GetNetworkServiceEndpoint().GetName())
 		if modelEp == nil {
-			return nil, errors.Errorf("Endpoint not found: %v", endpoint)
+			// Endpoint was deleted since it was selected, e.g. by cleanupNSE.
+			return nil, newEndpointNotFoundError(ErrLocalEndpointNotFound, endpoint.GetNetworkService().GetName(),
+				endpoint.GetNetworkServiceEndpoint().GetName(), localNsmName, 0, "Endpoint not found: %v", endpoint)
 		}
 		logger.Infof("Create local NSE connection to endpoint: %v", modelEp)
 		client, conn, err := nsem.serviceRegistry.EndpointConnection(span.Context(), modelEp)
 		if err != nil {
 			span.LogError(err)
 			// We failed to connect to local NSE.
-			nsem.cleanupNSE(ctx, modelEp)
+			nsem.localEndpointFailed(ctx, modelEp)
+			nsem.recordClientOutcome(endpoint, err)
+			nsem.discoveryCache.invalidate(endpoint.GetNetworkService().GetName())
 			return nil, err
 		}
+		nsem.localEndpointConnected(modelEp)
+		nsem.recordClientOutcome(endpoint, nil)
 		return &endpointClient{connection: conn, client: client}, nil
 	} else {
 		logger.Infof("Create remote NSE connection to endpoint: %v", endpoint)
-		ctx, cancel := context.WithTimeout(span.Context(), nsem.props.HealRequestConnectTimeout)
+		span.LogValue("locality", metrics.LocalityRemote)
+		nsem.clientCounter.WithLabelValues(endpoint.GetNetworkService().GetName(), metrics.LocalityRemote).Inc()
+		// Connect timeout bounds waiting for connection only, established connection is dialed with context living
+		// as long as the connection, so cancel does not affect it.
+		ctx, cancel := nsem.clock.WithTimeout(span.Context(), nsem.props.HealRequestConnectTimeout)
 		defer cancel()
-		client, conn, err := nsem.serviceRegistry.RemoteNetworkServiceClient(ctx, endpoint.GetNetworkServiceManager())
+		var pending *pendingDial
+		if nsem.props.PreemptRemoteDials {
+			pending = nsem.dialPreemptions.watch(endpoint.GetNetworkService().GetName(), cancel)
+		}
+		span.LogValue("dialUrl", manager.GetUrl())
+		start := time.Now()
+		pooled, err := nsem.acquireRemoteClient(ctx, manager)
+		if pending != nil && nsem.dialPreemptions.stop(pending) {
+			if err == nil {
+				_ = nsem.remoteClients.release(pooled, nsem.props.RemoteClientIdleTimeout)
+			}
+			err = errors.Wrapf(ErrRemoteDialPreempted, "dial of endpoint %v", endpoint.GetEndpointNSMName())
+			span.LogError(err)
+			nsem.discoveryCache.invalidate(endpoint.GetNetworkService().GetName())
+			return nil, err
+		}
+		if errors.Is(err, ErrManagerCircuitOpen) {
+			err = errors.Wrapf(err, "failed to create client of endpoint %v", endpoint.GetEndpointNSMName())
+			span.LogError(err)
+			return nil, err
+		}
+		nsem.dialDuration.WithLabelValues(endpoint.GetNetworkService().GetName()).Observe(time.Since(start).Seconds())
+		nsem.recordClientOutcome(endpoint, err)
 		if err != nil {
+			nsem.discoveryCache.invalidate(endpoint.GetNetworkService().GetName())
+			nsem.blacklistEndpoint(endpoint, err)
 			return nil, err
 		}
-		return &nsmClient{client: client, connection: conn}, nil
+		return &nsmClient{client: pooled.client, connection: pooled.conn, release: func() error {
+			return nsem.remoteClients.release(pooled, nsem.props.RemoteClientIdleTimeout)
+		}}, nil
 	}
 }
 
 func (nsem *nseManager) IsLocalEndpoint(endpoint *registry.NSERegistration) bool {
-	return nsem.model.GetNsm().GetName() == endpoint.GetNetworkServiceEndpoint().GetNetworkServiceManagerName()
+	isLocal, _, _ := nsem.EndpointLocality(endpoint)
+	return isLocal
+}
+
+// EndpointLocality - returns IsLocalEndpoint decision along with the names it compared: name of local NSM, empty
+// if it is not initialized, and name of NSM hosting endpoint. Endpoint hosted by NSM advertised with URL of local NSM
+// is local too.
+func (nsem *nseManager) EndpointLocality(endpoint *registry.NSERegistration) (isLocal bool, localNsmName, endpointNsmName string) {
+	endpointNsmName = endpoint.GetNetworkServiceEndpoint().GetNetworkServiceManagerName()
+	localNsm := nsem.model.GetNsm()
+	if localNsm == nil {
+		logrus.Warnf("%v, treating endpoint %v as remote", ErrNSMNotInitialized, endpoint.GetEndpointNSMName())
+		return false, "", endpointNsmName
+	}
+	isLocal = localNsm.GetName() == endpointNsmName || sameManagerURL(localNsm, endpoint.GetNetworkServiceManager())
+	return isLocal, localNsm.GetName(), endpointNsmName
+}
+
+// isLocalManager - tells if manager is local NSM, by name or by URL it is advertised with under another name.
+func (nsem *nseManager) isLocalManager(manager *registry.NetworkServiceManager) bool {
+	localNsm := nsem.model.GetNsm()
+	if localNsm == nil {
+		return false
+	}
+	return localNsm.GetName() == manager.GetName() || sameManagerURL(localNsm, manager)
+}
+
+func sameManagerURL(manager, other *registry.NetworkServiceManager) bool {
+	return manager.GetUrl() != "" && manager.GetUrl() == other.GetUrl()
+}
+
+// localNsmName - returns name of local NSM, if it is not initialized yet either fails with ErrNSMNotInitialized
+// or returns empty name treating all endpoints as remote, as properties.AllowUninitializedNSM says.
+func (nsem *nseManager) localNsmName() (string, error) {
+	localNsm := nsem.model.GetNsm()
+	if localNsm == nil && !nsem.props.AllowUninitializedNSM {
+		return "", ErrNSMNotInitialized
+	}
+	return localNsm.GetName(), nil
 }
 
 func (nsem *nseManager) CheckUpdateNSE(ctx context.Context, reg *registry.NSERegistration) bool {
-	pingCtx, pingCancel := context.WithTimeout(ctx, nsem.props.HealRequestConnectCheckTimeout)
+	return nsem.CheckUpdateNSEWithError(ctx, reg) == nil
+}
+
+// CheckUpdateNSEWithError - checks NSE client could be created, returns error it could not be created with.
+func (nsem *nseManager) CheckUpdateNSEWithError(ctx context.Context, reg *registry.NSERegistration) error {
+	return nsem.checkUpdateNSE(ctx, reg, nsem.props.HealRequestConnectCheckTimeout)
+}
+
+// checkUpdateNSE - CheckUpdateNSEWithError giving NSE client checkTimeout to be created.
+func (nsem *nseManager) checkUpdateNSE(ctx context.Context, reg *registry.NSERegistration, checkTimeout time.Duration) error {
+	span := spanhelper.FromContext(ctx, "CheckUpdateNSE")
+	defer span.Finish()
+	span.LogObject("endpoint", reg.GetEndpointNSMName())
+	// Ping is cancelled together with ctx.
+	pingTimeout := nsem.checkJitter.timeout(checkTimeout, nsem.props.HealCheckJitter)
+	span.LogValue("pingTimeout", pingTimeout)
+	pingCtx, pingCancel := nsem.clock.WithTimeout(span.Context(), pingTimeout)
 	defer pingCancel()
 
 	client, err := nsem.CreateNSEClient(pingCtx, reg)
-	if err == nil && client != nil {
-		_ = client.Cleanup()
-		return true
+	if err != nil {
+		span.LogError(err)
+		// Failed local endpoints are quarantined or removed, which is observed by localEndpointFailed. Check
+		// cancelled by caller says nothing about endpoint.
+		if !nsem.IsLocalEndpoint(reg) && ctx.Err() == nil {
+			nsem.observeReachability(reg, ReachabilityUnreachable)
+		}
+		return err
 	}
-
-	return false
+	if client == nil {
+		err = errors.Errorf("no client created for endpoint %s", reg.GetEndpointNSMName())
+		span.LogError(err)
+		nsem.observeReachability(reg, ReachabilityUnreachable)
+		return err
+	}
+	_ = client.Cleanup()
+	nsem.observeReachability(reg, ReachabilityReachable)
+	return nil
 }
 
 func (nsem *nseManager) cleanupNSE(ctx context.Context, endpoint *model.Endpoint) {
 	// Remove endpoint from model and put workspace into BAD state.
 	nsem.model.DeleteEndpoint(ctx, endpoint.EndpointName())
+	nsem.localEndpoints.evict(endpoint.EndpointName())
+	nsem.drained.remove(endpoint.EndpointName())
 	logrus.Infof("NSM: Remove Endpoint since it is not available... %v", endpoint)
+	nsem.observeReachability(endpoint.Endpoint, ReachabilityRemoved)
 }
 
-func (nsem *nseManager) filterEndpoints(endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) []*registry.NetworkServiceEndpoint {
+func (nsem *nseManager) filterEndpoints(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) ([]*registry.NetworkServiceEndpoint, error) {
+	return nsem.filterEndpointsReported(nil, requestConnection, endpoints, managers, ignoreEndpoints, nil)
+}
+
+// filterEndpointsReported - filterEndpoints recording why endpoints were filtered out to report, if it is not nil,
+// and logging survivor count of each filter stage to span, if it is not nil. Filter stages must be side effect free,
+// they are run by previews and reuse checks as well as by selection.
+func (nsem *nseManager) filterEndpointsReported(span spanhelper.SpanHelper, requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, report RejectionReport) ([]*registry.NetworkServiceEndpoint, error) {
+	service := requestConnection.GetNetworkService()
+	return runFilterStages(span, endpoints, report, []filterStage{
+		{name: "availability", filter: func(endpoints []*registry.NetworkServiceEndpoint, report RejectionReport) ([]*registry.NetworkServiceEndpoint, error) {
+			return nsem.filterAvailable(requestConnection, endpoints, managers, report), nil
+		}},
+		{name: "ignores", filter: func(endpoints []*registry.NetworkServiceEndpoint, report RejectionReport) ([]*registry.NetworkServiceEndpoint, error) {
+			return nsem.filterIgnored(endpoints, managers, ignoreEndpoints, report), nil
+		}},
+		{name: "readiness", reason: RejectedNotReady, filter: infallible(filterReady)},
+		{name: "upgrading", reason: RejectedUpgrading, filter: fallible(nsem.filterUpgrading)},
+		{name: "version", reason: RejectedVersion, filter: fallible(func(endpoints []*registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error) {
+			return filterMinVersion(requestConnection, endpoints)
+		})},
+		{name: "labels", reason: RejectedLabels, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			return nsem.filterLabelMatches(requestConnection, endpoints)
+		})},
+		{name: "managers", reason: RejectedManagerNotAllowed, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			return filterAllowedManagers(requestConnection, endpoints)
+		})},
+		{name: "mechanisms", reason: RejectedMechanisms, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			return filterMechanisms(requestConnection, endpoints)
+		})},
+		{name: "latency class", reason: RejectedLatencyClass, filter: fallible(func(endpoints []*registry.NetworkServiceEndpoint) ([]*registry.NetworkServiceEndpoint, error) {
+			return nsem.filterLatencyClass(requestConnection, endpoints, managers)
+		})},
+		{name: "SLA violations", reason: RejectedSLAViolations, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			return nsem.filterSLAViolations(service, endpoints, managers)
+		})},
+		{name: "failure rate", reason: RejectedFailureRate, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			return nsem.filterFailureRate(endpoints, managers)
+		})},
+		{name: "min age", reason: RejectedTooYoung, filter: infallible(nsem.filterMinAge)},
+		{name: "cooldown", reason: RejectedCooldown, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			return nsem.filterCooldown(endpoints, managers)
+		})},
+		{name: "locality", reason: RejectedNotLocal, filter: infallible(func(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+			// Local preference keeps order, candidates are sorted before it so that selectors get them sorted.
+			sortEndpoints(endpoints)
+			return nsem.preferLocal(endpoints)
+		})},
+	})
+}
+
+// filterAvailable - drops endpoints which could not be dialed, are quarantined, blacklisted, draining or denied by
+// approval gate, and duplicates of endpoints discovered earlier.
+func (nsem *nseManager) filterAvailable(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager, report RejectionReport) []*registry.NetworkServiceEndpoint {
 	result := []*registry.NetworkServiceEndpoint{}
-	// Do filter of endpoints
+	seen := map[string]bool{}
+	// Do filter of endpoints, endpoints could be discovered more than once
 	for _, candidate := range endpoints {
-		endpointName := registry.NewEndpointNSMName(candidate, managers[candidate.NetworkServiceManagerName])
-		if ignoreEndpoints[endpointName] == nil {
+		manager := managers[candidate.NetworkServiceManagerName]
+		if manager == nil {
+			// Registration with nil manager could not be dialed.
+			report.reject(candidate, RejectedDanglingManager)
+			continue
+		}
+		key := nsem.identity.Key(candidate, manager)
+		dedupKey := nsem.dedupKey(candidate, manager)
+		if seen[dedupKey] || nsem.quarantine.contains(key) || nsem.unreachable.contains(key) || nsem.blacklist.contains(key) ||
+			nsem.localQuarantine.contains(key) || nsem.drained.contains(key) {
+			if report != nil {
+				report.reject(candidate, nsem.unavailableReason(seen[dedupKey], key))
+			}
+			continue
+		}
+		if _, denied := nsem.deniedApproval(requestConnection, key); denied {
+			report.reject(candidate, RejectedApprovalDenied)
+			continue
+		}
+		seen[dedupKey] = true
+		result = append(result, candidate)
+	}
+	return result
+}
+
+// filterIgnored - drops ignored endpoints and endpoints of ignored managers.
+func (nsem *nseManager) filterIgnored(endpoints []*registry.NetworkServiceEndpoint, managers map[string]*registry.NetworkServiceManager,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, report RejectionReport) []*registry.NetworkServiceEndpoint {
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if isManagerIgnored(candidate, ignoreEndpoints) {
+			report.reject(candidate, RejectedManagerIgnored)
+		} else if nsem.isIgnored(candidate, managers[candidate.NetworkServiceManagerName], ignoreEndpoints) {
+			report.reject(candidate, RejectedIgnored)
+		} else {
+			result = append(result, candidate)
+		}
+	}
+	return result
+}
+
+func filterReady(endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		if isEndpointReady(candidate) {
 			result = append(result, candidate)
 		}
 	}
 	return result
 }
 
+func endpointNames(endpoints []*registry.NetworkServiceEndpoint) []string {
+	var names []string
+	for _, endpoint := range endpoints {
+		names = append(names, endpoint.GetName())
+	}
+	return names
+}
+
+// sortEndpoints - sorts endpoints by name, then by name of NSM hosting them, so selectors get candidates in the
+// same order regardless of order of discovery response.
+func sortEndpoints(endpoints []*registry.NetworkServiceEndpoint) {
+	sort.SliceStable(endpoints, func(i, j int) bool {
+		if endpoints[i].GetName() != endpoints[j].GetName() {
+			return endpoints[i].GetName() < endpoints[j].GetName()
+		}
+		return endpoints[i].GetNetworkServiceManagerName() < endpoints[j].GetNetworkServiceManagerName()
+	})
+}
+
 func (nsem *nseManager) getTargetEndpoint(endpoints []*registry.NetworkServiceEndpoint, targetEndpoint, targetNSManager string) *registry.NetworkServiceEndpoint {
-	// find matching endpoint in list
+	// find matching endpoint in list, endpoints hosted by different managers may have the same name
 	for _, candidate := range endpoints {
-		if candidate.GetName() == targetEndpoint {
+		if candidate.GetName() == targetEndpoint && (targetNSManager == "" || candidate.GetNetworkServiceManagerName() == targetNSManager) {
 			return candidate
 		}
 	}
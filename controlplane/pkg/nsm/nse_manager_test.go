@@ -0,0 +1,550 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/empty"
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/networkservice"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/common"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/properties"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+const nse3Name = "nse-3"
+
+type nseManagerTestData struct {
+	*healTestData
+
+	nseManager *nseManager
+	// endpoints - endpoints discovered by NSE manager under test.
+	endpoints []*registry.NSERegistration
+}
+
+// testDataOption - configures NSE manager under test and its environment.
+type testDataOption func(data *nseManagerTestData)
+
+func newNseManagerTestData(options ...testDataOption) *nseManagerTestData {
+	data := &nseManagerTestData{
+		healTestData: newHealTestData(),
+	}
+	data.nseManager = newNseManager(data.serviceRegistry, data.model, properties.NewNsmProperties())
+	for _, option := range options {
+		option(data)
+	}
+	return data
+}
+
+// withManagerOptions - applies options to NSE manager under test.
+func withManagerOptions(options ...NseManagerOption) testDataOption {
+	return func(data *nseManagerTestData) {
+		for _, option := range options {
+			option(data.nseManager)
+		}
+	}
+}
+
+// withSelectionHistory - keeps size last selections of each connection.
+func withSelectionHistory(size int) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.props.SelectionHistorySize = size
+	}
+}
+
+// selectorModel - model with selector replaced.
+type selectorModel struct {
+	model.Model
+	selector selector.Selector
+}
+
+func (m *selectorModel) GetSelector() selector.Selector {
+	return m.selector
+}
+
+// withSelector - selects endpoints with endpointSelector instead of the model one.
+func withSelector(endpointSelector selector.Selector) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.model = &selectorModel{Model: data.model, selector: endpointSelector}
+	}
+}
+
+// selectorStub - configurable selector, selects endpoint pick returns if it is set, otherwise the best scored one,
+// the first of equally scored. Scores candidates with scoreCandidate if it is set, otherwise by endpoint name with
+// scores, missing ones are scored 0. Endpoints and candidates it was offered last are recorded.
+type selectorStub struct {
+	sync.Mutex
+	pick           func(endpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint
+	scores         map[string]float64
+	scoreCandidate func(candidate *selector.Candidate) float64
+	offered        []string
+	candidates     []*selector.Candidate
+}
+
+func (s *selectorStub) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	s.Lock()
+	s.offered = nil
+	for _, endpoint := range endpoints {
+		s.offered = append(s.offered, endpoint.GetName())
+	}
+	s.Unlock()
+	if s.pick != nil {
+		return s.pick(endpoints)
+	}
+	var best *registry.NetworkServiceEndpoint
+	for _, endpoint := range endpoints {
+		if best == nil || s.scores[endpoint.GetName()] > s.scores[best.GetName()] {
+			best = endpoint
+		}
+	}
+	return best
+}
+
+func (s *selectorStub) ScoreEndpoints(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint) []float64 {
+	result := make([]float64, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		result = append(result, s.scores[endpoint.GetName()])
+	}
+	return result
+}
+
+func (s *selectorStub) ScoreCandidates(requestConnection *connection.Connection, ns *registry.NetworkService, candidates []*selector.Candidate) []float64 {
+	s.Lock()
+	s.candidates = candidates
+	s.Unlock()
+	result := make([]float64, 0, len(candidates))
+	for _, candidate := range candidates {
+		if s.scoreCandidate != nil {
+			result = append(result, s.scoreCandidate(candidate))
+		} else {
+			result = append(result, s.scores[candidate.Endpoint.GetName()])
+		}
+	}
+	return result
+}
+
+// lastEndpoint - picks the last endpoint, if any.
+func lastEndpoint(endpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	if len(endpoints) == 0 {
+		return nil
+	}
+	return endpoints[len(endpoints)-1]
+}
+
+// roundRobin - returns pick selecting endpoints in turn.
+func roundRobin() func(endpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	roundRobinSelector := selector.NewRoundRobinSelector()
+	return func(endpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+		return roundRobinSelector.SelectEndpoint(nil, nil, endpoints)
+	}
+}
+
+// noEndpoint - picks none of endpoints.
+func noEndpoint(endpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return nil
+}
+
+// withEndpoints - adds endpoints with given names on nsm to discovered ones.
+func withEndpoints(nsm string, names ...string) testDataOption {
+	return func(data *nseManagerTestData) {
+		endpoints := append([]*registry.NSERegistration{}, data.endpoints...)
+		for _, name := range names {
+			endpoints = append(endpoints, data.createEndpoint(name, nsm))
+		}
+		data.setDiscoveredEndpoints(endpoints...)
+	}
+}
+
+// withSpreadEndpoints - adds endpoints with given names to discovered ones, i-th of them on manager nsm-<i>.
+func withSpreadEndpoints(names ...string) testDataOption {
+	return func(data *nseManagerTestData) {
+		for i, name := range names {
+			withEndpoints(fmt.Sprintf("nsm-%d", i+1), name)(data)
+		}
+	}
+}
+
+// withLocalEndpoints - adds endpoints with given names to the model and to discovered ones.
+func withLocalEndpoints(names ...string) testDataOption {
+	return func(data *nseManagerTestData) {
+		withEndpoints(localNSMName, names...)(data)
+		for _, endpoint := range data.endpoints[len(data.endpoints)-len(names):] {
+			data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: endpoint})
+		}
+	}
+}
+
+// withClientConnection - adds client connection with connectionID to the first discovered endpoint.
+func withClientConnection(connectionID string) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.model.AddClientConnection(context.Background(), &model.ClientConnection{
+			ConnectionID: connectionID,
+			Endpoint:     data.endpoints[0],
+		})
+	}
+}
+
+// withUnreachableManagers - fails dials of remote managers with given names.
+func withUnreachableManagers(names ...string) testDataOption {
+	return func(data *nseManagerTestData) {
+		if data.serviceRegistry.unreachable == nil {
+			data.serviceRegistry.unreachable = map[string]bool{}
+		}
+		for _, name := range names {
+			data.serviceRegistry.unreachable[name] = true
+		}
+	}
+}
+
+// withHangingDials - remote manager dials hang until serviceRegistry.hang is closed.
+func withHangingDials(data *nseManagerTestData) {
+	data.serviceRegistry.hang = make(chan struct{})
+}
+
+func (data *nseManagerTestData) setDiscoveredEndpoints(nses ...*registry.NSERegistration) {
+	data.endpoints = nses
+	data.serviceRegistry.discoveryClient.response = data.createFindNetworkServiceResponse(nses...)
+}
+
+func (data *nseManagerTestData) ignores(nses ...*registry.NSERegistration) map[registry.EndpointNSMName]*registry.NSERegistration {
+	result := map[registry.EndpointNSMName]*registry.NSERegistration{}
+	for _, nse := range nses {
+		result[nse.GetEndpointNSMName()] = nse
+	}
+	return result
+}
+
+type networkServiceClientStub struct {
+	// dialCtx - context client was dialed with, client does not work once it is done.
+	dialCtx context.Context
+}
+
+func (stub *networkServiceClientStub) Request(ctx context.Context, in *networkservice.NetworkServiceRequest, opts ...grpc.CallOption) (*connection.Connection, error) {
+	if stub.dialCtx != nil && stub.dialCtx.Err() != nil {
+		return nil, stub.dialCtx.Err()
+	}
+	return in.GetConnection(), nil
+}
+
+func (stub *networkServiceClientStub) Close(ctx context.Context, in *connection.Connection, opts ...grpc.CallOption) (*empty.Empty, error) {
+	return &empty.Empty{}, nil
+}
+
+func (stub *serviceRegistryStub) RemoteNetworkServiceClient(ctx context.Context, nsm *registry.NetworkServiceManager, opts ...grpc.DialOption) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+	stub.Lock()
+	stub.remoteDials = append(stub.remoteDials, nsm)
+	stub.dialOptions = append(stub.dialOptions, opts)
+	stub.activeDials++
+	if stub.activeDials > stub.maxActiveDials {
+		stub.maxActiveDials = stub.activeDials
+	}
+	stub.Unlock()
+	defer func() {
+		stub.Lock()
+		defer stub.Unlock()
+		stub.activeDials--
+	}()
+
+	if stub.hang != nil {
+		<-stub.hang
+		return nil, nil, context.Canceled
+	}
+	if stub.dialDelay > 0 {
+		<-time.After(stub.dialDelay)
+	}
+	if stub.unreachable[nsm.GetName()] {
+		return nil, nil, errors.Errorf("%s is not reachable", nsm.GetName())
+	}
+	if stub.remoteClientError != nil {
+		return nil, nil, stub.remoteClientError
+	}
+	return &networkServiceClientStub{dialCtx: ctx}, nil, nil
+}
+
+func (stub *serviceRegistryStub) dialCount(name string) int {
+	stub.Lock()
+	defer stub.Unlock()
+	count := 0
+	for _, nsm := range stub.remoteDials {
+		if nsm.GetName() == name {
+			count++
+		}
+	}
+	return count
+}
+
+func (stub *serviceRegistryStub) EndpointConnection(ctx context.Context, endpoint *model.Endpoint) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+	stub.Lock()
+	defer stub.Unlock()
+	stub.endpointCtx = ctx
+	stub.endpointCtxErr = ctx.Err()
+	if stub.endpointError != nil {
+		return nil, nil, stub.endpointError
+	}
+	return &networkServiceClientStub{}, nil, nil
+}
+
+// requestOption - configures request connection of test.
+type requestOption func(requestConnection *connection.Connection)
+
+// withLabel - sets label of request.
+func withLabel(key, value string) requestOption {
+	return func(requestConnection *connection.Connection) {
+		if requestConnection.Labels == nil {
+			requestConnection.Labels = map[string]string{}
+		}
+		requestConnection.Labels[key] = value
+	}
+}
+
+// withID - sets connection id of request.
+func withID(id string) requestOption {
+	return func(requestConnection *connection.Connection) {
+		requestConnection.Id = id
+	}
+}
+
+// withNetworkService - requests networkService instead of the test one.
+func withNetworkService(networkService string) requestOption {
+	return func(requestConnection *connection.Connection) {
+		requestConnection.NetworkService = networkService
+	}
+}
+
+func newTestRequestConnection(options ...requestOption) *connection.Connection {
+	requestConnection := &connection.Connection{
+		NetworkService: networkServiceName,
+	}
+	for _, option := range options {
+		option(requestConnection)
+	}
+	return requestConnection
+}
+
+func newTargetedRequestConnection(nse, nsm string, options ...requestOption) *connection.Connection {
+	requestConnection := newTestRequestConnection(options...)
+	requestConnection.NetworkServiceEndpointName = nse
+	requestConnection.Path = common.Strings2Path(localNSMName, nsm)
+	return requestConnection
+}
+
+func TestGetEndpoint_SelectsNotIgnored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1))
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(endpoint.GetNetworkServiceManager().GetName()).To(Equal(remoteNSMName))
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1, nse2))
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestGetEndpoint_TargetEndpointOnTargetManager(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	otherNSMName := "nsm-other"
+
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, otherNSMName), data.createEndpoint(nse1Name, remoteNSMName))
+
+	for _, nsmName := range []string{remoteNSMName, otherNSMName} {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, nsmName), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+		g.Expect(endpoint.GetNetworkServiceManager().GetName()).To(Equal(nsmName))
+	}
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, "nsm-unknown"), nil)
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestGetTargetEndpoint_AnyManager(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	endpoints := []*registry.NetworkServiceEndpoint{
+		data.createEndpoint(nse1Name, remoteNSMName).GetNetworkServiceEndpoint(),
+		data.createEndpoint(nse2Name, remoteNSMName).GetNetworkServiceEndpoint(),
+	}
+
+	g.Expect(data.nseManager.getTargetEndpoint(endpoints, nse2Name, "")).To(Equal(endpoints[1]))
+	g.Expect(data.nseManager.getTargetEndpoint(endpoints, nse2Name, localNSMName)).To(BeNil())
+}
+
+func TestGetEndpoint_DiscoveryClientProvider(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.serviceRegistry.error = errors.New("service registry should not be used")
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	data.withScriptedDiscovery().script(networkServiceName, discoveryStep{response: data.createFindNetworkServiceResponse(nse1)})
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), &connection.Connection{NetworkService: "unknown"}, nil)
+	g.Expect(err).NotTo(BeNil())
+}
+
+func TestGetEndpoint_NsmNotInitialized(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.model.SetNsm(nil)
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, localNSMName))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrNSMNotInitialized)).To(BeTrue())
+
+	data.nseManager.props.AllowUninitializedNSM = true
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, localNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+}
+
+func TestIsLocalEndpoint_NsmNotInitialized(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	endpoint := data.createEndpoint(nse1Name, localNSMName)
+	g.Expect(data.nseManager.IsLocalEndpoint(endpoint)).To(BeTrue())
+
+	data.model.SetNsm(nil)
+	g.Expect(data.nseManager.IsLocalEndpoint(endpoint)).To(BeFalse())
+}
+
+func TestEndpointLocality(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	isLocal, localNsmName, endpointNsmName := data.nseManager.EndpointLocality(data.createEndpoint(nse1Name, localNSMName))
+	g.Expect(isLocal).To(BeTrue())
+	g.Expect(localNsmName).To(Equal(localNSMName))
+	g.Expect(endpointNsmName).To(Equal(localNSMName))
+
+	isLocal, localNsmName, endpointNsmName = data.nseManager.EndpointLocality(data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(isLocal).To(BeFalse())
+	g.Expect(localNsmName).To(Equal(localNSMName))
+	g.Expect(endpointNsmName).To(Equal(remoteNSMName))
+
+	data.model.SetNsm(nil)
+	isLocal, localNsmName, endpointNsmName = data.nseManager.EndpointLocality(data.createEndpoint(nse1Name, localNSMName))
+	g.Expect(isLocal).To(BeFalse())
+	g.Expect(localNsmName).To(BeEmpty())
+	g.Expect(endpointNsmName).To(Equal(localNSMName))
+}
+
+func TestGetEndpoint_WeightedSelectorExcludesIgnored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	withSelector(selector.NewWeightedSelector())(data)
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse1.NetworkServiceEndpoint.Labels = map[string]string{selector.CapacityLabel: "100"}
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse2.NetworkServiceEndpoint.Labels = map[string]string{selector.CapacityLabel: "1"}
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1))
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestCheckUpdateNSEWithError(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+
+	g.Expect(data.nseManager.CheckUpdateNSEWithError(context.Background(), endpoint)).To(BeNil())
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), endpoint)).To(BeTrue())
+
+	data.serviceRegistry.remoteClientError = errors.New("tls: handshake failure")
+	err := data.nseManager.CheckUpdateNSEWithError(context.Background(), endpoint)
+	g.Expect(err).To(Equal(data.serviceRegistry.remoteClientError))
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), endpoint)).To(BeFalse())
+}
+
+func TestGetEndpoint_EndpointNotFoundErrors(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelector(&selectorStub{pick: noEndpoint}), withEndpoints(remoteNSMName, nse1Name))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeTrue())
+	g.Expect(err.Error()).To(Equal("failed to find NSE for NetworkService " + networkServiceName + ". Total NSEs: 1, candidates: 1, ignored: 0"))
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse2Name, remoteNSMName), nil)
+	g.Expect(errors.Is(err, ErrTargetEndpointNotFound)).To(BeTrue())
+	var notFound *EndpointNotFoundError
+	g.Expect(errors.As(err, &notFound)).To(BeTrue())
+	g.Expect(notFound.Endpoint).To(Equal(nse2Name))
+	g.Expect(notFound.NetworkServiceManager).To(Equal(remoteNSMName))
+	g.Expect(err.Error()).To(Equal("failed to find targeted NSE " + nse2Name + " (NSMgr=" + remoteNSMName + ") for NetworkService " +
+		networkServiceName + ". Checked: 1 endpoints"))
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse2Name, localNSMName), nil)
+	g.Expect(errors.Is(err, ErrLocalEndpointNotFound)).To(BeTrue())
+	g.Expect(errors.Is(err, ErrTargetEndpointNotFound)).To(BeFalse())
+	g.Expect(err.Error()).To(Equal("Could not find endpoint with name: " + nse2Name + " at local registry"))
+}
+
+func TestGetEndpoint_NotFoundCounts(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelector(&selectorStub{pick: noEndpoint}), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	nse1, nse2 := data.endpoints[0], data.endpoints[1]
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1, nse2))
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeTrue())
+	g.Expect(err.Error()).To(Equal("failed to find NSE for NetworkService " + networkServiceName + ". Total NSEs: 3, candidates: 1, ignored: 2"))
+}
+
+func TestFilterEndpoints_SortedOrder(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	var endpoints []*registry.NetworkServiceEndpoint
+	managers := map[string]*registry.NetworkServiceManager{}
+	for i, nseName := range []string{nse1Name, nse2Name, nse3Name, "nse-4", "nse-5"} {
+		nsmName := []string{remoteNSMName, localNSMName}[i%2]
+		registration := data.createEndpoint(nseName, nsmName)
+		endpoints = append(endpoints, registration.GetNetworkServiceEndpoint())
+		managers[nsmName] = registration.GetNetworkServiceManager()
+	}
+
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		random.Shuffle(len(endpoints), func(i, j int) { endpoints[i], endpoints[j] = endpoints[j], endpoints[i] })
+		result, err := data.nseManager.filterEndpoints(newTestRequestConnection(), endpoints, managers, nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpointNames(result)).To(Equal([]string{nse1Name, nse2Name, nse3Name, "nse-4", "nse-5"}))
+	}
+}
+
+func TestSortEndpoints_SameNameByManager(t *testing.T) {
+	g := NewWithT(t)
+	endpoints := []*registry.NetworkServiceEndpoint{
+		{Name: nse2Name, NetworkServiceManagerName: localNSMName},
+		{Name: nse1Name, NetworkServiceManagerName: remoteNSMName},
+		{Name: nse1Name, NetworkServiceManagerName: localNSMName},
+	}
+
+	sortEndpoints(endpoints)
+	g.Expect(endpoints).To(Equal([]*registry.NetworkServiceEndpoint{
+		{Name: nse1Name, NetworkServiceManagerName: localNSMName},
+		{Name: nse1Name, NetworkServiceManagerName: remoteNSMName},
+		{Name: nse2Name, NetworkServiceManagerName: localNSMName},
+	}))
+}
@@ -102,13 +102,9 @@ func (srv *networkServiceManager) GetHealProperties() *properties.Properties {
 }
 
 // NewNetworkServiceManager creates an instance of NetworkServiceManager
-func NewNetworkServiceManager(ctx context.Context, model model.Model, serviceRegistry serviceregistry.ServiceRegistry) nsm.NetworkServiceManager {
+func NewNetworkServiceManager(ctx context.Context, model model.Model, serviceRegistry serviceregistry.ServiceRegistry, options ...NseManagerOption) nsm.NetworkServiceManager {
 	properties := properties.NewNsmProperties()
-	nseManager := &nseManager{
-		serviceRegistry: serviceRegistry,
-		model:           model,
-		props:           properties,
-	}
+	nseManager := newNseManager(serviceRegistry, model, properties, options...)
 
 	srv := &networkServiceManager{
 		serviceRegistry:  serviceRegistry,
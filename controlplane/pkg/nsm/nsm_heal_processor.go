@@ -389,12 +389,14 @@ func (p *healProcessor) nseIsNewAndAvailable(ctx context.Context, endpointName s
 	}
 
 	// Check remote is accessible.
-	if p.nseManager.CheckUpdateNSE(ctx, reg) {
+	err := p.nseManager.CheckUpdateNSEWithError(ctx, reg)
+	if err == nil {
 		logrus.Infof("NSE is available and Remote NSMD is accessible. %s.", reg.NetworkServiceManager.Url)
 		// We are able to connect to NSM with required NSE
 		return true
 	}
 
+	logrus.Infof("NSE %s is not available: %v", reg.GetEndpointNSMName(), err)
 	return false
 }
 
@@ -406,12 +408,14 @@ func (p *healProcessor) nseIsSameAndAvailable(ctx context.Context, endpointName
 	// Our endpoint, we need to check if it is remote one and NSM is accessible.
 
 	// Check remote is accessible.
-	if p.nseManager.CheckUpdateNSE(ctx, reg) {
+	err := p.nseManager.CheckUpdateNSEWithError(ctx, reg)
+	if err == nil {
 		logrus.Infof("NSE is available and Remote NSMD is accessible. %s.", reg.NetworkServiceManager.Url)
 		// We are able to connect to NSM with required NSE
 		return true
 	}
 
+	logrus.Infof("NSE %s is not available: %v", reg.GetEndpointNSMName(), err)
 	return false
 }
 
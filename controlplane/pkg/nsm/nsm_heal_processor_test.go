@@ -2,6 +2,7 @@ package nsm
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -288,9 +289,11 @@ func TestHealDstDown_LocalClientRemoteEndpoint_NoNSEFound(t *testing.T) {
 type discoveryClientStub struct {
 	response *registry.FindNetworkServiceResponse
 	error    error
+	calls    int
 }
 
 func (stub *discoveryClientStub) FindNetworkService(ctx net_context.Context, in *registry.FindNetworkServiceRequest, opts ...grpc.CallOption) (*registry.FindNetworkServiceResponse, error) {
+	stub.calls++
 	if in.GetNetworkServiceName() != networkServiceName {
 		return nil, errors.New("wrong Network Service name")
 	}
@@ -301,6 +304,26 @@ type serviceRegistryStub struct {
 	discoveryClient *discoveryClientStub
 	error           error
 
+	// remoteClientError - error every remote NSMgr dial fails with.
+	remoteClientError error
+	// unreachable - names of remote NSMgrs dials of which fail.
+	unreachable map[string]bool
+	// dialDelay - time every remote NSMgr dial takes.
+	dialDelay time.Duration
+	// hang - if set, remote NSMgr dials hang until it is closed.
+	hang chan struct{}
+	// endpointError - error every local endpoint connection fails with.
+	endpointError error
+
+	sync.Mutex
+	remoteDials    []*registry.NetworkServiceManager
+	dialOptions    [][]grpc.DialOption
+	activeDials    int
+	maxActiveDials int
+	// endpointCtx, endpointCtxErr - context of the last local endpoint connection and its error at connect time.
+	endpointCtx    context.Context
+	endpointCtxErr error
+
 	serviceregistry.ServiceRegistry
 }
 
@@ -436,6 +459,19 @@ func (stub *nseManagerStub) CheckUpdateNSE(ctx context.Context, reg *registry.NS
 	return false
 }
 
+func (stub *nseManagerStub) CheckUpdateNSEWithError(ctx context.Context, reg *registry.NSERegistration) error {
+	if !stub.CheckUpdateNSE(ctx, reg) {
+		return errors.Errorf("endpoint %s is not available", reg.GetEndpointNSMName())
+	}
+	return nil
+}
+
+func (stub *nseManagerStub) ReportRTT(endpoint registry.EndpointNSMName, rtt time.Duration) {
+}
+
+func (stub *nseManagerStub) ReportSLAViolation(endpointName, connectionID string) {
+}
+
 func (data *healTestData) createEndpoint(nse, nsm string) *registry.NSERegistration {
 	return &registry.NSERegistration{
 		NetworkService: &registry.NetworkService{
@@ -0,0 +1,71 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strconv"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+const (
+	// EndpointCPULabel - endpoint label with its CPU utilization from 0 to 1, used when no load report is known.
+	EndpointCPULabel = "nsm/cpu"
+	// EndpointQueueDepthLabel - endpoint label with count of its queued requests, used when no load report is known.
+	EndpointQueueDepthLabel = "nsm/queue-depth"
+)
+
+// OrcaLoadProvider - provides last ORCA-style load reports of endpoints, collected from a side channel.
+type OrcaLoadProvider interface {
+	LoadReport(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) (*selector.LoadReport, bool)
+}
+
+type noopOrcaLoadProvider struct{}
+
+func (noopOrcaLoadProvider) LoadReport(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) (*selector.LoadReport, bool) {
+	return nil, false
+}
+
+// WithOrcaLoadProvider - use load reports of provider for load aware selection, endpoints without reports fall back
+// to load labels.
+func WithOrcaLoadProvider(provider OrcaLoadProvider) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.loadProvider = provider
+	}
+}
+
+// loadReport - returns load reported for endpoint, or load from its labels, nil if neither is known.
+func (nsem *nseManager) loadReport(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) *selector.LoadReport {
+	if report, ok := nsem.loadProvider.LoadReport(endpoint, manager); ok {
+		return report
+	}
+	return labelLoadReport(endpoint)
+}
+
+func labelLoadReport(endpoint *registry.NetworkServiceEndpoint) *selector.LoadReport {
+	labels := endpoint.GetLabels()
+	cpu, cpuErr := strconv.ParseFloat(labels[EndpointCPULabel], 64)
+	queueDepth, queueErr := strconv.Atoi(labels[EndpointQueueDepthLabel])
+	if cpuErr != nil && queueErr != nil {
+		return nil
+	}
+	return &selector.LoadReport{
+		CPUUtilization: cpu,
+		QueueDepth:     queueDepth,
+	}
+}
@@ -0,0 +1,72 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+type orcaLoadProviderStub struct {
+	reports map[string]*selector.LoadReport
+}
+
+func (stub *orcaLoadProviderStub) LoadReport(endpoint *registry.NetworkServiceEndpoint, manager *registry.NetworkServiceManager) (*selector.LoadReport, bool) {
+	report, ok := stub.reports[endpoint.GetName()]
+	return report, ok
+}
+
+func withLeastLoadedSelector(data *nseManagerTestData) {
+	withSelector(selector.NewLexicographicSelector(selector.LeastLoadedCriterion()))(data)
+}
+
+func TestOrcaLoad_PrefersLessLoaded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLeastLoadedSelector)
+	WithOrcaLoadProvider(&orcaLoadProviderStub{reports: map[string]*selector.LoadReport{
+		nse1Name: {CPUUtilization: 0.9, QueueDepth: 10},
+		nse2Name: {CPUUtilization: 0.2, QueueDepth: 1},
+	}})(data.nseManager)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	for i := 0; i < 2; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	}
+}
+
+func TestOrcaLoad_FallsBackToLabels(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLeastLoadedSelector)
+	WithOrcaLoadProvider(&orcaLoadProviderStub{reports: map[string]*selector.LoadReport{
+		nse1Name: {CPUUtilization: 0.5},
+	}})(data.nseManager)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse2.NetworkServiceEndpoint.Labels = map[string]string{EndpointCPULabel: "0.3"}
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		nse2,
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+}
+
+func TestOrcaLoad_NoopProviderUsesLabels(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLeastLoadedSelector)
+	WithOrcaLoadProvider(noopOrcaLoadProvider{})(data.nseManager)
+
+	g.Expect(data.nseManager.loadReport(&registry.NetworkServiceEndpoint{}, nil)).To(BeNil())
+	report := data.nseManager.loadReport(&registry.NetworkServiceEndpoint{
+		Labels: map[string]string{EndpointCPULabel: "0.7", EndpointQueueDepthLabel: "3"},
+	}, nil)
+	g.Expect(report).To(Equal(&selector.LoadReport{CPUUtilization: 0.7, QueueDepth: 3}))
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// PreferredEndpointLabel - connection label with name of endpoint request prefers. Unlike targeting endpoint with
+// NetworkServiceEndpointName, preference is soft: preferred endpoint is selected only if it survives filtering,
+// otherwise selection goes on as usual. Hard target wins when request has both.
+const PreferredEndpointLabel = "nsm/preferred-endpoint"
+
+// endpointFromPreference - returns endpoint preferred by request if it is among filtered candidates, nil if request
+// has no preference or preferred endpoint is not viable.
+func (nsem *nseManager) endpointFromPreference(span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	candidates func() []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	preferred := requestConnection.GetLabels()[PreferredEndpointLabel]
+	if preferred == "" {
+		return nil
+	}
+	endpoint := findEndpoint(candidates(), preferred, "")
+	if endpoint == nil {
+		span.LogValue("preferredEndpoint", "not viable")
+		return nil
+	}
+	span.LogValue("preferredEndpoint", "selected")
+	return endpoint
+}
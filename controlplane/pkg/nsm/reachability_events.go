@@ -0,0 +1,121 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// Reachability - reachability of endpoint as last observed by endpoint manager.
+type Reachability string
+
+const (
+	// ReachabilityUnknown - endpoint was not observed yet.
+	ReachabilityUnknown Reachability = ""
+	// ReachabilityReachable - endpoint passed its last heal check.
+	ReachabilityReachable Reachability = "reachable"
+	// ReachabilityUnreachable - endpoint failed its last heal check or was quarantined.
+	ReachabilityUnreachable Reachability = "unreachable"
+	// ReachabilityRemoved - local endpoint was removed from model as not available.
+	ReachabilityRemoved Reachability = "removed"
+)
+
+// ReachabilityTransition - change of endpoint reachability.
+type ReachabilityTransition struct {
+	Endpoint registry.EndpointNSMName
+	Old      Reachability
+	New      Reachability
+	Time     time.Time
+}
+
+// reachabilityOf - returns reachability of endpoint check failed with err, nil if it passed.
+func reachabilityOf(err error) Reachability {
+	if err != nil {
+		return ReachabilityUnreachable
+	}
+	return ReachabilityReachable
+}
+
+// reachabilityEvents - last observed reachability of endpoints keyed by endpoint identity and listeners of its
+// transitions, zero value is ready to use.
+type reachabilityEvents struct {
+	sync.Mutex
+	states    map[string]Reachability
+	listeners map[<-chan ReachabilityTransition]chan ReachabilityTransition
+}
+
+// WatchReachability - registers listener of endpoint reachability transitions observed by heal checks, health
+// refreshes, quarantines and removals of local endpoints. Up to buffer transitions are buffered, transitions
+// listener has no room for are dropped, so slow listener never blocks heal. Listener must be unregistered with
+// UnwatchReachability.
+func (nsem *nseManager) WatchReachability(buffer int) <-chan ReachabilityTransition {
+	nsem.reachability.Lock()
+	defer nsem.reachability.Unlock()
+	if nsem.reachability.listeners == nil {
+		nsem.reachability.listeners = map[<-chan ReachabilityTransition]chan ReachabilityTransition{}
+	}
+	listener := make(chan ReachabilityTransition, buffer)
+	nsem.reachability.listeners[listener] = listener
+	return listener
+}
+
+// UnwatchReachability - unregisters listener and closes its channel.
+func (nsem *nseManager) UnwatchReachability(listener <-chan ReachabilityTransition) {
+	nsem.reachability.Lock()
+	defer nsem.reachability.Unlock()
+	if ch, ok := nsem.reachability.listeners[listener]; ok {
+		delete(nsem.reachability.listeners, listener)
+		close(ch)
+	}
+}
+
+// observeReachability - records reachability of endpoint and publishes it to listeners if it changed.
+func (nsem *nseManager) observeReachability(endpoint *registry.NSERegistration, state Reachability) {
+	key := nsem.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager())
+	nsem.reachability.Lock()
+	defer nsem.reachability.Unlock()
+	old := nsem.reachability.states[key]
+	if old == state {
+		return
+	}
+	if state == ReachabilityRemoved {
+		delete(nsem.reachability.states, key)
+	} else {
+		if nsem.reachability.states == nil {
+			nsem.reachability.states = map[string]Reachability{}
+		}
+		nsem.reachability.states[key] = state
+	}
+	transition := ReachabilityTransition{
+		Endpoint: endpoint.GetEndpointNSMName(),
+		Old:      old,
+		New:      state,
+		Time:     time.Now(),
+	}
+	for _, listener := range nsem.reachability.listeners {
+		select {
+		case listener <- transition:
+		default:
+			logrus.Warnf("Reachability listener is full, dropping transition of %s to %s", transition.Endpoint, state)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func expectTransition(g *WithT, listener <-chan ReachabilityTransition, endpoint *registry.NSERegistration, old, state Reachability) {
+	var transition ReachabilityTransition
+	g.Expect(listener).To(Receive(&transition))
+	g.Expect(transition.Time).NotTo(BeZero())
+	transition.Time = time.Time{}
+	g.Expect(transition).To(Equal(ReachabilityTransition{Endpoint: endpoint.GetEndpointNSMName(), Old: old, New: state}))
+}
+
+func TestReachabilityEvents_HealCheckTransitions(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	listener := data.nseManager.WatchReachability(10)
+
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), nse1)).To(BeTrue())
+	expectTransition(g, listener, nse1, ReachabilityUnknown, ReachabilityReachable)
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), nse1)).To(BeTrue())
+	g.Expect(listener).NotTo(Receive())
+
+	data.serviceRegistry.remoteClientError = errors.New("connection refused")
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), nse1)).To(BeFalse())
+	expectTransition(g, listener, nse1, ReachabilityReachable, ReachabilityUnreachable)
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), nse1)).To(BeFalse())
+	g.Expect(listener).NotTo(Receive())
+
+	data.nseManager.UnwatchReachability(listener)
+	g.Expect(listener).To(BeClosed())
+}
+
+func TestReachabilityEvents_LocalQuarantineAndRemoval(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withLocalEndpoints(nse1Name, nse2Name), withLocalQuarantine(time.Hour))
+	failed := data.endpoints[0]
+	listener := data.nseManager.WatchReachability(10)
+
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), failed)).To(BeFalse())
+	expectTransition(g, listener, failed, ReachabilityUnknown, ReachabilityUnreachable)
+	g.Expect(data.nseManager.CheckUpdateNSE(context.Background(), failed)).To(BeFalse())
+	g.Expect(data.model.GetEndpoint(nse1Name)).To(BeNil())
+	expectTransition(g, listener, failed, ReachabilityUnreachable, ReachabilityRemoved)
+	g.Expect(listener).NotTo(Receive())
+}
+
+func TestReachabilityEvents_SlowListenerDoesNotBlock(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	slow := data.nseManager.WatchReachability(0)
+	listener := data.nseManager.WatchReachability(10)
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		data.nseManager.CheckUpdateNSE(context.Background(), nse1)
+		data.nseManager.CheckUpdateNSE(context.Background(), nse2)
+	}()
+	g.Eventually(done).Should(BeClosed())
+	expectTransition(g, listener, nse1, ReachabilityUnknown, ReachabilityReachable)
+	expectTransition(g, listener, nse2, ReachabilityUnknown, ReachabilityReachable)
+	g.Expect(slow).NotTo(Receive())
+
+	data.nseManager.UnwatchReachability(slow)
+	data.nseManager.UnwatchReachability(slow)
+	g.Expect(slow).To(BeClosed())
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// RegistrationTransform - rewrites registration GetEndpoint resolved for request before it is returned, e.g. adds
+// labels of local policy. Registration is a copy transform may mutate or replace, but identity of endpoint, its name
+// and URL of its manager, must be kept. Error fails the request.
+type RegistrationTransform func(ctx context.Context, registration *registry.NSERegistration, requestConnection *connection.Connection) (*registry.NSERegistration, error)
+
+// WithRegistrationTransform - transform registrations with transform before GetEndpoint returns them.
+func WithRegistrationTransform(transform RegistrationTransform) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.transform = transform
+	}
+}
+
+// transformRegistration - transforms registration resolved for request by RegistrationTransform, if any.
+func (nsem *nseManager) transformRegistration(ctx context.Context, requestConnection *connection.Connection, endpoint *registry.NSERegistration) (*registry.NSERegistration, error) {
+	if nsem.transform == nil {
+		return endpoint, nil
+	}
+	transformed, err := nsem.transform(ctx, proto.Clone(endpoint).(*registry.NSERegistration), requestConnection)
+	if err != nil {
+		// Endpoint is not going to be connected to.
+		nsem.settleReservation(endpoint)
+		return nil, errors.Wrapf(err, "failed to transform registration of endpoint %s", endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	if transformed == nil {
+		nsem.settleReservation(endpoint)
+		return nil, errors.Errorf("transform of registration of endpoint %s returned no registration", endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	return transformed, nil
+}
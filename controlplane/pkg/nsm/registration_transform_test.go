@@ -0,0 +1,44 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestRegistrationTransform_AddsLabels(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovered := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(discovered)
+	WithRegistrationTransform(func(ctx context.Context, registration *registry.NSERegistration, requestConnection *connection.Connection) (*registry.NSERegistration, error) {
+		registration.NetworkServiceEndpoint.Labels = map[string]string{"region": "us-east"}
+		return registration, nil
+	})(data.nseManager)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetLabels()).To(Equal(map[string]string{"region": "us-east"}))
+	// Discovered endpoint is not affected.
+	g.Expect(data.serviceRegistry.discoveryClient.response.GetNetworkServiceEndpoints()[0].GetLabels()).To(BeEmpty())
+}
+
+func TestRegistrationTransform_Fails(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+	errDenied := errors.New("denied by policy")
+	WithRegistrationTransform(func(ctx context.Context, registration *registry.NSERegistration, requestConnection *connection.Connection) (*registry.NSERegistration, error) {
+		return nil, errDenied
+	})(data.nseManager)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, errDenied)).To(BeTrue())
+	g.Expect(endpoint).To(BeNil())
+}
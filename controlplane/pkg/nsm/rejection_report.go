@@ -0,0 +1,133 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// Reasons endpoint was filtered out of selection candidates.
+const (
+	RejectedDanglingManager   = "dangling manager"
+	RejectedDuplicate         = "duplicate"
+	RejectedBlackhole         = "blackhole quarantine"
+	RejectedUnreachable       = "unreachable"
+	RejectedBlacklisted       = "blacklisted"
+	RejectedLocalQuarantine   = "local endpoint quarantine"
+	RejectedDraining          = "draining"
+	RejectedApprovalDenied    = "approval denied"
+	RejectedIgnored           = "ignored"
+	RejectedManagerIgnored    = "manager ignored"
+	RejectedNotReady          = "not ready"
+	RejectedUpgrading         = "upgrading"
+	RejectedVersion           = "version"
+	RejectedLabels            = "labels"
+	RejectedManagerNotAllowed = "manager not allowed"
+	RejectedMechanisms        = "mechanisms"
+	RejectedLatencyClass      = "latency class"
+	RejectedSLAViolations     = "SLA violations"
+	RejectedFailureRate       = "failure rate"
+	RejectedTooYoung          = "too young"
+	RejectedCooldown          = "cooldown"
+	RejectedNotLocal          = "not local"
+)
+
+// RejectionReport - reason each discovered endpoint was filtered out for, by endpoint name and name of NSM hosting
+// it joined with ":". Collected only if properties.SelectionRejectionReport is set, nil report records nothing.
+type RejectionReport map[string]string
+
+func (nsem *nseManager) newRejectionReport() RejectionReport {
+	if !nsem.props.SelectionRejectionReport {
+		return nil
+	}
+	return RejectionReport{}
+}
+
+func (r RejectionReport) reject(endpoint *registry.NetworkServiceEndpoint, reason string) {
+	if r == nil {
+		return
+	}
+	r[rejectionKey(endpoint)] = reason
+}
+
+func rejectionKey(endpoint *registry.NetworkServiceEndpoint) string {
+	return endpoint.GetName() + ":" + endpoint.GetNetworkServiceManagerName()
+}
+
+// filtered - records endpoints of before missing in after as rejected for reason, returns after.
+func (r RejectionReport) filtered(reason string, before, after []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	if r == nil || len(before) == len(after) {
+		return after
+	}
+	kept := make(map[*registry.NetworkServiceEndpoint]bool, len(after))
+	for _, endpoint := range after {
+		kept[endpoint] = true
+	}
+	for _, endpoint := range before {
+		if !kept[endpoint] {
+			r.reject(endpoint, reason)
+		}
+	}
+	return after
+}
+
+// unavailableReason - tells why endpoint with key is not available for selection, once it is known it is not.
+func (nsem *nseManager) unavailableReason(duplicate bool, key string) string {
+	switch {
+	case duplicate:
+		return RejectedDuplicate
+	case nsem.quarantine.contains(key):
+		return RejectedBlackhole
+	case nsem.unreachable.contains(key):
+		return RejectedUnreachable
+	case nsem.blacklist.contains(key):
+		return RejectedBlacklisted
+	case nsem.localQuarantine.contains(key):
+		return RejectedLocalQuarantine
+	default:
+		return RejectedDraining
+	}
+}
+
+// RejectionReportError - error of selection with report of why discovered endpoints were filtered out.
+type RejectionReportError struct {
+	error
+	Report RejectionReport
+}
+
+// Unwrap - returns error of selection.
+func (e *RejectionReportError) Unwrap() error {
+	return e.error
+}
+
+func withRejections(err error, report RejectionReport) error {
+	if err == nil || report == nil {
+		return err
+	}
+	return &RejectionReportError{error: err, Report: report}
+}
+
+// RejectionsFrom - returns rejection report attached to err, if any.
+func RejectionsFrom(err error) (RejectionReport, bool) {
+	var reportErr *RejectionReportError
+	if errors.As(err, &reportErr) {
+		return reportErr.Report, true
+	}
+	return nil, false
+}
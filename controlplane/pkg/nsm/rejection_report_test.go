@@ -0,0 +1,47 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+// selectWithRejections - selects endpoint out of ignored one and one with not matching labels.
+func selectWithRejections(report bool) error {
+	data := newNseManagerTestData()
+	data.nseManager.props.MatchEndpointLabels = true
+	data.nseManager.props.SelectionRejectionReport = report
+	ignored := data.createEndpoint(nse1Name, remoteNSMName)
+	mismatched := data.createEndpoint(nse2Name, remoteNSMName)
+	mismatched.NetworkServiceEndpoint.Labels = map[string]string{"zone": "us-west"}
+	data.setDiscoveredEndpoints(ignored, mismatched)
+
+	request := newTestRequestConnection()
+	request.Labels = map[string]string{"zone": "us-east"}
+	_, err := data.nseManager.GetEndpoint(context.Background(), request, data.ignores(ignored))
+	return err
+}
+
+func TestRejectionReport_RecordsReasons(t *testing.T) {
+	g := NewWithT(t)
+	err := selectWithRejections(true)
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+
+	report, ok := RejectionsFrom(err)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(report).To(Equal(RejectionReport{
+		nse1Name + ":" + remoteNSMName: RejectedIgnored,
+		nse2Name + ":" + remoteNSMName: RejectedLabels,
+	}))
+}
+
+func TestRejectionReport_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	err := selectWithRejections(false)
+	g.Expect(errors.Is(err, ErrCandidatesExhausted)).To(BeTrue())
+
+	_, ok := RejectionsFrom(err)
+	g.Expect(ok).To(BeFalse())
+}
@@ -0,0 +1,236 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/networkservice"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type remoteClientDialFunc func(ctx context.Context) (networkservice.NetworkServiceClient, *grpc.ClientConn, error)
+
+type pooledRemoteClient struct {
+	key    string
+	client networkservice.NetworkServiceClient
+	conn   *grpc.ClientConn
+	err    error
+	dialed chan struct{}
+	refs   int
+	idle   *time.Timer
+	// cancel - cancels context client was dialed with, it lives as long as the client.
+	cancel context.CancelFunc
+}
+
+// healthy - tells if client is being dialed or its channel is usable.
+func (c *pooledRemoteClient) healthy() bool {
+	select {
+	case <-c.dialed:
+	default:
+		return true
+	}
+	if c.err != nil {
+		return false
+	}
+	if c.conn == nil { // Required for testing
+		return true
+	}
+	state := c.conn.GetState()
+	return state != connectivity.Shutdown && state != connectivity.TransientFailure
+}
+
+func (c *pooledRemoteClient) close() error {
+	defer c.cancel()
+	if c.conn == nil {
+		return nil
+	}
+	return c.conn.Close()
+}
+
+// remoteClientPool - clients of remote network service managers shared by connections to the same manager, keyed by
+// manager name, URL and credentials key, zero value is ready to use.
+type remoteClientPool struct {
+	sync.Mutex
+	entries map[string]*pooledRemoteClient
+}
+
+// remoteClientKey - returns pool key of manager dialed with credentials, nil credentials are the default ones.
+func remoteClientKey(manager *registry.NetworkServiceManager, credentials *RemoteCredentials) string {
+	key := manager.GetName() + "|" + manager.GetUrl()
+	if credentials != nil {
+		key += "|" + credentials.Key
+	}
+	return key
+}
+
+// acquire - returns pooled client with key, dialing it if there is no healthy one. Concurrent acquires of not yet
+// dialed manager share one dial, each waits for it until its ctx is done. Dial is not bound to ctx of any of them,
+// it is given connectTimeout to establish connection and its context is cancelled only when client is closed.
+// Acquired client must be released.
+func (p *remoteClientPool) acquire(ctx context.Context, key string, connectTimeout time.Duration,
+	dial remoteClientDialFunc) (*pooledRemoteClient, error) {
+	p.Lock()
+	if p.entries == nil {
+		p.entries = map[string]*pooledRemoteClient{}
+	}
+	entry, ok := p.entries[key]
+	if ok && !entry.healthy() {
+		delete(p.entries, key)
+		if entry.refs == 0 {
+			_ = entry.close()
+		}
+		ok = false
+	}
+	if !ok {
+		entry = &pooledRemoteClient{key: key, dialed: make(chan struct{})}
+		p.entries[key] = entry
+		go p.dial(key, entry, connectTimeout, dial)
+	}
+	entry.refs++
+	if entry.idle != nil {
+		entry.idle.Stop()
+		entry.idle = nil
+	}
+	p.Unlock()
+	return p.await(ctx, entry)
+}
+
+// acquireDedicated - acquire for client of its own, which is never shared with other acquires and is closed once
+// released.
+func (p *remoteClientPool) acquireDedicated(ctx context.Context, key string, connectTimeout time.Duration,
+	dial remoteClientDialFunc) (*pooledRemoteClient, error) {
+	entry := &pooledRemoteClient{key: key, dialed: make(chan struct{}), refs: 1}
+	go p.dial(key, entry, connectTimeout, dial)
+	return p.await(ctx, entry)
+}
+
+// await - waits for acquired entry to be dialed or for ctx to be done, entry is released in the latter case.
+func (p *remoteClientPool) await(ctx context.Context, entry *pooledRemoteClient) (*pooledRemoteClient, error) {
+	select {
+	case <-entry.dialed:
+	case <-ctx.Done():
+		_ = p.release(entry, 0)
+		return nil, ctx.Err()
+	}
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry, nil
+}
+
+func (p *remoteClientPool) dial(key string, entry *pooledRemoteClient, connectTimeout time.Duration, dial remoteClientDialFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	timer := time.AfterFunc(connectTimeout, cancel)
+	client, conn, err := dial(ctx)
+	timer.Stop()
+
+	p.Lock()
+	defer p.Unlock()
+	entry.client, entry.conn, entry.err, entry.cancel = client, conn, err, cancel
+	close(entry.dialed)
+	if err != nil || entry.refs == 0 {
+		// Dial failed or all acquires gave up waiting for it.
+		if p.entries[key] == entry {
+			delete(p.entries, key)
+		}
+		_ = entry.close()
+	}
+}
+
+// release - returns client to the pool, client no longer used is closed after idleTimeout, or right away if
+// idleTimeout is not positive or client was removed from the pool as not healthy.
+func (p *remoteClientPool) release(entry *pooledRemoteClient, idleTimeout time.Duration) error {
+	key := entry.key
+	p.Lock()
+	defer p.Unlock()
+	entry.refs--
+	if entry.refs > 0 {
+		return nil
+	}
+	select {
+	case <-entry.dialed:
+	default:
+		// Still dialing, client is closed once dialed.
+		if p.entries[key] == entry {
+			delete(p.entries, key)
+		}
+		return nil
+	}
+	if p.entries[key] != entry {
+		return entry.close()
+	}
+	if idleTimeout <= 0 {
+		delete(p.entries, key)
+		return entry.close()
+	}
+	entry.idle = time.AfterFunc(idleTimeout, func() {
+		p.Lock()
+		defer p.Unlock()
+		if p.entries[key] == entry && entry.refs == 0 {
+			delete(p.entries, key)
+			_ = entry.close()
+		}
+	})
+	return nil
+}
+
+// acquireRemoteClient - acquires client of remote manager dialed with its credentials, pooled only if
+// properties.RemoteClientIdleTimeout is positive. Fails with ErrManagerCircuitOpen without dialing while circuit
+// breaker of manager is open.
+func (nsem *nseManager) acquireRemoteClient(ctx context.Context, manager *registry.NetworkServiceManager) (*pooledRemoteClient, error) {
+	credentials, err := nsem.remoteCredentials(ctx, manager)
+	if err != nil {
+		return nil, err
+	}
+	breaker := nsem.props.ManagerBreakerThreshold > 0
+	if breaker && !nsem.breakers.allow(manager.GetName()) {
+		return nil, errors.Wrapf(ErrManagerCircuitOpen, "NSMgr %s", manager.GetName())
+	}
+	opts := nsem.remoteDialOptions()
+	if credentials != nil {
+		opts = append(opts, credentials.DialOptions...)
+	}
+	acquire := nsem.remoteClients.acquire
+	if nsem.props.RemoteClientIdleTimeout <= 0 {
+		// Nothing is kept for reuse, so connections are not shared either.
+		acquire = nsem.remoteClients.acquireDedicated
+	}
+	pooled, err := acquire(ctx, remoteClientKey(manager, credentials), nsem.props.HealRequestConnectTimeout,
+		func(ctx context.Context) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+			return nsem.serviceRegistry.RemoteNetworkServiceClient(ctx, manager, opts...)
+		})
+	if !breaker {
+		return pooled, err
+	}
+	if err != nil && ctx.Err() == context.Canceled {
+		// Caller gave up waiting for dial, it says nothing about the manager.
+		return pooled, err
+	}
+	if err != nil {
+		nsem.breakers.failure(manager.GetName(), nsem.props.ManagerBreakerThreshold, nsem.props.ManagerBreakerCooldown)
+	} else {
+		nsem.breakers.success(manager.GetName())
+	}
+	return pooled, err
+}
@@ -0,0 +1,146 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/networkservice"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestCreateNSEClient_ReusesRemoteClient(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.RemoteClientIdleTimeout = 50 * time.Millisecond
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	client1, err := data.nseManager.CreateNSEClient(context.Background(), nse1)
+	g.Expect(err).To(BeNil())
+	client2, err := data.nseManager.CreateNSEClient(context.Background(), nse2)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+
+	g.Expect(client1.Cleanup()).To(BeNil())
+	g.Expect(client2.Cleanup()).To(BeNil())
+	client3, err := data.nseManager.CreateNSEClient(context.Background(), nse1)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(1))
+
+	// Idle client is evicted.
+	g.Expect(client3.Cleanup()).To(BeNil())
+	time.Sleep(100 * time.Millisecond)
+	_, err = data.nseManager.CreateNSEClient(context.Background(), nse1)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+}
+
+func TestCreateNSEClient_NotPooledByDefault(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+
+	client1, err := data.nseManager.CreateNSEClient(context.Background(), nse1)
+	g.Expect(err).To(BeNil())
+	client2, err := data.nseManager.CreateNSEClient(context.Background(), nse2)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.remoteDials).To(HaveLen(2))
+	g.Expect(client1.Cleanup()).To(BeNil())
+	g.Expect(client2.Cleanup()).To(BeNil())
+}
+
+func TestRemoteClientPool_ConcurrentAcquiresShareDial(t *testing.T) {
+	g := NewWithT(t)
+	pool := &remoteClientPool{}
+	manager := &registry.NetworkServiceManager{Name: remoteNSMName, Url: "remote:5001"}
+	dials := int32(0)
+	release := make(chan struct{})
+	dial := func(ctx context.Context) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+		atomic.AddInt32(&dials, 1)
+		<-release
+		return &networkServiceClientStub{}, nil, nil
+	}
+
+	wg := sync.WaitGroup{}
+	clients := make([]*pooledRemoteClient, 10)
+	for i := range clients {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client, err := pool.acquire(context.Background(), remoteClientKey(manager, nil), time.Second, dial)
+			g.Expect(err).To(BeNil())
+			clients[i] = client
+		}(i)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	g.Expect(atomic.LoadInt32(&dials)).To(Equal(int32(1)))
+	for _, client := range clients {
+		g.Expect(client).To(BeIdenticalTo(clients[0]))
+	}
+}
+
+func TestRemoteClientPool_FailedDialNotPooled(t *testing.T) {
+	g := NewWithT(t)
+	pool := &remoteClientPool{}
+	manager := &registry.NetworkServiceManager{Name: remoteNSMName}
+	dialErr := errors.New("connection refused")
+	dials := 0
+	dial := func(ctx context.Context) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+		dials++
+		return nil, nil, dialErr
+	}
+
+	_, err := pool.acquire(context.Background(), remoteClientKey(manager, nil), time.Second, dial)
+	g.Expect(err).To(Equal(dialErr))
+	_, err = pool.acquire(context.Background(), remoteClientKey(manager, nil), time.Second, dial)
+	g.Expect(err).To(Equal(dialErr))
+	g.Expect(dials).To(Equal(2))
+}
+
+func TestRemoteClientPool_AbandonedDialIsClosed(t *testing.T) {
+	g := NewWithT(t)
+	pool := &remoteClientPool{}
+	manager := &registry.NetworkServiceManager{Name: remoteNSMName}
+	release := make(chan struct{})
+	dialCtxs := make(chan context.Context, 1)
+	dial := func(ctx context.Context) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+		dialCtxs <- ctx
+		<-release
+		return &networkServiceClientStub{}, nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := pool.acquire(ctx, remoteClientKey(manager, nil), time.Second, dial)
+	g.Expect(err).To(Equal(context.DeadlineExceeded))
+
+	close(release)
+	dialCtx := <-dialCtxs
+	g.Eventually(dialCtx.Done).Should(BeClosed())
+}
+
+func TestCreateNSEClient_OutlivesConnectTimeout(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.HealRequestConnectTimeout = 10 * time.Millisecond
+	endpoint := data.createEndpoint(nse1Name, remoteNSMName)
+
+	client, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).To(BeNil())
+	time.Sleep(50 * time.Millisecond)
+	_, err = client.Request(context.Background(), &networkservice.NetworkServiceRequest{Connection: newTestRequestConnection()})
+	g.Expect(err).To(BeNil())
+
+	g.Expect(client.Cleanup()).To(BeNil())
+}
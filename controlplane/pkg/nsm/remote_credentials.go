@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type remoteCredentialsKey struct{}
+
+// RemoteCredentials - connection-specific transport options remote manager is dialed with in addition to the
+// default ones, e.g. mTLS identity of client namespace.
+type RemoteCredentials struct {
+	// Key - identifies credentials, remote clients are shared only by connections with the same key.
+	Key string
+	// DialOptions - appended to default dial options, so they take precedence over them.
+	DialOptions []grpc.DialOption
+}
+
+// RemoteCredentialsResolver - returns credentials remote manager is dialed with, nil for the default dialing.
+type RemoteCredentialsResolver func(ctx context.Context, manager *registry.NetworkServiceManager) (*RemoteCredentials, error)
+
+// WithRemoteCredentialsResolver - resolves credentials of remote manager dials not given pre-resolved ones.
+func WithRemoteCredentialsResolver(resolver RemoteCredentialsResolver) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.credentialsResolver = resolver
+	}
+}
+
+// WithRemoteCredentials - asks CreateNSEClient to dial remote manager with pre-resolved credentials instead of
+// resolving them. Connections to local endpoints are not affected.
+func WithRemoteCredentials(ctx context.Context, credentials *RemoteCredentials) context.Context {
+	return context.WithValue(ctx, remoteCredentialsKey{}, credentials)
+}
+
+// remoteCredentials - returns credentials manager is dialed with, nil if there are neither pre-resolved
+// credentials nor a resolver.
+func (nsem *nseManager) remoteCredentials(ctx context.Context, manager *registry.NetworkServiceManager) (*RemoteCredentials, error) {
+	if credentials, ok := ctx.Value(remoteCredentialsKey{}).(*RemoteCredentials); ok && credentials != nil {
+		return credentials, nil
+	}
+	if nsem.credentialsResolver == nil {
+		return nil, nil
+	}
+	credentials, err := nsem.credentialsResolver(ctx, manager)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve credentials of NSMgr %s", manager.GetName())
+	}
+	return credentials, nil
+}
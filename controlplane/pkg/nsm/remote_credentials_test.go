@@ -0,0 +1,92 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type tenantTestKey struct{}
+
+type tokenCredentials struct {
+	token string
+}
+
+func (c *tokenCredentials) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	return map[string]string{"authorization": c.token}, nil
+}
+
+func (c *tokenCredentials) RequireTransportSecurity() bool {
+	return false
+}
+
+func TestRemoteCredentials_DefaultDialUnchanged(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.dialOptions).To(HaveLen(1))
+	g.Expect(data.serviceRegistry.dialOptions[0]).To(BeEmpty())
+}
+
+func TestRemoteCredentials_PreResolvedReachDial(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.RemoteKeepaliveTime = 10 * time.Second
+	option := grpc.WithPerRPCCredentials(&tokenCredentials{token: "tenant-a"})
+	ctx := WithRemoteCredentials(context.Background(), &RemoteCredentials{Key: "tenant-a", DialOptions: []grpc.DialOption{option}})
+
+	_, err := data.nseManager.CreateNSEClient(ctx, data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.dialOptions).To(HaveLen(1))
+	// Default options are kept, credentials come after them.
+	g.Expect(data.serviceRegistry.dialOptions[0]).To(HaveLen(2))
+	g.Expect(data.serviceRegistry.dialOptions[0][1]).To(BeIdenticalTo(option))
+}
+
+func TestRemoteCredentials_ResolverKeysPooledClients(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.RemoteClientIdleTimeout = time.Minute
+	options := map[string]grpc.DialOption{
+		"tenant-a": grpc.WithPerRPCCredentials(&tokenCredentials{token: "tenant-a"}),
+		"tenant-b": grpc.WithPerRPCCredentials(&tokenCredentials{token: "tenant-b"}),
+	}
+	var resolved []string
+	data.nseManager.credentialsResolver = func(ctx context.Context, manager *registry.NetworkServiceManager) (*RemoteCredentials, error) {
+		resolved = append(resolved, manager.GetName())
+		tenant := ctx.Value(tenantTestKey{}).(string)
+		return &RemoteCredentials{Key: tenant, DialOptions: []grpc.DialOption{options[tenant]}}, nil
+	}
+	nse := data.createEndpoint(nse1Name, remoteNSMName)
+
+	for _, tenant := range []string{"tenant-a", "tenant-b", "tenant-a"} {
+		_, err := data.nseManager.CreateNSEClient(context.WithValue(context.Background(), tenantTestKey{}, tenant), nse)
+		g.Expect(err).To(BeNil())
+	}
+	g.Expect(resolved).To(Equal([]string{remoteNSMName, remoteNSMName, remoteNSMName}))
+	// Client dialed with credentials of tenant-a is not shared with tenant-b.
+	g.Expect(data.serviceRegistry.dialOptions).To(HaveLen(2))
+	g.Expect(data.serviceRegistry.dialOptions[0][0]).To(BeIdenticalTo(options["tenant-a"]))
+	g.Expect(data.serviceRegistry.dialOptions[1][0]).To(BeIdenticalTo(options["tenant-b"]))
+}
+
+func TestRemoteCredentials_ResolverFailureDoesNotDial(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	resolveErr := errors.New("identity not found")
+	data.nseManager.credentialsResolver = func(ctx context.Context, manager *registry.NetworkServiceManager) (*RemoteCredentials, error) {
+		return nil, resolveErr
+	}
+
+	_, err := data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse1Name, remoteNSMName))
+	g.Expect(errors.Is(err, resolveErr)).To(BeTrue())
+	g.Expect(data.serviceRegistry.dialOptions).To(BeEmpty())
+}
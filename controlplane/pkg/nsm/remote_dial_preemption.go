@@ -0,0 +1,85 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// pendingDial - remote dial which is cancelled once local endpoint of its network service appears.
+type pendingDial struct {
+	service   string
+	cancel    context.CancelFunc
+	preempted bool
+}
+
+// dialPreemptions - remote dials in flight by network service, cancelled with properties.PreemptRemoteDials when
+// ready local endpoint of the same network service is added to model.
+type dialPreemptions struct {
+	model.ListenerImpl
+	sync.Mutex
+	pending map[string]map[*pendingDial]bool
+}
+
+func newDialPreemptions(m model.Model) *dialPreemptions {
+	preemptions := &dialPreemptions{
+		pending: map[string]map[*pendingDial]bool{},
+	}
+	m.AddListener(preemptions)
+	return preemptions
+}
+
+// watch - registers remote dial of network service to be cancelled with cancel, dial must be stopped watching.
+func (p *dialPreemptions) watch(service string, cancel context.CancelFunc) *pendingDial {
+	dial := &pendingDial{service: service, cancel: cancel}
+	p.Lock()
+	defer p.Unlock()
+	if p.pending[service] == nil {
+		p.pending[service] = map[*pendingDial]bool{}
+	}
+	p.pending[service][dial] = true
+	return dial
+}
+
+// stop - stops watching dial, returns if it was preempted.
+func (p *dialPreemptions) stop(dial *pendingDial) bool {
+	p.Lock()
+	defer p.Unlock()
+	delete(p.pending[dial.service], dial)
+	if len(p.pending[dial.service]) == 0 {
+		delete(p.pending, dial.service)
+	}
+	return dial.preempted
+}
+
+// EndpointAdded - cancels remote dials of network service of added endpoint, if it is ready.
+func (p *dialPreemptions) EndpointAdded(_ context.Context, endpoint *model.Endpoint) {
+	if !isEndpointReady(endpoint.Endpoint.GetNetworkServiceEndpoint()) {
+		return
+	}
+	service := endpoint.Endpoint.GetNetworkServiceEndpoint().GetNetworkServiceName()
+	p.Lock()
+	defer p.Unlock()
+	for dial := range p.pending[service] {
+		dial.preempted = true
+		dial.cancel()
+	}
+	delete(p.pending, service)
+}
@@ -0,0 +1,82 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func withDialPreemption(data *nseManagerTestData) {
+	data.nseManager.props.PreemptRemoteDials = true
+	data.nseManager.props.ManagerBreakerThreshold = 1
+}
+
+func (data *nseManagerTestData) createNSEClientInBackground(endpoint *registry.NSERegistration) chan error {
+	result := make(chan error, 1)
+	go func() {
+		_, err := data.nseManager.CreateNSEClient(context.Background(), endpoint)
+		result <- err
+	}()
+	return result
+}
+
+func (data *nseManagerTestData) pendingDials() int {
+	data.nseManager.dialPreemptions.Lock()
+	defer data.nseManager.dialPreemptions.Unlock()
+	return len(data.nseManager.dialPreemptions.pending[networkServiceName])
+}
+
+func TestPreemptRemoteDials_LocalEndpointCancelsDial(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withHangingDials, withDialPreemption)
+	defer close(data.serviceRegistry.hang)
+	result := data.createNSEClientInBackground(data.createEndpoint(nse1Name, remoteNSMName))
+	g.Eventually(data.pendingDials).Should(Equal(1))
+
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: data.createEndpoint(nse2Name, localNSMName)})
+	var err error
+	g.Eventually(result).Should(Receive(&err))
+	g.Expect(errors.Is(err, ErrRemoteDialPreempted)).To(BeTrue())
+	g.Expect(data.pendingDials()).To(BeZero())
+	// Preempted dial is not a failure of remote NSMgr.
+	g.Expect(data.nseManager.breakers.allow(remoteNSMName)).To(BeTrue())
+}
+
+func TestPreemptRemoteDials_OtherServiceDoesNotCancel(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withHangingDials, withDialPreemption)
+	defer close(data.serviceRegistry.hang)
+	result := data.createNSEClientInBackground(data.createEndpoint(nse1Name, remoteNSMName))
+	g.Eventually(data.pendingDials).Should(Equal(1))
+
+	other := data.createEndpoint(nse2Name, localNSMName)
+	other.NetworkServiceEndpoint.NetworkServiceName = "other"
+	notReady := data.createEndpoint(nse3Name, localNSMName)
+	notReady.NetworkServiceEndpoint.Labels = map[string]string{EndpointReadyLabel: "false"}
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: other})
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: notReady})
+	g.Consistently(result, 100*time.Millisecond).ShouldNot(Receive())
+	g.Expect(data.pendingDials()).To(Equal(1))
+}
+
+func TestPreemptRemoteDials_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withHangingDials, withDialPreemption)
+	defer close(data.serviceRegistry.hang)
+	result := data.createNSEClientInBackground(data.createEndpoint(nse1Name, remoteNSMName))
+	g.Eventually(data.pendingDials).Should(Equal(1))
+	data.nseManager.props.PreemptRemoteDials = false
+
+	second := data.createNSEClientInBackground(data.createEndpoint(nse2Name, remoteNSMName))
+	g.Consistently(data.pendingDials, 100*time.Millisecond).Should(Equal(1))
+
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: data.createEndpoint(nse3Name, localNSMName)})
+	g.Eventually(result).Should(Receive())
+	g.Consistently(second, 100*time.Millisecond).ShouldNot(Receive())
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+// remoteKeepalive - keepalive parameters of connections to remote managers, false if keepalive is disabled.
+func (nsem *nseManager) remoteKeepalive() (keepalive.ClientParameters, bool) {
+	if nsem.props.RemoteKeepaliveTime <= 0 {
+		return keepalive.ClientParameters{}, false
+	}
+	return keepalive.ClientParameters{
+		Time:                nsem.props.RemoteKeepaliveTime,
+		Timeout:             nsem.props.RemoteKeepaliveTimeout,
+		PermitWithoutStream: nsem.props.RemoteKeepalivePermitWithoutStream,
+	}, true
+}
+
+// remoteDialOptions - options remote managers are dialed with, see properties.RemoteKeepaliveTime.
+func (nsem *nseManager) remoteDialOptions() []grpc.DialOption {
+	params, ok := nsem.remoteKeepalive()
+	if !ok {
+		return nil
+	}
+	return []grpc.DialOption{grpc.WithKeepaliveParams(params)}
+}
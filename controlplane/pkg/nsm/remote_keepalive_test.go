@@ -0,0 +1,46 @@
+package nsm
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc/keepalive"
+)
+
+func withRemoteKeepalive(keepaliveTime time.Duration) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.props.RemoteKeepaliveTime = keepaliveTime
+		data.nseManager.props.RemoteKeepaliveTimeout = 5 * time.Second
+		data.nseManager.props.RemoteKeepalivePermitWithoutStream = true
+	}
+}
+
+func TestRemoteKeepalive_PassedToDial(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withRemoteKeepalive(time.Minute))
+
+	params, ok := data.nseManager.remoteKeepalive()
+	g.Expect(ok).To(BeTrue())
+	g.Expect(params).To(Equal(keepalive.ClientParameters{
+		Time:                time.Minute,
+		Timeout:             5 * time.Second,
+		PermitWithoutStream: true,
+	}))
+
+	g.Expect(data.createRemoteClient()).To(BeNil())
+	g.Expect(data.serviceRegistry.dialOptions).To(HaveLen(1))
+	g.Expect(data.serviceRegistry.dialOptions[0]).To(HaveLen(1))
+}
+
+func TestRemoteKeepalive_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withRemoteKeepalive(0))
+
+	_, ok := data.nseManager.remoteKeepalive()
+	g.Expect(ok).To(BeFalse())
+
+	g.Expect(data.createRemoteClient()).To(BeNil())
+	g.Expect(data.serviceRegistry.dialOptions).To(HaveLen(1))
+	g.Expect(data.serviceRegistry.dialOptions[0]).To(BeEmpty())
+}
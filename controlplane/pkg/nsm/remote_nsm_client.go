@@ -14,6 +14,8 @@ import (
 type nsmClient struct {
 	client     networkservice.NetworkServiceClient
 	connection *grpc.ClientConn
+	// release - returns pooled connection instead of closing it.
+	release func() error
 }
 
 func (c *nsmClient) Request(ctx context.Context, request *networkservice.NetworkServiceRequest) (*connection.Connection, error) {
@@ -21,7 +23,7 @@ func (c *nsmClient) Request(ctx context.Context, request *networkservice.Network
 		return nil, errors.New("Remote NSM Connection is not initialized...")
 	}
 
-	response, err := c.client.Request(ctx, request)
+	response, err := c.client.Request(withCorrelationID(ctx, request.GetConnection()), request)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +35,7 @@ func (c *nsmClient) Close(ctx context.Context, conn *connection.Connection) erro
 	if c == nil || c.client == nil {
 		return errors.New("Remote NSM Connection is not initialized...")
 	}
-	_, err := c.client.Close(ctx, conn)
+	_, err := c.client.Close(withCorrelationID(ctx, conn), conn)
 	_ = c.Cleanup()
 	return err
 }
@@ -43,7 +45,9 @@ func (c *nsmClient) Cleanup() error {
 		return errors.Errorf("Remote NSM Connection is already cleaned...")
 	}
 	var err error
-	if c.connection != nil { // Required for testing
+	if c.release != nil {
+		err = c.release()
+	} else if c.connection != nil { // Required for testing
 		err = c.connection.Close()
 	}
 	c.connection = nil
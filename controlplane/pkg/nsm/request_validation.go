@@ -0,0 +1,38 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+// validateRequest - rejects requests endpoint could not be selected for anyway, before any registry round-trip:
+// request which names neither network service nor target endpoint, and request targeted to local NSM which
+// does not name endpoint.
+func validateRequest(requestConnection *connection.Connection, myNsemName string) error {
+	targetEndpoint := requestConnection.GetNetworkServiceEndpointName()
+	if requestConnection.GetNetworkService() == "" && targetEndpoint == "" {
+		return errors.Wrap(ErrInvalidRequest, "neither network service nor target endpoint is set")
+	}
+	targetNsemName := requestConnection.GetDestinationNetworkServiceManagerName()
+	if targetNsemName != "" && targetNsemName == myNsemName && targetEndpoint == "" {
+		return errors.Wrapf(ErrInvalidRequest, "request is targeted to local NSMgr %s, but target endpoint is not set", myNsemName)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+func TestGetEndpoint_InvalidRequest(t *testing.T) {
+	for name, requestConnection := range map[string]*connection.Connection{
+		"no network service":       {},
+		"local target without nse": newTargetedRequestConnection("", localNSMName),
+	} {
+		t.Run(name, func(t *testing.T) {
+			g := NewWithT(t)
+			data := newNseManagerTestData()
+			data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+			_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+			g.Expect(errors.Is(err, ErrInvalidRequest)).To(BeTrue())
+			g.Expect(data.serviceRegistry.discoveryClient.calls).To(BeZero())
+		})
+	}
+}
+
+func TestGetEndpoint_TargetWithoutNetworkService(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	requestConnection := newTargetedRequestConnection(nse1Name, remoteNSMName)
+	requestConnection.NetworkService = ""
+	_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(errors.Is(err, ErrInvalidRequest)).To(BeFalse())
+}
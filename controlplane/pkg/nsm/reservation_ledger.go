@@ -0,0 +1,103 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// reservationLedger - slots reserved on selected endpoints until NSE client to them is created, keyed by endpoint
+// identity. Reservations not settled in time expire. Zero value is ready to use.
+type reservationLedger struct {
+	// selection - serializes selections with reserving, so concurrent selections see each other's reservations.
+	selection sync.Mutex
+
+	sync.Mutex
+	expirations map[string][]time.Time
+}
+
+func (l *reservationLedger) reserve(key string, ttl time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+	if l.expirations == nil {
+		l.expirations = map[string][]time.Time{}
+	}
+	l.expirations[key] = append(l.expirations[key], time.Now().Add(ttl))
+}
+
+// settle - removes the oldest reservation of endpoint, when client to it is created and connection is counted by
+// model, or when client could not be created.
+func (l *reservationLedger) settle(key string) {
+	l.Lock()
+	defer l.Unlock()
+	l.prune(key)
+	if expirations := l.expirations[key]; len(expirations) > 0 {
+		l.expirations[key] = expirations[1:]
+	}
+}
+
+// count - returns count of not expired reservations of endpoint.
+func (l *reservationLedger) count(key string) int {
+	l.Lock()
+	defer l.Unlock()
+	l.prune(key)
+	return len(l.expirations[key])
+}
+
+func (l *reservationLedger) prune(key string) {
+	expirations := l.expirations[key]
+	now := time.Now()
+	i := 0
+	for i < len(expirations) && !expirations[i].After(now) {
+		i++
+	}
+	if i == len(expirations) {
+		delete(l.expirations, key)
+		return
+	}
+	l.expirations[key] = expirations[i:]
+}
+
+// selectAndReserve - selects endpoint and reserves a slot on it, see properties.ReservationTTL.
+func (nsem *nseManager) selectAndReserve(span spanhelper.SpanHelper, requestConnection *connection.Connection, endpointResponse *registry.FindNetworkServiceResponse,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration, selectFn selectFunc) (*registry.NetworkServiceEndpoint, []*registry.NetworkServiceEndpoint, error) {
+	if nsem.props.ReservationTTL <= 0 {
+		return nsem.selectEndpoint(span, requestConnection, endpointResponse, ignoreEndpoints, selectFn)
+	}
+	nsem.reservations.selection.Lock()
+	defer nsem.reservations.selection.Unlock()
+	endpoint, candidates, err := nsem.selectEndpoint(span, requestConnection, endpointResponse, ignoreEndpoints, selectFn)
+	if err != nil {
+		return nil, nil, err
+	}
+	manager := endpointResponse.GetNetworkServiceManagers()[endpoint.GetNetworkServiceManagerName()]
+	nsem.reservations.reserve(nsem.identity.Key(endpoint, manager), nsem.props.ReservationTTL)
+	return endpoint, candidates, nil
+}
+
+// settleReservation - settles reservation of endpoint NSE client was created to or failed to be created to.
+func (nsem *nseManager) settleReservation(endpoint *registry.NSERegistration) {
+	if nsem.props.ReservationTTL <= 0 {
+		return
+	}
+	nsem.reservations.settle(nsem.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager()))
+}
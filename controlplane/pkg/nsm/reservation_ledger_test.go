@@ -0,0 +1,73 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// withReservations - reserves selected endpoints for ttl, selector prefers endpoints with less connections.
+func withReservations(ttl time.Duration) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.props.ReservationTTL = ttl
+		withSelector(selector.NewLexicographicSelector(selector.LeastConnectionsCriterion()))(data)
+	}
+}
+
+func TestReservationLedger_ConcurrentSelectionsSpread(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withReservations(time.Hour), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	mutex := sync.Mutex{}
+	selected := map[string]int{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+			g.Expect(err).To(BeNil())
+			mutex.Lock()
+			selected[endpoint.GetNetworkServiceEndpoint().GetName()]++
+			mutex.Unlock()
+		}()
+	}
+	wg.Wait()
+	g.Expect(selected).To(Equal(map[string]int{nse1Name: 10, nse2Name: 10, nse3Name: 10}))
+}
+
+func TestReservationLedger_UnconfirmedReservationsExpire(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withReservations(50*time.Millisecond), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	for _, expected := range []string{nse1Name, nse2Name, nse3Name} {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(expected))
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestReservationLedger_SettledByClientCreation(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withReservations(time.Hour), withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	_, err = data.nseManager.CreateNSEClient(context.Background(), endpoint)
+	g.Expect(err).To(BeNil())
+
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
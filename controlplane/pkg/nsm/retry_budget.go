@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type retryBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// retryBudget - token buckets of retries per network service, zero value is ready to use.
+type retryBudget struct {
+	sync.Mutex
+	buckets map[string]*retryBucket
+}
+
+// take - takes a retry token of service if there is one, bucket is refilled by one token every refill.
+func (b *retryBudget) take(service string, capacity int, refill time.Duration) bool {
+	b.Lock()
+	defer b.Unlock()
+	now := time.Now()
+	if b.buckets == nil {
+		b.buckets = map[string]*retryBucket{}
+	}
+	bucket, ok := b.buckets[service]
+	if !ok {
+		bucket = &retryBucket{tokens: float64(capacity), last: now}
+		b.buckets[service] = bucket
+	}
+	if refill > 0 {
+		bucket.tokens = math.Min(float64(capacity), bucket.tokens+float64(now.Sub(bucket.last))/float64(refill))
+	}
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return false
+	}
+	bucket.tokens--
+	return true
+}
+
+// allowRetry - takes retry token of network service for requests with ignored endpoints, returns ErrRetryNotAllowed
+// if retry budget is exhausted.
+func (nsem *nseManager) allowRetry(requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) error {
+	if nsem.props.RetryBudget <= 0 || len(ignoreEndpoints) == 0 {
+		return nil
+	}
+	if !nsem.retries.take(requestConnection.GetNetworkService(), nsem.props.RetryBudget, nsem.props.RetryBudgetRefill) {
+		return errors.Wrapf(ErrRetryNotAllowed, "retry budget of %s is exhausted", requestConnection.GetNetworkService())
+	}
+	return nil
+}
+
+// checkIgnoresLimit - returns ErrIgnoresExhausted if request ignores more endpoints than MaxIgnoredEndpoints.
+func (nsem *nseManager) checkIgnoresLimit(requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) error {
+	if limit := nsem.props.MaxIgnoredEndpoints; limit > 0 && len(ignoreEndpoints) > limit {
+		return errors.Wrapf(ErrIgnoresExhausted, "%d endpoints of %s are ignored, limit is %d",
+			len(ignoreEndpoints), requestConnection.GetNetworkService(), limit)
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func withRetryBudget(refill time.Duration) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.nseManager.props.RetryBudget = 2
+		data.nseManager.props.RetryBudgetRefill = refill
+	}
+}
+
+// retry - selects endpoint again, ignoring the first discovered one.
+func (data *nseManagerTestData) retry() error {
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(data.endpoints[0]))
+	return err
+}
+
+func TestRetryBudget_Exhausted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withRetryBudget(time.Hour), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	g.Expect(data.retry()).To(BeNil())
+	g.Expect(data.retry()).To(BeNil())
+	g.Expect(errors.Is(data.retry(), ErrRetryNotAllowed)).To(BeTrue())
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+}
+
+func TestRetryBudget_Refilled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withRetryBudget(50*time.Millisecond), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	g.Expect(data.retry()).To(BeNil())
+	g.Expect(data.retry()).To(BeNil())
+	g.Expect(errors.Is(data.retry(), ErrRetryNotAllowed)).To(BeTrue())
+
+	<-time.After(100 * time.Millisecond)
+	g.Expect(data.retry()).To(BeNil())
+}
+
+func TestRetryBudget_IgnoresLimitExceeded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.MaxIgnoredEndpoints = 2
+	nse1, nse2, nse3 := data.createEndpoint(nse1Name, localNSMName), data.createEndpoint(nse2Name, localNSMName),
+		data.createEndpoint(nse3Name, localNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2, nse3)
+
+	ignores := data.ignores(nse1, nse2)
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+
+	ignores = data.ignores(nse1, nse2, nse3)
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), ignores)
+	g.Expect(errors.Is(err, ErrIgnoresExhausted)).To(BeTrue())
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeFalse())
+}
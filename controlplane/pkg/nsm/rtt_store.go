@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// rttStore - keeps the last measured round trip time for endpoints, RTT of local endpoint is dropped when it is
+// deleted from model.
+type rttStore struct {
+	model.ListenerImpl
+	sync.RWMutex
+	rtt map[registry.EndpointNSMName]time.Duration
+}
+
+func newRTTStore(m model.Model) *rttStore {
+	store := &rttStore{
+		rtt: map[registry.EndpointNSMName]time.Duration{},
+	}
+	m.AddListener(store)
+	return store
+}
+
+func (s *rttStore) store(endpoint registry.EndpointNSMName, rtt time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+	s.rtt[endpoint] = rtt
+}
+
+func (s *rttStore) load(endpoint registry.EndpointNSMName) (time.Duration, bool) {
+	s.RLock()
+	defer s.RUnlock()
+	rtt, ok := s.rtt[endpoint]
+	return rtt, ok
+}
+
+// EndpointDeleted - drops RTT of deleted local endpoint.
+func (s *rttStore) EndpointDeleted(_ context.Context, endpoint *model.Endpoint) {
+	s.Lock()
+	defer s.Unlock()
+	delete(s.rtt, endpoint.Endpoint.GetEndpointNSMName())
+}
+
+// ReportRTT - records the last measured round trip time to endpoint, if properties.LatencyClasses are configured.
+func (nsem *nseManager) ReportRTT(endpoint registry.EndpointNSMName, rtt time.Duration) {
+	if len(nsem.props.LatencyClasses) == 0 {
+		return
+	}
+	nsem.rttStore.store(endpoint, rtt)
+}
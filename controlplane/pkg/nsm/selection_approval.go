@@ -0,0 +1,84 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// ApprovalGate - external authorizer approving endpoint chosen for request, with full request context.
+// Returns whether chosen endpoint is approved and reason of denial.
+type ApprovalGate interface {
+	Approve(ctx context.Context, requestConnection *connection.Connection, chosen *registry.NSERegistration) (bool, string, error)
+}
+
+// WithApprovalGate - require approval of endpoint chosen by GetEndpoint, approval is bounded by
+// properties.ApprovalTimeout. Denied endpoints are not selected for the same client for
+// properties.ApprovalDenialCacheTTL.
+func WithApprovalGate(gate ApprovalGate) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.approvalGate = gate
+	}
+}
+
+// approve - asks approval gate to approve chosen endpoint, returns PermissionDenied error with reason of denial.
+func (nsem *nseManager) approve(ctx context.Context, requestConnection *connection.Connection, chosen *registry.NSERegistration) error {
+	if nsem.approvalGate == nil {
+		return nil
+	}
+	key := nsem.identity.Key(chosen.GetNetworkServiceEndpoint(), chosen.GetNetworkServiceManager())
+	if reason, ok := nsem.deniedApproval(requestConnection, key); ok {
+		return status.Errorf(codes.PermissionDenied, "endpoint %s is not approved (cached): %s", chosen.GetEndpointNSMName(), reason)
+	}
+	if nsem.props.ApprovalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, nsem.props.ApprovalTimeout)
+		defer cancel()
+	}
+
+	type approval struct {
+		approved bool
+		reason   string
+		err      error
+	}
+	result := make(chan approval, 1)
+	go func() {
+		approved, reason, err := nsem.approvalGate.Approve(ctx, requestConnection, chosen)
+		result <- approval{approved: approved, reason: reason, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return errors.Wrapf(ctx.Err(), "approval of endpoint %s", chosen.GetEndpointNSMName())
+	case r := <-result:
+		if r.err != nil {
+			return errors.Wrapf(r.err, "approval of endpoint %s", chosen.GetEndpointNSMName())
+		}
+		if !r.approved {
+			nsem.cacheDenial(requestConnection, key, r.reason)
+			return status.Errorf(codes.PermissionDenied, "endpoint %s is not approved: %s", chosen.GetEndpointNSMName(), r.reason)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,71 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type approvalGateStub struct {
+	denied map[string]string
+	block  bool
+	chosen []string
+}
+
+func (stub *approvalGateStub) Approve(ctx context.Context, requestConnection *connection.Connection, chosen *registry.NSERegistration) (bool, string, error) {
+	if stub.block {
+		<-ctx.Done()
+		return false, "", ctx.Err()
+	}
+	name := chosen.GetNetworkServiceEndpoint().GetName()
+	stub.chosen = append(stub.chosen, name)
+	if reason, ok := stub.denied[name]; ok {
+		return false, reason, nil
+	}
+	return true, "", nil
+}
+
+func TestSelectionApproval_Approved(t *testing.T) {
+	g := NewWithT(t)
+	gate := &approvalGateStub{}
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(gate)(data.nseManager)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(gate.chosen).To(Equal([]string{endpoint.GetNetworkServiceEndpoint().GetName()}))
+}
+
+func TestSelectionApproval_DeniedWithReason(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(&approvalGateStub{denied: map[string]string{nse1Name: "tenant is not allowed"}})(data.nseManager)
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(status.Code(err)).To(Equal(codes.PermissionDenied))
+	g.Expect(err.Error()).To(ContainSubstring("tenant is not allowed"))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+}
+
+func TestSelectionApproval_Timeout(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	WithApprovalGate(&approvalGateStub{block: true})(data.nseManager)
+	data.nseManager.props.ApprovalTimeout = 50 * time.Millisecond
+
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+type budgetPhase string
+
+const (
+	discoveryPhase  budgetPhase = "discovery"
+	validationPhase budgetPhase = "validation"
+	selectionPhase  budgetPhase = "selection"
+	dialPhase       budgetPhase = "dial"
+)
+
+// BudgetExceededError - error returned when a phase of endpoint selection or connecting to endpoint has run out of
+// its share of request deadline, while the request deadline itself is not exceeded yet.
+type BudgetExceededError struct {
+	// Phase - phase which has run out of time: discovery, validation, selection or dial.
+	Phase  string
+	Budget time.Duration
+	err    error
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s phase exceeded its budget of %v: %v", e.Phase, e.Budget, e.err)
+}
+
+// Unwrap - returns error of the phase, usually context.DeadlineExceeded.
+func (e *BudgetExceededError) Unwrap() error {
+	return e.err
+}
+
+// selectionBudget - splits time left until request deadline between phases proportionally, so a slow phase
+// could not starve the others.
+// Phases with explicit timeout get it instead of their share, still bounded by request deadline.
+type selectionBudget struct {
+	total    time.Duration
+	shares   map[budgetPhase]float64
+	timeouts map[budgetPhase]time.Duration
+}
+
+func (nsem *nseManager) newSelectionBudget(ctx context.Context, networkService string) *selectionBudget {
+	budget := &selectionBudget{
+		shares: map[budgetPhase]float64{
+			discoveryPhase:  nsem.props.DiscoveryBudgetShare,
+			validationPhase: nsem.props.ValidationBudgetShare,
+			selectionPhase:  nsem.props.SelectionBudgetShare,
+			dialPhase:       nsem.props.DialBudgetShare,
+		},
+		timeouts: map[budgetPhase]time.Duration{
+			discoveryPhase: nsem.props.DiscoveryTimeouts[networkService],
+		},
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		budget.total = time.Until(deadline)
+	}
+	return budget
+}
+
+// phaseBudget - returns time given to phase, 0 if phase has no explicit timeout and request has no deadline
+// or phase share is not configured.
+func (b *selectionBudget) phaseBudget(phase budgetPhase) time.Duration {
+	if timeout := b.timeouts[phase]; timeout > 0 {
+		return timeout
+	}
+	return time.Duration(float64(b.total) * b.shares[phase])
+}
+
+// run - runs phase with its own share of request deadline, error tells which phase has run out of time.
+func (b *selectionBudget) run(ctx context.Context, phase budgetPhase, phaseFunc func(ctx context.Context) error) error {
+	phaseBudget := b.phaseBudget(phase)
+	if phaseBudget <= 0 {
+		return phaseFunc(ctx)
+	}
+	phaseCtx, cancel := context.WithTimeout(ctx, phaseBudget)
+	defer cancel()
+
+	err := phaseFunc(phaseCtx)
+	// Phase which succeeded just as its budget ran out keeps its result.
+	if err != nil && phaseCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		return &BudgetExceededError{Phase: string(phase), Budget: phaseBudget, err: err}
+	}
+	return err
+}
+
+// reserve - returns ctx with deadline leaving budget of phase for after it, ctx itself if phase has no budget.
+func (b *selectionBudget) reserve(ctx context.Context, phase budgetPhase) (context.Context, context.CancelFunc) {
+	phaseBudget := b.phaseBudget(phase)
+	if phaseBudget <= 0 || b.total <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, b.total-phaseBudget)
+}
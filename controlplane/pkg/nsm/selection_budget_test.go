@@ -0,0 +1,179 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/api/nsm"
+)
+
+type blockingNegotiator struct {
+	deadline time.Time
+}
+
+func (n *blockingNegotiator) Capabilities(ctx context.Context, endpoint *registry.NSERegistration, client nsm.NetworkServiceClient) ([]string, error) {
+	n.deadline, _ = ctx.Deadline()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (data *nseManagerTestData) setBudgetShares(discovery, validation, selection float64) {
+	data.nseManager.props.DiscoveryBudgetShare = discovery
+	data.nseManager.props.ValidationBudgetShare = validation
+	data.nseManager.props.SelectionBudgetShare = selection
+}
+
+func TestSelectionBudget_DiscoveryPhaseIsCut(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setBudgetShares(0.6, 0.2, 0.2)
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("discovery phase exceeded its budget"))
+	var exceeded *BudgetExceededError
+	g.Expect(errors.As(err, &exceeded)).To(BeTrue())
+	g.Expect(exceeded.Phase).To(Equal("discovery"))
+	g.Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	g.Expect(ctx.Err()).To(BeNil())
+	g.Expect(discovery.received()[0].deadline.Sub(start)).To(BeNumerically("~", 600*time.Millisecond, 50*time.Millisecond))
+}
+
+func TestSelectionBudget_ValidationPhaseIsCut(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setBudgetShares(0.6, 0.2, 0.2)
+	negotiator := &blockingNegotiator{}
+	WithCapabilityNegotiator(negotiator)(data.nseManager)
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, _, err := data.nseManager.CreateNegotiatedNSEClient(ctx, newTestRequestConnection(withLabel(RequiredCapabilitiesLabel, "ipv6")), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(negotiator.deadline.Sub(start)).To(BeNumerically("~", 200*time.Millisecond, 50*time.Millisecond))
+}
+
+func TestSelectionBudget_UnlimitedByDefault(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	var exceeded *BudgetExceededError
+	g.Expect(errors.As(err, &exceeded)).To(BeFalse())
+	g.Expect(discovery.received()[0].deadline).To(Equal(deadline))
+}
+
+func TestSelectionBudget_PhaseSucceededAtDeadlineKeepsResult(t *testing.T) {
+	g := NewWithT(t)
+	budget := &selectionBudget{total: time.Second, shares: map[budgetPhase]float64{discoveryPhase: 0.01}}
+
+	err := budget.run(context.Background(), discoveryPhase, func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	g.Expect(err).To(BeNil())
+}
+
+func TestSelectionBudget_NoDeadline(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(discovery.received()[0].hasDeadline).To(BeFalse())
+}
+
+func TestSelectionBudget_PerServiceDiscoveryTimeout(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+	data.nseManager.props.DiscoveryTimeouts = map[string]time.Duration{
+		networkServiceName: 100 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("discovery phase exceeded its budget of 100ms"))
+	g.Expect(discovery.received()[0].deadline.Sub(start)).To(BeNumerically("~", 100*time.Millisecond, 50*time.Millisecond))
+}
+
+func TestSelectionBudget_PerServiceDiscoveryTimeoutFallsBack(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+	data.nseManager.props.DiscoveryTimeouts = map[string]time.Duration{
+		"other-service": 100 * time.Millisecond,
+	}
+	data.setBudgetShares(0.6, 0.2, 0.2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(discovery.received()[0].deadline.Sub(start)).To(BeNumerically("~", 600*time.Millisecond, 50*time.Millisecond))
+}
+
+func TestSelectionBudget_DialReservedFromSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	discovery := data.withScriptedDiscovery()
+	discovery.block = untilDone
+	data.nseManager.props.DialBudgetShare = 0.5
+	data.setBudgetShares(0.6, 0.2, 0.2)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, _, err := data.nseManager.ConnectToAnyEndpoint(ctx, newTestRequestConnection(), nil)
+	var exceeded *BudgetExceededError
+	g.Expect(errors.As(err, &exceeded)).To(BeTrue())
+	g.Expect(exceeded.Phase).To(Equal("discovery"))
+	// Discovery share of time left after dial reservation.
+	g.Expect(discovery.received()[0].deadline.Sub(start)).To(BeNumerically("~", 300*time.Millisecond, 50*time.Millisecond))
+}
+
+func TestSelectionBudget_DialPhaseIsCut(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withHangingDials)
+	defer close(data.serviceRegistry.hang)
+	data.nseManager.props.DialBudgetShare = 0.2
+	data.nseManager.props.ConnectAttempts = 1
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, _, err := data.nseManager.ConnectToAnyEndpoint(ctx, newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(ContainSubstring("dial phase exceeded its budget"))
+	g.Expect(time.Since(start)).To(BeNumerically("~", 200*time.Millisecond, 100*time.Millisecond))
+	g.Expect(ctx.Err()).To(BeNil())
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// MaxCandidatesLabel - request label overriding properties.MaxSelectionCandidates for the request.
+const MaxCandidatesLabel = "nsm/max-candidates"
+
+// maxCandidatesLimit - upper bound of candidate cap requests could ask for.
+const maxCandidatesLimit = 1024
+
+// maxCandidates - returns candidate cap of request, 0 if candidates are not capped.
+func (nsem *nseManager) maxCandidates(requestConnection *connection.Connection) int {
+	value, ok := requestConnection.GetLabels()[MaxCandidatesLabel]
+	if !ok {
+		return nsem.props.MaxSelectionCandidates
+	}
+	requested, err := strconv.Atoi(value)
+	if err != nil {
+		logrus.Warnf("Malformed %s label %q, using default of %d", MaxCandidatesLabel, value, nsem.props.MaxSelectionCandidates)
+		return nsem.props.MaxSelectionCandidates
+	}
+	if requested < 1 {
+		return 1
+	}
+	if requested > maxCandidatesLimit {
+		return maxCandidatesLimit
+	}
+	return requested
+}
+
+// capCandidates - keeps first candidates up to cap of request.
+func (nsem *nseManager) capCandidates(requestConnection *connection.Connection, endpoints []*registry.NetworkServiceEndpoint) []*registry.NetworkServiceEndpoint {
+	if max := nsem.maxCandidates(requestConnection); max > 0 && len(endpoints) > max {
+		return endpoints[:max]
+	}
+	return endpoints
+}
@@ -0,0 +1,46 @@
+package nsm
+
+import (
+	"strconv"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+func TestSelectionCandidates_Cap(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+	response := data.serviceRegistry.discoveryClient.response
+
+	for _, testCase := range []struct {
+		global     int
+		request    *connection.Connection
+		candidates int
+	}{
+		{global: 0, request: newTestRequestConnection(), candidates: 3},
+		{global: 2, request: newTestRequestConnection(), candidates: 2},
+		{global: 2, request: newTestRequestConnection(withLabel(MaxCandidatesLabel, "1")), candidates: 1},
+		{global: 1, request: newTestRequestConnection(withLabel(MaxCandidatesLabel, "3")), candidates: 3},
+		{global: 2, request: newTestRequestConnection(withLabel(MaxCandidatesLabel, "0")), candidates: 1},
+		{global: 2, request: newTestRequestConnection(withLabel(MaxCandidatesLabel, "-5")), candidates: 1},
+		{global: 2, request: newTestRequestConnection(withLabel(MaxCandidatesLabel, "many")), candidates: 2},
+	} {
+		data.nseManager.props.MaxSelectionCandidates = testCase.global
+		_, candidates, err := data.nseManager.selectEndpoint(nil, testCase.request, response, nil, data.nseManager.selectAndRecord)
+		g.Expect(err).To(BeNil())
+		g.Expect(candidates).To(HaveLen(testCase.candidates))
+	}
+}
+
+func TestSelectionCandidates_ClampedToLimit(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	g.Expect(data.nseManager.maxCandidates(newTestRequestConnection(withLabel(MaxCandidatesLabel, strconv.Itoa(maxCandidatesLimit*10))))).To(Equal(maxCandidatesLimit))
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// selectionSlots - semaphores bounding concurrent GetEndpoint calls by network service, zero value is ready to use.
+// Semaphore capacity is the limit in effect when network service is first requested.
+type selectionSlots struct {
+	sync.Mutex
+	slots map[string]chan struct{}
+}
+
+func (s *selectionSlots) get(networkService string, limit int) chan struct{} {
+	s.Lock()
+	defer s.Unlock()
+	if s.slots == nil {
+		s.slots = map[string]chan struct{}{}
+	}
+	slots, ok := s.slots[networkService]
+	if !ok {
+		slots = make(chan struct{}, limit)
+		s.slots[networkService] = slots
+	}
+	return slots
+}
+
+// acquireSelectionSlot - waits until there are less than properties.MaxConcurrentSelections GetEndpoint calls in
+// flight for network service, or until ctx is done. Returned release must be called once selection is done.
+func (nsem *nseManager) acquireSelectionSlot(ctx context.Context, span spanhelper.SpanHelper, networkService string) (func(), error) {
+	limit := nsem.props.MaxConcurrentSelections
+	if limit <= 0 {
+		return func() {}, nil
+	}
+	slots := nsem.selectionSlots.get(networkService, limit)
+	release := func() { <-slots }
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	default:
+	}
+	span.LogValue("throttled", true)
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ctx.Err(), "waiting for %d concurrent selections of NetworkService %s", limit, networkService)
+	}
+}
@@ -0,0 +1,45 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+func TestSelectionConcurrency_ExcessCallWaits(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.MaxConcurrentSelections = 1
+	started, proceed := make(chan struct{}, 3), make(chan struct{})
+	discovery := data.withScriptedDiscovery(discoveryStep{
+		response: data.createFindNetworkServiceResponse(data.createEndpoint(nse1Name, remoteNSMName)),
+	})
+	discovery.block = heldUntil(started, proceed)
+
+	getEndpoint := func(ctx context.Context) chan error {
+		result := make(chan error, 1)
+		go func() {
+			_, err := data.nseManager.GetEndpoint(ctx, newTestRequestConnection(), nil)
+			result <- err
+		}()
+		return result
+	}
+	first := getEndpoint(context.Background())
+	<-started
+
+	// Excess call gives up waiting once its context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	g.Expect(errors.Is(<-getEndpoint(ctx), context.DeadlineExceeded)).To(BeTrue())
+
+	// Excess call proceeds once the first one completes.
+	second := getEndpoint(context.Background())
+	g.Consistently(second, 50*time.Millisecond).ShouldNot(Receive())
+	g.Expect(started).To(BeEmpty())
+	close(proceed)
+	g.Expect(<-first).To(BeNil())
+	g.Eventually(second).Should(Receive(BeNil()))
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// selectionConfidence - tells how clear-cut the selection was. For scored candidates it is a relative margin of
+// the selected endpoint score over the best of the others, otherwise all candidates are considered tied.
+func selectionConfidence(scores []float64, candidates []*registry.NetworkServiceEndpoint, selected *registry.NetworkServiceEndpoint) float64 {
+	if len(candidates) <= 1 {
+		return 1
+	}
+	if len(scores) != len(candidates) {
+		return 1 / float64(len(candidates))
+	}
+
+	selectedScore, bestOther, found := 0.0, 0.0, false
+	for i, candidate := range candidates {
+		if candidate == selected && !found {
+			selectedScore, found = scores[i], true
+			continue
+		}
+		if scores[i] > bestOther {
+			bestOther = scores[i]
+		}
+	}
+	if !found || selectedScore <= 0 || selectedScore <= bestOther {
+		return 0
+	}
+	return (selectedScore - bestOther) / selectedScore
+}
@@ -0,0 +1,52 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func selectWithConfidence(endpointSelector *selectorStub, endpoints []*registry.NetworkServiceEndpoint) float64 {
+	selected := endpointSelector.SelectEndpoint(nil, nil, endpoints)
+	return selectionConfidence(endpointSelector.ScoreEndpoints(nil, nil, endpoints), endpoints, selected)
+}
+
+func TestSelectionConfidence_DominantWinner(t *testing.T) {
+	g := NewWithT(t)
+	endpoints := []*registry.NetworkServiceEndpoint{{Name: nse1Name}, {Name: nse2Name}, {Name: nse3Name}}
+
+	confidence := selectWithConfidence(&selectorStub{
+		scores: map[string]float64{nse1Name: 10, nse2Name: 1, nse3Name: 0.5},
+	}, endpoints)
+	g.Expect(confidence).To(BeNumerically("~", 0.9, 0.001))
+}
+
+func TestSelectionConfidence_NearTie(t *testing.T) {
+	g := NewWithT(t)
+	endpoints := []*registry.NetworkServiceEndpoint{{Name: nse1Name}, {Name: nse2Name}}
+
+	confidence := selectWithConfidence(&selectorStub{
+		scores: map[string]float64{nse1Name: 10, nse2Name: 9.9},
+	}, endpoints)
+	g.Expect(confidence).To(BeNumerically("<", 0.05))
+}
+
+func TestSelectionConfidence_ReportedByGetEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Confidence).To(Equal(0.5))
+
+	_, err = data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTargetedRequestConnection(nse1Name, remoteNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Confidence).To(Equal(1.0))
+}
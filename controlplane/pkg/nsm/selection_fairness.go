@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// fairnessWindow - identities of endpoints of network service which were candidates and which were selected within
+// evaluation interval.
+type fairnessWindow struct {
+	start    time.Time
+	seen     map[string]bool
+	selected map[string]bool
+}
+
+// selectionFairness - detects endpoints of network services which were candidates for a whole interval but were
+// never selected, zero value is ready to use.
+type selectionFairness struct {
+	sync.Mutex
+	random  *rand.Rand
+	windows map[string]*fairnessWindow
+	// starved - identities of starved endpoints by network service, endpoint is no longer starved once selected.
+	starved map[string]map[string]bool
+}
+
+// window - returns current window of network service, starting a new one and marking endpoints starved in the
+// finished one if interval elapsed.
+func (f *selectionFairness) window(service string, interval time.Duration) *fairnessWindow {
+	if f.windows == nil {
+		f.windows = map[string]*fairnessWindow{}
+		f.starved = map[string]map[string]bool{}
+	}
+	w := f.windows[service]
+	if w != nil && time.Since(w.start) < interval {
+		return w
+	}
+	if w != nil {
+		starved := map[string]bool{}
+		for key := range w.seen {
+			if !w.selected[key] {
+				starved[key] = true
+			}
+		}
+		f.starved[service] = starved
+	}
+	w = &fairnessWindow{start: time.Now(), seen: map[string]bool{}, selected: map[string]bool{}}
+	f.windows[service] = w
+	return w
+}
+
+// starvedCandidate - returns index of a random starved candidate with probability of share, -1 if there is none
+// or the guard is not applied.
+func (f *selectionFairness) starvedCandidate(service string, keys []string, share float64, interval time.Duration) int {
+	f.Lock()
+	defer f.Unlock()
+	f.window(service, interval)
+	var starved []int
+	for i, key := range keys {
+		if f.starved[service][key] {
+			starved = append(starved, i)
+		}
+	}
+	if len(starved) == 0 {
+		return -1
+	}
+	if f.random == nil {
+		f.random = rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404 - fairness does not need secure random
+	}
+	if f.random.Float64() >= share {
+		return -1
+	}
+	return starved[f.random.Intn(len(starved))]
+}
+
+// record - records candidates of network service and the selected one.
+func (f *selectionFairness) record(service string, keys []string, selected string, interval time.Duration) {
+	f.Lock()
+	defer f.Unlock()
+	w := f.window(service, interval)
+	for _, key := range keys {
+		w.seen[key] = true
+	}
+	w.selected[selected] = true
+	delete(f.starved[service], selected)
+}
+
+// fairSelect - selectFn routing properties.FairnessShare of new connections to endpoints starved within
+// properties.FairnessInterval. Connections bound to endpoint by session affinity are selected by selectFn.
+func (nsem *nseManager) fairSelect(selectFn selectFunc) selectFunc {
+	return func(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint,
+		managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+		share, interval := nsem.props.FairnessShare, nsem.props.FairnessInterval
+		if share <= 0 || interval <= 0 {
+			return selectFn(requestConnection, ns, endpoints, managers)
+		}
+		keys := make([]string, 0, len(endpoints))
+		for _, endpoint := range endpoints {
+			keys = append(keys, nsem.identity.Key(endpoint, managers[endpoint.GetNetworkServiceManagerName()]))
+		}
+		var endpoint *registry.NetworkServiceEndpoint
+		if !nsem.hasAffinity(requestConnection) {
+			if i := nsem.fairness.starvedCandidate(ns.GetName(), keys, share, interval); i >= 0 {
+				endpoint = endpoints[i]
+			}
+		}
+		if endpoint == nil {
+			endpoint = selectFn(requestConnection, ns, endpoints, managers)
+		}
+		if endpoint != nil {
+			nsem.fairness.record(ns.GetName(), keys, nsem.identity.Key(endpoint, managers[endpoint.GetNetworkServiceManagerName()]), interval)
+		}
+		return endpoint
+	}
+}
+
+// hasAffinity - tells if connection is bound to endpoint with properties.SessionAffinity.
+func (nsem *nseManager) hasAffinity(requestConnection *connection.Connection) bool {
+	if !nsem.props.SessionAffinity || requestConnection.GetId() == "" {
+		return false
+	}
+	_, ok := nsem.affinity.get(nsem.connectionKey(requestConnection))
+	return ok
+}
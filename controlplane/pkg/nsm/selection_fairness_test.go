@@ -0,0 +1,94 @@
+package nsm
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+)
+
+const fairnessTestInterval = 30 * time.Millisecond
+
+// withFairness - shares selections of sticky clients, selector prefers nse-1 over nse-2.
+func withFairness(share float64) testDataOption {
+	return func(data *nseManagerTestData) {
+		withSessionAffinity(data)
+		data.nseManager.props.FairnessShare = share
+		data.nseManager.props.FairnessInterval = fairnessTestInterval
+		data.nseManager.fairness.random = rand.New(rand.NewSource(1))
+		withSelector(&selectorStub{
+			scores: map[string]float64{nse1Name: 2, nse2Name: 1},
+		})(data)
+	}
+}
+
+func (data *nseManagerTestData) selectedFor(id string) string {
+	requestConnection := newTestRequestConnection(withID(id))
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	if err != nil {
+		return err.Error()
+	}
+	return endpoint.GetNetworkServiceEndpoint().GetName()
+}
+
+func TestSelectionFairness_StarvedEndpointSelected(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFairness(1), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	g.Expect(data.selectedFor("a")).To(Equal(nse1Name))
+	g.Expect(data.selectedFor("b")).To(Equal(nse1Name))
+	time.Sleep(fairnessTestInterval)
+
+	g.Expect(data.selectedFor("c")).To(Equal(nse2Name))
+	// Endpoint is no longer starved once selected.
+	g.Expect(data.selectedFor("d")).To(Equal(nse1Name))
+	// Existing connections keep their endpoints.
+	g.Expect(data.selectedFor("a")).To(Equal(nse1Name))
+	g.Expect(data.selectedFor("c")).To(Equal(nse2Name))
+}
+
+func TestSelectionFairness_ShareOfNewConnections(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFairness(0.3), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	g.Expect(data.selectedFor("a")).To(Equal(nse1Name))
+	time.Sleep(fairnessTestInterval)
+
+	ids := []string{"b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}
+	selected := []string{}
+	for _, id := range ids {
+		selected = append(selected, data.selectedFor(id))
+	}
+	g.Expect(selected).To(ContainElement(nse2Name))
+	g.Expect(selected).To(ContainElement(nse1Name))
+}
+
+func TestSelectionFairness_BoundConnectionNotRerouted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFairness(1), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	endpoints := data.serviceRegistry.discoveryClient.response.GetNetworkServiceEndpoints()
+	managers := data.serviceRegistry.discoveryClient.response.GetNetworkServiceManagers()
+	ns := data.serviceRegistry.discoveryClient.response.GetNetworkService()
+	selectFn := data.nseManager.fairSelect(data.nseManager.selectAndRecord)
+	bound := &connection.Connection{Id: "a", NetworkService: networkServiceName}
+
+	g.Expect(selectFn(bound, ns, endpoints, managers).GetName()).To(Equal(nse1Name))
+	data.nseManager.stickEndpoint(bound, endpoints[0])
+	time.Sleep(fairnessTestInterval)
+
+	g.Expect(selectFn(bound, ns, endpoints, managers).GetName()).To(Equal(nse1Name))
+	g.Expect(selectFn(&connection.Connection{Id: "b", NetworkService: networkServiceName}, ns, endpoints, managers).GetName()).To(Equal(nse2Name))
+}
+
+func TestSelectionFairness_DisabledWithoutShare(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFairness(0), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	g.Expect(data.selectedFor("a")).To(Equal(nse1Name))
+	time.Sleep(fairnessTestInterval)
+	g.Expect(data.selectedFor("b")).To(Equal(nse1Name))
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+// Reason codes of endpoint selection recorded in selection history and metrics.
+const (
+	SelectionReasonSelected  = "selected"
+	SelectionReasonPinned    = "pinned"
+	SelectionReasonUnpinned  = "unpinned"
+	SelectionReasonToken     = "token"
+	SelectionReasonRehomed   = "rehomed"
+	SelectionReasonLocality  = "locality"
+	SelectionReasonSticky    = "sticky"
+	SelectionReasonPreferred = "preferred"
+	SelectionReasonAssigned  = "assigned"
+)
+
+// SelectionRecord - endpoint a connection was routed to by GetEndpoint.
+type SelectionRecord struct {
+	Endpoint registry.EndpointNSMName
+	Time     time.Time
+	Reason   string
+}
+
+type connectionHistory struct {
+	id      string
+	records []SelectionRecord
+}
+
+// selectionHistory - last selections per namespaced connection id, history of connection is dropped when it is
+// deleted from model or when it was selected for least recently of all once there are too many connections.
+type selectionHistory struct {
+	model.ListenerImpl
+	sync.Mutex
+	namespace   ConnectionNamespace
+	connections map[string]*list.Element
+	// recent - connection histories, the most recently selected for first.
+	recent *list.List
+}
+
+func newSelectionHistory(m model.Model, namespace ConnectionNamespace) *selectionHistory {
+	history := &selectionHistory{
+		namespace:   namespace,
+		connections: map[string]*list.Element{},
+		recent:      list.New(),
+	}
+	m.AddListener(history)
+	return history
+}
+
+// SelectionHistory - returns last endpoint selections of connection in the global namespace, oldest first.
+func (nsem *nseManager) SelectionHistory(connectionID string) []SelectionRecord {
+	return nsem.NamespacedSelectionHistory("", connectionID)
+}
+
+// NamespacedSelectionHistory - returns last endpoint selections of connection in namespace, e.g. tenant, oldest first.
+func (nsem *nseManager) NamespacedSelectionHistory(namespace, connectionID string) []SelectionRecord {
+	nsem.history.Lock()
+	defer nsem.history.Unlock()
+	element, ok := nsem.history.connections[namespacedID(namespace, connectionID)]
+	if !ok {
+		return nil
+	}
+	return append([]SelectionRecord(nil), element.Value.(*connectionHistory).records...)
+}
+
+// record - records selection keeping size last ones of connection, if there are maxConnections histories already,
+// history of connection selected for least recently is dropped to record another one, maxConnections 0 means
+// no limit.
+func (h *selectionHistory) record(connectionID string, endpoint *registry.NSERegistration, reason string, size, maxConnections int) {
+	if connectionID == "" || size <= 0 {
+		return
+	}
+	h.Lock()
+	defer h.Unlock()
+	element, ok := h.connections[connectionID]
+	if ok {
+		h.recent.MoveToFront(element)
+	} else {
+		if maxConnections > 0 && len(h.connections) >= maxConnections {
+			h.remove(h.recent.Back())
+		}
+		element = h.recent.PushFront(&connectionHistory{id: connectionID})
+		h.connections[connectionID] = element
+	}
+	history := element.Value.(*connectionHistory)
+	history.records = append(history.records, SelectionRecord{
+		Endpoint: endpoint.GetEndpointNSMName(),
+		Time:     time.Now(),
+		Reason:   reason,
+	})
+	if len(history.records) > size {
+		history.records = append([]SelectionRecord(nil), history.records[len(history.records)-size:]...)
+	}
+}
+
+func (h *selectionHistory) remove(element *list.Element) {
+	h.recent.Remove(element)
+	delete(h.connections, element.Value.(*connectionHistory).id)
+}
+
+// recordSelection - records endpoint selection to connection history, connection routes and selection metrics.
+func (nsem *nseManager) recordSelection(requestConnection *connection.Connection, endpoint *registry.NSERegistration, reason string) {
+	nsem.history.record(nsem.connectionKey(requestConnection), endpoint, reason, nsem.props.SelectionHistorySize,
+		nsem.props.SelectionHistoryMaxConnections)
+	nsem.routes.route(nsem.connectionKey(requestConnection), endpoint)
+	nsem.selectionCounter.WithLabelValues(requestConnection.GetNetworkService(), reason).Inc()
+}
+
+// countFailure - counts failed selection for network service by cause.
+func (nsem *nseManager) countFailure(service, cause string) {
+	nsem.failureCounter.WithLabelValues(service, cause).Inc()
+}
+
+// ClientConnectionDeleted - drops history of closed connection.
+func (h *selectionHistory) ClientConnectionDeleted(ctx context.Context, clientConnection *model.ClientConnection) {
+	h.Lock()
+	defer h.Unlock()
+	if element, ok := h.connections[namespacedID(h.namespace(clientConnection.Request.GetConnection()), clientConnection.GetID())]; ok {
+		h.remove(element)
+	}
+}
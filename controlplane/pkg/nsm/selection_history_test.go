@@ -0,0 +1,106 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+const historyConnectionID = "1"
+
+func historyEndpoints(records []SelectionRecord) []registry.EndpointNSMName {
+	var result []registry.EndpointNSMName
+	for _, record := range records {
+		result = append(result, record.Endpoint)
+	}
+	return result
+}
+
+func TestSelectionHistory_RecordedInOrder(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(16))
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	for i := 0; i < 3; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+		g.Expect(err).To(BeNil())
+	}
+	pinned := newTargetedRequestConnection(nse2Name, remoteNSMName, withID(historyConnectionID))
+	_, err := data.nseManager.GetEndpoint(context.Background(), pinned, nil)
+	g.Expect(err).To(BeNil())
+
+	history := data.nseManager.SelectionHistory(historyConnectionID)
+	g.Expect(historyEndpoints(history)).To(Equal([]registry.EndpointNSMName{
+		nse1.GetEndpointNSMName(), nse2.GetEndpointNSMName(), nse1.GetEndpointNSMName(), nse2.GetEndpointNSMName(),
+	}))
+	g.Expect(history[0].Reason).To(Equal(SelectionReasonSelected))
+	g.Expect(history[3].Reason).To(Equal(SelectionReasonPinned))
+	for i := 1; i < len(history); i++ {
+		g.Expect(history[i].Time).NotTo(BeTemporally("<", history[i-1].Time))
+	}
+	g.Expect(data.nseManager.SelectionHistory("unknown")).To(BeEmpty())
+}
+
+func TestSelectionHistory_Bounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(2))
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	for i := 0; i < 3; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+		g.Expect(err).To(BeNil())
+	}
+	history := data.nseManager.SelectionHistory(historyConnectionID)
+	g.Expect(history).To(HaveLen(2))
+	g.Expect(string(history[0].Endpoint)).To(HavePrefix(nse2Name))
+	g.Expect(string(history[1].Endpoint)).To(HavePrefix(nse3Name))
+}
+
+func TestSelectionHistory_PrunedOnClose(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(16))
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.SelectionHistory(historyConnectionID)).To(HaveLen(1))
+
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: historyConnectionID})
+	data.model.DeleteClientConnection(context.Background(), historyConnectionID)
+	g.Eventually(func() []SelectionRecord {
+		return data.nseManager.SelectionHistory(historyConnectionID)
+	}).Should(BeEmpty())
+}
+
+func TestSelectionHistory_DisabledByDefault(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(historyConnectionID)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.SelectionHistory(historyConnectionID)).To(BeEmpty())
+}
+
+func TestSelectionHistory_LeastRecentConnectionEvicted(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionHistory(16), withEndpoints(remoteNSMName, nse1Name))
+	data.nseManager.props.SelectionHistoryMaxConnections = 2
+
+	for _, id := range []string{"1", "2", "1", "3"} {
+		requestConnection := newTestRequestConnection(withID(id))
+		_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Expect(data.nseManager.SelectionHistory("1")).To(HaveLen(2))
+	g.Expect(data.nseManager.SelectionHistory("2")).To(BeEmpty())
+	g.Expect(data.nseManager.SelectionHistory("3")).To(HaveLen(1))
+}
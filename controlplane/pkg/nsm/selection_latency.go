@@ -0,0 +1,72 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyReservoir - ring buffer of recent selection durations, zero value is ready to use.
+type latencyReservoir struct {
+	sync.Mutex
+	durations []time.Duration
+	next      int
+}
+
+// record - records duration of selection started at start, keeping up to size most recent ones.
+func (r *latencyReservoir) record(start time.Time, size int) {
+	r.add(time.Since(start), size)
+}
+
+func (r *latencyReservoir) add(duration time.Duration, size int) {
+	if size <= 0 {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	if len(r.durations) > size {
+		// Reservoir size was decreased.
+		r.durations, r.next = nil, 0
+	}
+	if len(r.durations) < size {
+		r.durations = append(r.durations, duration)
+		return
+	}
+	r.durations[r.next] = duration
+	r.next = (r.next + 1) % size
+}
+
+// SelectionLatencyPercentiles - returns 50th, 90th and 99th percentiles of recent GetEndpoint durations,
+// zeros if there were none.
+func (nsem *nseManager) SelectionLatencyPercentiles() (p50, p90, p99 time.Duration) {
+	nsem.latencies.Lock()
+	durations := append([]time.Duration(nil), nsem.latencies.durations...)
+	nsem.latencies.Unlock()
+
+	if len(durations) == 0 {
+		return 0, 0, 0
+	}
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	percentile := func(p int) time.Duration {
+		return durations[(len(durations)-1)*p/100]
+	}
+	return percentile(50), percentile(90), percentile(99)
+}
@@ -0,0 +1,72 @@
+package nsm
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSelectionLatency_Percentiles(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	p50, p90, p99 := data.nseManager.SelectionLatencyPercentiles()
+	g.Expect([]time.Duration{p50, p90, p99}).To(Equal([]time.Duration{0, 0, 0}))
+
+	for i := 100; i > 0; i-- {
+		data.nseManager.latencies.add(time.Duration(i)*time.Millisecond, 100)
+	}
+	p50, p90, p99 = data.nseManager.SelectionLatencyPercentiles()
+	g.Expect(p50).To(BeNumerically("~", 50*time.Millisecond, time.Millisecond))
+	g.Expect(p90).To(BeNumerically("~", 90*time.Millisecond, time.Millisecond))
+	g.Expect(p99).To(BeNumerically("~", 99*time.Millisecond, time.Millisecond))
+}
+
+func TestSelectionLatency_ReservoirIsBounded(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	for i := 0; i < 10; i++ {
+		data.nseManager.latencies.add(time.Second, 10)
+	}
+	for i := 0; i < 10; i++ {
+		data.nseManager.latencies.add(time.Millisecond, 10)
+	}
+	_, _, p99 := data.nseManager.SelectionLatencyPercentiles()
+	g.Expect(p99).To(Equal(time.Millisecond))
+	g.Expect(data.nseManager.latencies.durations).To(HaveLen(10))
+}
+
+func TestSelectionLatency_ConcurrentRecording(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				data.nseManager.latencies.add(time.Millisecond, 64)
+				_, _, _ = data.nseManager.SelectionLatencyPercentiles()
+			}
+		}()
+	}
+	wg.Wait()
+	g.Expect(data.nseManager.latencies.durations).To(HaveLen(64))
+}
+
+func TestSelectionLatency_RecordedByGetEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.nseManager.latencies.durations).To(HaveLen(1))
+	_, _, p99 := data.nseManager.SelectionLatencyPercentiles()
+	g.Expect(p99).To(BeNumerically(">", 0))
+}
@@ -0,0 +1,217 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/metrics"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func (data *nseManagerTestData) selectionCount(reason string) float64 {
+	return testutil.ToFloat64(data.nseManager.selectionCounter.WithLabelValues(networkServiceName, reason))
+}
+
+func TestSelectionMetrics_CountedByReason(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.SelectionTokenTTL = time.Minute
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	tokenRequest := newTestRequestConnection(withLabel(SelectionTokenLabel, result.Token))
+	unpinnedRequest := newTargetedRequestConnection(nse3Name, remoteNSMName, withLabel(UnpinOnFailureLabel, "true"))
+
+	for _, testCase := range []struct {
+		request *connection.Connection
+		reason  string
+	}{
+		{request: newTestRequestConnection(), reason: SelectionReasonSelected},
+		{request: newTargetedRequestConnection(nse2Name, remoteNSMName), reason: SelectionReasonPinned},
+		{request: unpinnedRequest, reason: SelectionReasonUnpinned},
+		{request: tokenRequest, reason: SelectionReasonToken},
+	} {
+		before := map[string]float64{}
+		for _, reason := range []string{SelectionReasonSelected, SelectionReasonPinned, SelectionReasonUnpinned, SelectionReasonToken} {
+			before[reason] = data.selectionCount(reason)
+		}
+		_, err := data.nseManager.GetEndpoint(context.Background(), testCase.request, nil)
+		g.Expect(err).To(BeNil())
+		for reason, count := range before {
+			if reason == testCase.reason {
+				g.Expect(data.selectionCount(reason)).To(Equal(count + 1))
+			} else {
+				g.Expect(data.selectionCount(reason)).To(Equal(count))
+			}
+		}
+	}
+}
+
+func (data *nseManagerTestData) failureCount(cause string) float64 {
+	return testutil.ToFloat64(data.nseManager.failureCounter.WithLabelValues(networkServiceName, cause))
+}
+
+func TestSelectionMetrics_FailuresCountedByCause(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1)
+
+	noEndpoints := data.failureCount(metrics.FailureNoEndpoints)
+	targetNotFound := data.failureCount(metrics.FailureTargetNotFound)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1))
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.failureCount(metrics.FailureNoEndpoints)).To(Equal(noEndpoints + 1))
+
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse2Name, remoteNSMName), nil)
+	g.Expect(err).NotTo(BeNil())
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse2Name, localNSMName), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(data.failureCount(metrics.FailureTargetNotFound)).To(Equal(targetNotFound + 2))
+	g.Expect(data.failureCount(metrics.FailureNoEndpoints)).To(Equal(noEndpoints + 1))
+
+	// Previews do not count as failed selections.
+	_, _ = data.nseManager.PreviewSelections(context.Background(), newTestRequestConnection(),
+		[]map[registry.EndpointNSMName]*registry.NSERegistration{data.ignores(nse1)})
+	g.Expect(data.failureCount(metrics.FailureNoEndpoints)).To(Equal(noEndpoints + 1))
+}
+
+func TestSelectionMetrics_SelectorReturnedNil(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelector(&selectorStub{pick: noEndpoint}), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	nse1, nse2 := data.endpoints[0], data.endpoints[1]
+
+	noEndpoints := data.failureCount(metrics.FailureNoEndpoints)
+	selectorNil := data.failureCount(metrics.FailureSelectorNil)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	var nilErr *SelectorReturnedNilError
+	g.Expect(errors.As(err, &nilErr)).To(BeTrue())
+	g.Expect(nilErr.Candidates).To(Equal(2))
+	g.Expect(errors.Is(err, ErrNoEndpointFound)).To(BeTrue())
+	g.Expect(data.failureCount(metrics.FailureSelectorNil)).To(Equal(selectorNil + 1))
+	g.Expect(data.failureCount(metrics.FailureNoEndpoints)).To(Equal(noEndpoints))
+
+	// Nothing to select from is not a selector failure.
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1, nse2))
+	g.Expect(errors.As(err, &nilErr)).To(BeFalse())
+	g.Expect(data.failureCount(metrics.FailureSelectorNil)).To(Equal(selectorNil + 1))
+	g.Expect(data.failureCount(metrics.FailureNoEndpoints)).To(Equal(noEndpoints + 1))
+}
+
+func histogramCount(g *WithT, name string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	g.Expect(err).To(BeNil())
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == metrics.ServiceKey && label.GetValue() == networkServiceName {
+					return metric.GetHistogram().GetSampleCount()
+				}
+			}
+		}
+	}
+	return 0
+}
+
+func TestSelectionMetrics_DiscoveryLatencyObserved(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	before := histogramCount(g, metrics.DiscoveryDurationSeconds)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(histogramCount(g, metrics.DiscoveryDurationSeconds)).To(Equal(before + 1))
+}
+
+func (data *nseManagerTestData) clientCount(locality string) float64 {
+	return testutil.ToFloat64(data.nseManager.clientCounter.WithLabelValues(networkServiceName, locality))
+}
+
+func TestSelectionMetrics_ClientsCountedByLocality(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	local := data.createEndpoint(nse1Name, localNSMName)
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: local})
+
+	locals, remotes := data.clientCount(metrics.LocalityLocal), data.clientCount(metrics.LocalityRemote)
+	dials := histogramCount(g, metrics.RemoteDialDurationSeconds)
+	_, err := data.nseManager.CreateNSEClient(context.Background(), local)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.clientCount(metrics.LocalityLocal)).To(Equal(locals + 1))
+	g.Expect(histogramCount(g, metrics.RemoteDialDurationSeconds)).To(Equal(dials))
+
+	_, err = data.nseManager.CreateNSEClient(context.Background(), data.createEndpoint(nse2Name, remoteNSMName))
+	g.Expect(err).To(BeNil())
+	g.Expect(data.clientCount(metrics.LocalityRemote)).To(Equal(remotes + 1))
+	g.Expect(data.clientCount(metrics.LocalityLocal)).To(Equal(locals + 1))
+	g.Expect(histogramCount(g, metrics.RemoteDialDurationSeconds)).To(Equal(dials + 1))
+}
+
+func resolutionCount(g *WithT, path, outcome string) uint64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	g.Expect(err).To(BeNil())
+	for _, family := range families {
+		if family.GetName() != metrics.EndpointResolutionDurationSeconds {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels[metrics.ServiceKey] == networkServiceName && labels[metrics.PathKey] == path && labels[metrics.OutcomeKey] == outcome {
+				return metric.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return 0
+}
+
+func TestSelectionMetrics_ResolutionLatencyByPath(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	selected := resolutionCount(g, metrics.PathSelected, metrics.OutcomeSuccess)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(resolutionCount(g, metrics.PathSelected, metrics.OutcomeSuccess)).To(Equal(selected + 1))
+
+	targeted := resolutionCount(g, metrics.PathTargeted, metrics.OutcomeSuccess)
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse1Name, remoteNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(resolutionCount(g, metrics.PathTargeted, metrics.OutcomeSuccess)).To(Equal(targeted + 1))
+
+	failed := resolutionCount(g, metrics.PathSelected, metrics.OutcomeFailure)
+	data.setDiscoveredEndpoints()
+	_, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(resolutionCount(g, metrics.PathSelected, metrics.OutcomeFailure)).To(Equal(failed + 1))
+}
+
+func TestSelectionMetrics_ResolutionLatencyLocalOnly(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withFailingDiscovery, withLocalEndpoints(nse1Name))
+	data.nseManager.props.DiscoveryFailOpen = true
+
+	localOnly := resolutionCount(g, metrics.PathLocalOnly, metrics.OutcomeSuccess)
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(resolutionCount(g, metrics.PathLocalOnly, metrics.OutcomeSuccess)).To(Equal(localOnly + 1))
+}
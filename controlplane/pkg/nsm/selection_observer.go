@@ -0,0 +1,87 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/metrics"
+)
+
+// SelectionEvent - outcome of GetEndpoint.
+type SelectionEvent struct {
+	// Connection - copy of request connection.
+	Connection *connection.Connection
+	// Endpoint - resolved endpoint, nil if GetEndpoint failed.
+	Endpoint *registry.NSERegistration
+	// Targeted - endpoint is the one request targeted by name, rather than selected.
+	Targeted bool
+	// Err - error GetEndpoint failed with.
+	Err error
+}
+
+// SelectionObserver - notified about every GetEndpoint outcome, both successful and failed, e.g. to audit selection
+// decisions. Observer is called in its own goroutine and can not affect the request.
+type SelectionObserver interface {
+	SelectionResolved(event SelectionEvent)
+}
+
+// WithSelectionObserver - notify observer about outcomes of GetEndpoint.
+func WithSelectionObserver(observer SelectionObserver) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.selectionObserver = observer
+	}
+}
+
+// observeSelection - records resolution time of GetEndpoint started at start and notifies selection observer, if
+// any, about its outcome.
+func (nsem *nseManager) observeSelection(start time.Time, requestConnection *connection.Connection, result *SelectionResult,
+	endpoint *registry.NSERegistration, err error) {
+	outcome := metrics.OutcomeSuccess
+	if err != nil {
+		outcome = metrics.OutcomeFailure
+	}
+	nsem.resolveDuration.WithLabelValues(requestConnection.GetNetworkService(), selectionPath(requestConnection, result), outcome).
+		Observe(time.Since(start).Seconds())
+	if nsem.selectionObserver != nil {
+		go nsem.selectionObserver.SelectionResolved(SelectionEvent{
+			Connection: requestConnection.Clone(),
+			Endpoint:   endpoint,
+			Targeted:   endpoint != nil && isTargeted(requestConnection, result),
+			Err:        err,
+		})
+	}
+}
+
+// isTargeted - tells if request is resolved to endpoint it is targeted to by name rather than by selection.
+func isTargeted(requestConnection *connection.Connection, result *SelectionResult) bool {
+	return requestConnection.GetNetworkServiceEndpointName() != "" && !result.Unpinned
+}
+
+// selectionPath - returns path endpoint of request was resolved or failed to be resolved through, see metrics.PathKey.
+func selectionPath(requestConnection *connection.Connection, result *SelectionResult) string {
+	switch {
+	case result.LocalOnly:
+		return metrics.PathLocalOnly
+	case isTargeted(requestConnection, result):
+		return metrics.PathTargeted
+	default:
+		return metrics.PathSelected
+	}
+}
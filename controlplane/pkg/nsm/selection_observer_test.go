@@ -0,0 +1,64 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+)
+
+type selectionObserverStub struct {
+	events chan SelectionEvent
+	block  chan struct{}
+}
+
+func (stub *selectionObserverStub) SelectionResolved(event SelectionEvent) {
+	<-stub.block
+	stub.events <- event
+}
+
+func newSelectionObserverStub() *selectionObserverStub {
+	return &selectionObserverStub{events: make(chan SelectionEvent, 10), block: make(chan struct{})}
+}
+
+func TestSelectionObserver(t *testing.T) {
+	g := NewWithT(t)
+	observer := newSelectionObserverStub()
+	data := newNseManagerTestData(withManagerOptions(WithSelectionObserver(observer)), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+
+	// Blocked observer does not block requests.
+	selected, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	targeted, err := data.nseManager.GetEndpoint(context.Background(), newTargetedRequestConnection(nse2Name, remoteNSMName), nil)
+	g.Expect(err).To(BeNil())
+	close(observer.block)
+
+	events := map[string]SelectionEvent{}
+	for i := 0; i < 2; i++ {
+		var event SelectionEvent
+		g.Eventually(observer.events).Should(Receive(&event))
+		events[event.Connection.GetNetworkServiceEndpointName()] = event
+	}
+	g.Expect(events[""].Endpoint).To(Equal(selected))
+	g.Expect(events[""].Targeted).To(BeFalse())
+	g.Expect(events[nse2Name].Endpoint).To(Equal(targeted))
+	g.Expect(events[nse2Name].Targeted).To(BeTrue())
+}
+
+func TestSelectionObserver_Failure(t *testing.T) {
+	g := NewWithT(t)
+	observer := newSelectionObserverStub()
+	data := newNseManagerTestData(withManagerOptions(WithSelectionObserver(observer)), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	close(observer.block)
+	data.serviceRegistry.discoveryClient.error = errors.New("registry is down")
+
+	_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).NotTo(BeNil())
+
+	var event SelectionEvent
+	g.Eventually(observer.events).Should(Receive(&event))
+	g.Expect(event.Endpoint).To(BeNil())
+	g.Expect(event.Targeted).To(BeFalse())
+	g.Expect(event.Err).To(Equal(err))
+}
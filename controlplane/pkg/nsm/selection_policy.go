@@ -0,0 +1,58 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"math/rand"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// WithSelectionPolicies - resolve selection policies of network services with registry instead of
+// selector.NewPolicyRegistry.
+func WithSelectionPolicies(policies *selector.PolicyRegistry) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.policies = policies
+	}
+}
+
+// WithSelectionSource - resolve selection policies with selector.NewPolicyRegistryWithSource(source) instead of
+// selector.NewPolicyRegistry, so random selections are reproducible given a fixed seed source. Overrides
+// WithSelectionPolicies given before it.
+func WithSelectionSource(source rand.Source) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.policies = selector.NewPolicyRegistryWithSource(source)
+	}
+}
+
+// policySelector - returns selector of selection policy declared for network service in
+// properties.SelectionPolicies, nil if it declares none or declared policy is not registered.
+func (nsem *nseManager) policySelector(ns *registry.NetworkService) selector.Selector {
+	policy, ok := nsem.props.SelectionPolicies[ns.GetName()]
+	if !ok {
+		return nil
+	}
+	policySelector, ok := nsem.policies.Selector(policy)
+	if !ok {
+		logrus.Warnf("Network service %s declares unknown selection policy %q, using model selector", ns.GetName(), policy)
+		return nil
+	}
+	return policySelector
+}
@@ -0,0 +1,102 @@
+package nsm
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+const (
+	lastPolicy       = "last"
+	lastServiceName  = "last-service"
+	roundServiceName = "round-service"
+)
+
+// discoverServices - discovers the same three endpoints for each of services.
+func (data *nseManagerTestData) discoverServices(services ...string) *scriptedDiscovery {
+	withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name)(data)
+	discovery := data.withScriptedDiscovery()
+	for _, service := range services {
+		response := data.createFindNetworkServiceResponse(data.endpoints...)
+		response.NetworkService.Name = service
+		discovery.script(service, discoveryStep{response: response})
+	}
+	return discovery
+}
+
+// withServices - discovers the same three endpoints for each of services, registers the last endpoint policy.
+func withServices(services ...string) testDataOption {
+	return func(data *nseManagerTestData) {
+		data.discoverServices(services...)
+		policies := selector.NewPolicyRegistry()
+		policies.Register(lastPolicy, &selectorStub{pick: lastEndpoint})
+		withManagerOptions(WithSelectionPolicies(policies))(data)
+	}
+}
+
+func (data *nseManagerTestData) selectedForService(service string, n int) []string {
+	var names []string
+	for i := 0; i < n; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), &connection.Connection{NetworkService: service}, nil)
+		if err != nil {
+			return append(names, err.Error())
+		}
+		names = append(names, endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	return names
+}
+
+func TestSelectionPolicy_PerService(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withServices(lastServiceName, roundServiceName))
+	data.nseManager.props.SelectionPolicies = map[string]string{
+		lastServiceName:  lastPolicy,
+		roundServiceName: selector.PolicyRoundRobin,
+	}
+
+	g.Expect(data.selectedForService(lastServiceName, 3)).To(Equal([]string{nse3Name, nse3Name, nse3Name}))
+	g.Expect(data.selectedForService(roundServiceName, 3)).To(Equal([]string{nse1Name, nse2Name, nse3Name}))
+}
+
+func TestSelectionPolicy_Default(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withServices(lastServiceName, roundServiceName), withSelector(&selectorStub{pick: lastEndpoint}))
+	data.nseManager.props.SelectionPolicies = map[string]string{roundServiceName: "unknown"}
+
+	g.Expect(data.selectedForService(lastServiceName, 2)).To(Equal([]string{nse3Name, nse3Name}))
+	g.Expect(data.selectedForService(roundServiceName, 2)).To(Equal([]string{nse3Name, nse3Name}))
+}
+
+func TestManagerFairSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.ManagerFairSelection = true
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, "nsm-other"),
+	)
+
+	g.Expect(data.selectedNames(4)).To(Equal([]string{nse3Name, nse1Name, nse3Name, nse2Name}))
+}
+
+func TestSelectionPolicy_FixedSeedReproducible(t *testing.T) {
+	g := NewWithT(t)
+	var runs [][]string
+	for run := 0; run < 2; run++ {
+		data := newNseManagerTestData(withServices(roundServiceName))
+		WithSelectionSource(rand.NewSource(42))(data.nseManager)
+		data.nseManager.props.SelectionPolicies = map[string]string{roundServiceName: selector.PolicyRandom}
+		runs = append(runs, data.selectedForService(roundServiceName, 20))
+	}
+	g.Expect(runs[0]).To(HaveLen(20))
+	for _, name := range runs[0] {
+		g.Expect(name).To(BeElementOf(nse1Name, nse2Name, nse3Name))
+	}
+	g.Expect(runs[1]).To(Equal(runs[0]))
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// PreviewSelections - answers "what will be selected if these endpoints are ignored" for a number of ignore sets.
+// Discovery is performed once and shared by all sets, result[i] is an endpoint selected for ignoreSets[i] or nil if
+// nothing could be selected. Preview is side effect free: no connections are made and selector state is not advanced.
+// ErrPreviewNotSupported is returned if selector of network service does not implement selector.Peeker.
+func (nsem *nseManager) PreviewSelections(ctx context.Context, requestConnection *connection.Connection, ignoreSets []map[registry.EndpointNSMName]*registry.NSERegistration) ([]*registry.NSERegistration, error) {
+	span := spanhelper.FromContext(ctx, "PreviewSelections")
+	defer span.Finish()
+	span.LogObject("request", requestConnection)
+	span.LogValue("ignoreSets", len(ignoreSets))
+
+	endpointResponse, err := nsem.findNetworkService(span.Context(), span, requestConnection.GetNetworkService())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := nsem.activeSelector(endpointResponse.GetNetworkService()).(selector.Peeker); !ok {
+		span.LogError(ErrPreviewNotSupported)
+		return nil, ErrPreviewNotSupported
+	}
+
+	result := make([]*registry.NSERegistration, len(ignoreSets))
+	for i, ignoreEndpoints := range ignoreSets {
+		endpoint, _, err := nsem.selectEndpoint(nil, requestConnection, endpointResponse, ignoreEndpoints, nsem.peekEndpoint)
+		if err != nil {
+			span.Logger().Infof("Ignore set %d: %v", i, err)
+			continue
+		}
+		result[i] = newNSERegistration(endpointResponse, endpoint)
+	}
+	span.LogObject("previews", result)
+	return result, nil
+}
+
+// ListViableEndpoints - returns all endpoints of requested network service which survive filtering, in the order
+// they would be given to selector, and managers of the discovery response. Selector is not invoked and no connections
+// are made.
+func (nsem *nseManager) ListViableEndpoints(ctx context.Context, requestConnection *connection.Connection, ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) ([]*registry.NetworkServiceEndpoint, map[string]*registry.NetworkServiceManager, error) {
+	span := spanhelper.FromContext(ctx, "ListViableEndpoints")
+	defer span.Finish()
+	span.LogObject("request", requestConnection)
+	span.LogObject("ignores", newIgnoresSummary(ignoreEndpoints))
+
+	endpointResponse, err := nsem.findNetworkService(span.Context(), span, requestConnection.GetNetworkService())
+	if err != nil {
+		return nil, nil, err
+	}
+	endpoints, err := nsem.filterEndpoints(requestConnection, endpointResponse.GetNetworkServiceEndpoints(), endpointResponse.GetNetworkServiceManagers(), ignoreEndpoints)
+	if err != nil {
+		span.LogError(err)
+		return nil, nil, err
+	}
+	span.LogObject("viable", endpointNames(endpoints))
+	return endpoints, endpointResponse.GetNetworkServiceManagers(), nil
+}
+
+func (nsem *nseManager) peekEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+	if peeker, ok := nsem.activeSelector(ns).(selector.Peeker); ok {
+		return peeker.PeekEndpoint(requestConnection, ns, networkServiceEndpoints)
+	}
+	return nil
+}
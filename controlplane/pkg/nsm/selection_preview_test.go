@@ -0,0 +1,120 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func TestPreviewSelections(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse3 := data.createEndpoint(nse3Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1, nse2, nse3)
+
+	previews, err := data.nseManager.PreviewSelections(context.Background(), newTestRequestConnection(), []map[registry.EndpointNSMName]*registry.NSERegistration{
+		data.ignores(),
+		data.ignores(nse1),
+		data.ignores(nse1, nse2),
+		data.ignores(nse1, nse2, nse3),
+	})
+	g.Expect(err).To(BeNil())
+	g.Expect(previews).To(HaveLen(4))
+	g.Expect(previews[0].GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(previews[1].GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(previews[2].GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+	g.Expect(previews[3]).To(BeNil())
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+
+	// Preview should not affect the real selection.
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
+
+func TestPreviewSelections_SelectorCannotPeek(t *testing.T) {
+	g := NewWithT(t)
+	newData := func() *nseManagerTestData {
+		return newNseManagerTestData(
+			withSelector(&selectorStub{pick: roundRobin()}),
+			withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	}
+
+	data := newData()
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	expected := endpoint.GetNetworkServiceEndpoint().GetName()
+
+	data = newData()
+	previews, err := data.nseManager.PreviewSelections(context.Background(), newTestRequestConnection(), []map[registry.EndpointNSMName]*registry.NSERegistration{
+		data.ignores(),
+	})
+	g.Expect(errors.Is(err, ErrPreviewNotSupported)).To(BeTrue())
+	g.Expect(previews).To(BeNil())
+
+	// Failed preview should not advance selector.
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(expected))
+}
+
+func TestPreviewSelections_DiscoveryError(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.serviceRegistry.discoveryClient.error = errors.New("registry is down")
+
+	previews, err := data.nseManager.PreviewSelections(context.Background(), newTestRequestConnection(), []map[registry.EndpointNSMName]*registry.NSERegistration{
+		data.ignores(),
+	})
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(previews).To(BeNil())
+}
+
+func TestPreviewSelections_NoDrainNotifications(t *testing.T) {
+	g := NewWithT(t)
+	notifications := make(drainNotifierStub, 10)
+	data := newNseManagerTestData(withManagerOptions(WithDrainNotifier(notifications)), withEndpoints(remoteNSMName, nse1Name, nse2Name))
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: "1", Endpoint: data.endpoints[0]})
+	data.endpoints[0].NetworkServiceEndpoint.Labels = map[string]string{EndpointUpgradingLabel: "true"}
+
+	previews, err := data.nseManager.PreviewSelections(context.Background(), newTestRequestConnection(), []map[registry.EndpointNSMName]*registry.NSERegistration{
+		data.ignores(),
+	})
+	g.Expect(err).To(BeNil())
+	g.Expect(previews[0].GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	_, _, err = data.nseManager.ListViableEndpoints(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Consistently(notifications, 50*time.Millisecond).ShouldNot(Receive())
+}
+
+func TestListViableEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	nse2 := data.createEndpoint(nse2Name, remoteNSMName)
+	nse3 := data.createEndpoint(nse3Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse3, nse1, nse2)
+
+	for i := 0; i < 3; i++ {
+		endpoints, managers, err := data.nseManager.ListViableEndpoints(context.Background(), newTestRequestConnection(), data.ignores(nse2))
+		g.Expect(err).To(BeNil())
+		g.Expect(endpointNames(endpoints)).To(Equal([]string{nse1Name, nse3Name}))
+		g.Expect(managers).To(HaveKey(remoteNSMName))
+	}
+	g.Expect(data.serviceRegistry.remoteDials).To(BeEmpty())
+
+	// Listing should not advance selector.
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+}
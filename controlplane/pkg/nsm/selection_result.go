@@ -0,0 +1,75 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+type selectionResultKey struct{}
+
+// SelectionResult - details of endpoint selection GetEndpoint reports in addition to returned registration.
+type SelectionResult struct {
+	// Confidence - how clear-cut the choice was, from 0 to 1. Targeted requests and single candidates have 1.
+	Confidence float64
+	// Unpinned - pinned endpoint was unavailable and another one was selected as request allowed with nsm/unpin-on-failure.
+	Unpinned bool
+	// Token - opaque selection token a client can pass with nsm/selection-token label to get the same endpoint
+	// without full selection while token is valid, empty if tokens are disabled.
+	Token string
+	// Generation - hash of discovered endpoint set, changes only when endpoints of network service change.
+	Generation string
+	// DiscoveredAt - when discovery response endpoint was selected from was fetched from registry, which does not
+	// version its responses. Earlier than selection if response was cached, zero if endpoint was resolved without
+	// discovery, e.g. endpoint of local NSM request is targeted to.
+	DiscoveredAt time.Time
+	// LocalOnly - discovery failed and endpoint was selected among endpoints registered at local NSM, see
+	// properties.DiscoveryFailOpen.
+	LocalOnly bool
+	// ShadowEndpoint - endpoint shadow selector chose among the same candidates, empty if there is no shadow
+	// selector or endpoint was not selected by model selector.
+	ShadowEndpoint string
+	// Metadata - labels of selected endpoint allowed for export by properties.ExportedEndpointLabels.
+	Metadata map[string]string
+	// Selected - endpoint was chosen by selector, rather than targeted or reused bypassing it.
+	Selected bool
+	// Breadth - how many viable candidates selector chose among, 1 if selector was bypassed. Consistently low
+	// breadth means network service is one endpoint failure away from an outage.
+	Breadth int
+}
+
+// WithSelectionResult - asks GetEndpoint to fill result with details of endpoint selection.
+func WithSelectionResult(ctx context.Context, result *SelectionResult) context.Context {
+	return context.WithValue(ctx, selectionResultKey{}, result)
+}
+
+// logChoiceBreadth - logs how many candidates resolved endpoint was chosen among to span.
+func logChoiceBreadth(span spanhelper.SpanHelper, result *SelectionResult) {
+	span.LogValue("selected", result.Selected)
+	span.LogValue("breadth", result.Breadth)
+}
+
+// selectionResultFrom - returns SelectionResult passed with context or a throwaway one.
+func selectionResultFrom(ctx context.Context) *SelectionResult {
+	if result, ok := ctx.Value(selectionResultKey{}).(*SelectionResult); ok && result != nil {
+		return result
+	}
+	return &SelectionResult{}
+}
@@ -0,0 +1,52 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+func TestSelectionResult_ChoiceBreadth(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	withSelector(&selectorStub{
+		scores: map[string]float64{nse1Name: 3, nse2Name: 2, nse3Name: 1},
+	})(data)
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+	data.setDiscoveredEndpoints(nse1,
+		data.createEndpoint(nse2Name, remoteNSMName),
+		data.createEndpoint(nse3Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), data.ignores(nse1))
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+	g.Expect(result.Selected).To(BeTrue())
+	g.Expect(result.Breadth).To(Equal(2))
+
+	// Reporting breadth does not change the choice.
+	endpoint, err = data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), data.ignores(nse1))
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+
+	_, err = data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTargetedRequestConnection(nse3Name, remoteNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Selected).To(BeFalse())
+	g.Expect(result.Breadth).To(Equal(1))
+}
+
+func TestSelectionResult_LocalTargetBreadth(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	nse1 := data.createEndpoint(nse1Name, localNSMName)
+	data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: nse1})
+
+	result := &SelectionResult{Selected: true, Breadth: 5}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTargetedRequestConnection(nse1Name, localNSMName), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Selected).To(BeFalse())
+	g.Expect(result.Breadth).To(Equal(1))
+}
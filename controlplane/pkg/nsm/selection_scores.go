@@ -0,0 +1,151 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"container/heap"
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// DebugSelectionLabel - request label forcing export of selection scores regardless of sampling.
+const DebugSelectionLabel = "nsm/debug-selection"
+
+// CandidateScore - score a scoring selector gave to candidate endpoint.
+type CandidateScore struct {
+	Endpoint string
+	Score    float64
+}
+
+// SelectionScoresCallback - receives network service name, selected endpoint name and top scored candidates,
+// best first.
+type SelectionScoresCallback func(service, selected string, scores []CandidateScore)
+
+// selectionScoresExporter - samples selections of scoring selectors and passes candidate scores to callbacks,
+// zero value is ready to use.
+type selectionScoresExporter struct {
+	sync.Mutex
+	selections int
+	callbacks  []SelectionScoresCallback
+}
+
+// OnSelectionScores - registers a callback receiving candidate scores of sampled selections, see
+// properties.SelectionScoresSampleEvery. Callback is called asynchronously.
+func (nsem *nseManager) OnSelectionScores(callback SelectionScoresCallback) {
+	nsem.scoresExporter.Lock()
+	defer nsem.scoresExporter.Unlock()
+	nsem.scoresExporter.callbacks = append(nsem.scoresExporter.callbacks, callback)
+}
+
+// exportScores - exports top scored candidates of selection if it is sampled and candidates are scored.
+func (nsem *nseManager) exportScores(requestConnection *connection.Connection, ns *registry.NetworkService,
+	candidates []*registry.NetworkServiceEndpoint, selected *registry.NetworkServiceEndpoint, scores []float64) {
+	if scores == nil {
+		return
+	}
+	callbacks := nsem.scoresExporter.sample(requestConnection.GetLabels()[DebugSelectionLabel] == "true", nsem.props.SelectionScoresSampleEvery)
+	if len(callbacks) == 0 {
+		return
+	}
+	var top []CandidateScore
+	if k := nsem.props.SelectionScoresTopK; k > 0 && len(candidates) > nsem.props.SelectionScoresHeapThreshold {
+		top = topScoresHeap(scores, candidates, k)
+	} else {
+		top = topScores(scores, candidates, k)
+	}
+	for _, callback := range callbacks {
+		go callback(ns.GetName(), selected.GetName(), top)
+	}
+}
+
+// sample - counts selection and returns callbacks to export it to, none if selection is not sampled.
+func (e *selectionScoresExporter) sample(forced bool, every int) []SelectionScoresCallback {
+	e.Lock()
+	defer e.Unlock()
+	e.selections++
+	if !forced && (every <= 0 || e.selections%every != 0) {
+		return nil
+	}
+	return append([]SelectionScoresCallback(nil), e.callbacks...)
+}
+
+func topScores(scores []float64, candidates []*registry.NetworkServiceEndpoint, k int) []CandidateScore {
+	result := make([]CandidateScore, 0, len(candidates))
+	for i, candidate := range candidates {
+		if i < len(scores) {
+			result = append(result, CandidateScore{Endpoint: candidate.GetName(), Score: scores[i]})
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Score > result[j].Score
+	})
+	if k > 0 && len(result) > k {
+		result = result[:k]
+	}
+	return result
+}
+
+// indexedScore - score of candidate with its index, candidates with the same score rank in discovery order.
+type indexedScore struct {
+	index int
+	score float64
+}
+
+func (s indexedScore) better(other indexedScore) bool {
+	return s.score > other.score || s.score == other.score && s.index < other.index
+}
+
+// worstFirst - heap of k best scores seen so far, the worst of them on top.
+type worstFirst []indexedScore
+
+func (h worstFirst) Len() int            { return len(h) }
+func (h worstFirst) Less(i, j int) bool  { return h[j].better(h[i]) }
+func (h worstFirst) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *worstFirst) Push(x interface{}) { *h = append(*h, x.(indexedScore)) }
+func (h *worstFirst) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// topScoresHeap - same as topScores for positive k, finds k best scores in a single pass with a bounded heap
+// instead of sorting all candidates.
+func topScoresHeap(scores []float64, candidates []*registry.NetworkServiceEndpoint, k int) []CandidateScore {
+	h := make(worstFirst, 0, k)
+	for i := range candidates {
+		if i >= len(scores) {
+			break
+		}
+		current := indexedScore{index: i, score: scores[i]}
+		if len(h) < k {
+			heap.Push(&h, current)
+		} else if current.better(h[0]) {
+			h[0] = current
+			heap.Fix(&h, 0)
+		}
+	}
+	result := make([]CandidateScore, len(h))
+	for i := len(h) - 1; i >= 0; i-- {
+		best := heap.Pop(&h).(indexedScore)
+		result[i] = CandidateScore{Endpoint: candidates[best.index].GetName(), Score: best.score}
+	}
+	return result
+}
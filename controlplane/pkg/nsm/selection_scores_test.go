@@ -0,0 +1,116 @@
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type exportedScores struct {
+	selected string
+	scores   []CandidateScore
+}
+
+// withTopScores - selector scores nse-2 over nse-3 over nse-1, top 2 scores are exported.
+func withTopScores(data *nseManagerTestData) {
+	withSelector(&selectorStub{
+		scores: map[string]float64{nse1Name: 1, nse2Name: 3, nse3Name: 2},
+	})(data)
+	data.nseManager.props.SelectionScoresTopK = 2
+}
+
+func (data *nseManagerTestData) exportedScores() chan exportedScores {
+	exported := make(chan exportedScores, 10)
+	data.nseManager.OnSelectionScores(func(service, selected string, scores []CandidateScore) {
+		exported <- exportedScores{selected: selected, scores: scores}
+	})
+	return exported
+}
+
+func TestSelectionScores_NotExportedByDefault(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withTopScores, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	exported := data.exportedScores()
+
+	for i := 0; i < 5; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Consistently(exported, 100*time.Millisecond).ShouldNot(Receive())
+}
+
+func TestSelectionScores_ExportedForForcedRequest(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withTopScores, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	exported := data.exportedScores()
+
+	requestConnection := newTestRequestConnection(withLabel(DebugSelectionLabel, "true"))
+	_, err := data.nseManager.GetEndpoint(context.Background(), requestConnection, nil)
+	g.Expect(err).To(BeNil())
+
+	var scores exportedScores
+	g.Eventually(exported).Should(Receive(&scores))
+	g.Expect(scores.selected).To(Equal(nse2Name))
+	g.Expect(scores.scores).To(Equal([]CandidateScore{
+		{Endpoint: nse2Name, Score: 3},
+		{Endpoint: nse3Name, Score: 2},
+	}))
+}
+
+func TestSelectionScores_ExportedForSampledRequests(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withTopScores, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	exported := data.exportedScores()
+	data.nseManager.props.SelectionScoresSampleEvery = 3
+
+	for i := 0; i < 6; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Eventually(exported).Should(Receive())
+	g.Eventually(exported).Should(Receive())
+	g.Consistently(exported, 100*time.Millisecond).ShouldNot(Receive())
+}
+
+func newScoredCandidates(n int) ([]float64, []*registry.NetworkServiceEndpoint) {
+	random := rand.New(rand.NewSource(1))
+	scores := make([]float64, n)
+	candidates := make([]*registry.NetworkServiceEndpoint, n)
+	for i := range candidates {
+		// Few distinct scores, so there are ties to break.
+		scores[i] = float64(random.Intn(n / 10))
+		candidates[i] = &registry.NetworkServiceEndpoint{Name: fmt.Sprintf("nse-%d", i)}
+	}
+	return scores, candidates
+}
+
+func TestTopScoresHeap_SameAsSorting(t *testing.T) {
+	g := NewWithT(t)
+	scores, candidates := newScoredCandidates(20000)
+	for _, k := range []int{1, 5, 100, 20000, 30000} {
+		g.Expect(topScoresHeap(scores, candidates, k)).To(Equal(topScores(scores, candidates, k)))
+	}
+	g.Expect(topScoresHeap(scores[:10], candidates, 5)).To(Equal(topScores(scores[:10], candidates, 5)))
+}
+
+func BenchmarkTopScores(b *testing.B) {
+	scores, candidates := newScoredCandidates(50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		topScores(scores, candidates, 5)
+	}
+}
+
+func BenchmarkTopScoresHeap(b *testing.B) {
+	scores, candidates := newScoredCandidates(50000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		topScoresHeap(scores, candidates, 5)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// skewMinSelections - skew is not evaluated until window has this many selections, to not alert on first few ones.
+const skewMinSelections = 10
+
+// SelectionSkewCallback - receives network service name and its selection skew,
+// the ratio of the most selected endpoint selections to the average selections per endpoint.
+type SelectionSkewCallback func(service string, skew float64)
+
+type skewWindow struct {
+	start    time.Time
+	total    int
+	counts   map[string]int
+	reported bool
+}
+
+// selectionSkewMonitor - counts selections of endpoints per network service within a window, zero value is ready to use.
+type selectionSkewMonitor struct {
+	sync.Mutex
+	windows   map[string]*skewWindow
+	callbacks []SelectionSkewCallback
+}
+
+// OnSelectionSkew - registers a callback called when selection skew of network service exceeds
+// properties.SelectionSkewThreshold. Callback is called asynchronously, at most once per service per window.
+func (nsem *nseManager) OnSelectionSkew(callback SelectionSkewCallback) {
+	nsem.skewMonitor.Lock()
+	defer nsem.skewMonitor.Unlock()
+	nsem.skewMonitor.callbacks = append(nsem.skewMonitor.callbacks, callback)
+}
+
+// selectAndRecord - selects endpoint with model selector and records selection for skew monitoring.
+func (nsem *nseManager) selectAndRecord(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+	endpoint := nsem.selectCandidate(nsem.activeSelector(ns), requestConnection, ns, endpoints, managers)
+	if endpoint != nil {
+		nsem.recordSkew(ns, endpoints, endpoint, managers)
+	}
+	return endpoint
+}
+
+// recordSkew - records selection for skew monitoring, endpoints are counted by their identity so same named
+// endpoints of different managers are told apart.
+func (nsem *nseManager) recordSkew(ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint, selected *registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) {
+	if nsem.props.SelectionSkewThreshold <= 0 {
+		return
+	}
+	candidates := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		candidates = append(candidates, nsem.identity.Key(endpoint, managers[endpoint.GetNetworkServiceManagerName()]))
+	}
+	selectedKey := nsem.identity.Key(selected, managers[selected.GetNetworkServiceManagerName()])
+	nsem.skewMonitor.record(ns.GetName(), candidates, selectedKey, nsem.props.SelectionSkewThreshold, nsem.props.SelectionSkewWindow)
+}
+
+func (m *selectionSkewMonitor) record(service string, candidates []string, selected string, threshold float64, window time.Duration) {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.windows == nil {
+		m.windows = map[string]*skewWindow{}
+	}
+	w := m.windows[service]
+	if w == nil || time.Since(w.start) > window {
+		w = &skewWindow{start: time.Now(), counts: map[string]int{}}
+		m.windows[service] = w
+	}
+	for _, candidate := range candidates {
+		if _, ok := w.counts[candidate]; !ok {
+			w.counts[candidate] = 0
+		}
+	}
+	w.counts[selected]++
+	w.total++
+
+	if w.reported || w.total < skewMinSelections {
+		return
+	}
+	skew := w.skew()
+	if skew <= threshold {
+		return
+	}
+	w.reported = true
+	for _, callback := range m.callbacks {
+		go callback(service, skew)
+	}
+}
+
+func (w *skewWindow) skew() float64 {
+	max := 0
+	for _, count := range w.counts {
+		if count > max {
+			max = count
+		}
+	}
+	return float64(max) * float64(len(w.counts)) / float64(w.total)
+}
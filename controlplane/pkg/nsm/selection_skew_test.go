@@ -0,0 +1,75 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestSelectionSkew_ReportedOncePerWindow(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.SelectionSkewThreshold = 1.5
+
+	skews := make(chan float64, 10)
+	data.nseManager.OnSelectionSkew(func(service string, skew float64) {
+		g.Expect(service).To(Equal(networkServiceName))
+		skews <- skew
+	})
+
+	for i := 0; i < 100; i++ {
+		data.nseManager.skewMonitor.record(networkServiceName, []string{nse1Name, nse2Name}, nse1Name,
+			data.nseManager.props.SelectionSkewThreshold, data.nseManager.props.SelectionSkewWindow)
+	}
+
+	g.Eventually(skews).Should(Receive(BeNumerically("==", 2.0)))
+	g.Consistently(skews, 100*time.Millisecond).ShouldNot(Receive())
+}
+
+func TestSelectionSkew_BalancedSelectionNotReported(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.SelectionSkewThreshold = 1.5
+
+	skews := make(chan float64, 10)
+	data.nseManager.OnSelectionSkew(func(service string, skew float64) {
+		skews <- skew
+	})
+
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName), data.createEndpoint(nse2Name, remoteNSMName))
+	for i := 0; i < 100; i++ {
+		_, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+	}
+	g.Consistently(skews, 100*time.Millisecond).ShouldNot(Receive())
+}
+
+func TestSelectionSkew_SameNamedEndpointsToldApart(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.nseManager.props.SelectionSkewThreshold = 1.5
+
+	skews := make(chan float64, 10)
+	data.nseManager.OnSelectionSkew(func(service string, skew float64) {
+		skews <- skew
+	})
+
+	nse1 := data.createEndpoint(nse1Name, "nsm-1")
+	nse2 := data.createEndpoint(nse1Name, "nsm-2")
+	nse1.NetworkServiceManager.Url = "nsm-1:5001"
+	nse2.NetworkServiceManager.Url = "nsm-2:5001"
+	candidates := []*registry.NetworkServiceEndpoint{nse1.GetNetworkServiceEndpoint(), nse2.GetNetworkServiceEndpoint()}
+	managers := map[string]*registry.NetworkServiceManager{
+		"nsm-1": nse1.GetNetworkServiceManager(),
+		"nsm-2": nse2.GetNetworkServiceManager(),
+	}
+	for i := 0; i < 100; i++ {
+		data.nseManager.recordSkew(nse1.GetNetworkService(), candidates, nse1.GetNetworkServiceEndpoint(), managers)
+	}
+
+	g.Eventually(skews).Should(Receive(BeNumerically("==", 2.0)))
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// SelectionTokenLabel - request label carrying selection token of previous request, see SelectionResult.Token.
+const SelectionTokenLabel = "nsm/selection-token"
+
+const selectionTokenKeySize = 32
+
+// selectionToken - payload of selection token. Tokens are signed with a key of nseManager instance, so they are
+// valid only for the NSMgr which issued them.
+type selectionToken struct {
+	Service  string `json:"s"`
+	Endpoint string `json:"e"`
+	Manager  string `json:"m"`
+	Expires  int64  `json:"x"`
+}
+
+func newSelectionTokenKey() []byte {
+	key := make([]byte, selectionTokenKeySize)
+	if _, err := rand.Read(key); err != nil {
+		logrus.Errorf("Failed to generate selection token key, selection tokens are disabled: %v", err)
+		return nil
+	}
+	return key
+}
+
+// issueSelectionToken - returns token for selected endpoint, empty if tokens are disabled.
+func (nsem *nseManager) issueSelectionToken(networkService string, endpoint *registry.NetworkServiceEndpoint) string {
+	if len(nsem.tokenKey) == 0 || nsem.props.SelectionTokenTTL <= 0 {
+		return ""
+	}
+	payload, err := json.Marshal(&selectionToken{
+		Service:  networkService,
+		Endpoint: endpoint.GetName(),
+		Manager:  endpoint.GetNetworkServiceManagerName(),
+		Expires:  time.Now().Add(nsem.props.SelectionTokenTTL).UnixNano(),
+	})
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(nsem.signSelectionToken(payload))
+}
+
+func (nsem *nseManager) signSelectionToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, nsem.tokenKey)
+	_, _ = mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// parseSelectionToken - verifies token signature and expiration, returns nil if token is not valid.
+func (nsem *nseManager) parseSelectionToken(token string) *selectionToken {
+	if len(nsem.tokenKey) == 0 {
+		return nil
+	}
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, nsem.signSelectionToken(payload)) {
+		return nil
+	}
+	result := &selectionToken{}
+	if err := json.Unmarshal(payload, result); err != nil || time.Now().UnixNano() > result.Expires {
+		return nil
+	}
+	return result
+}
+
+// endpointFromToken - returns endpoint of request selection token if token is valid and endpoint is still
+// discovered and survives filtering, nil means full selection is required.
+func (nsem *nseManager) endpointFromToken(span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	candidates func() []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	tokenValue := requestConnection.GetLabels()[SelectionTokenLabel]
+	if tokenValue == "" {
+		return nil
+	}
+	token := nsem.parseSelectionToken(tokenValue)
+	if token == nil || token.Service != requestConnection.GetNetworkService() {
+		span.LogValue("selectionToken", "invalid")
+		return nil
+	}
+	if endpoint := findEndpoint(candidates(), token.Endpoint, token.Manager); endpoint != nil {
+		span.LogValue("selectionToken", "reused")
+		return endpoint
+	}
+	span.LogValue("selectionToken", "stale")
+	return nil
+}
@@ -0,0 +1,96 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func withSelectionTokens(data *nseManagerTestData) {
+	data.nseManager.props.SelectionTokenTTL = 30 * time.Second
+}
+
+func TestSelectionToken_IssuedAndReused(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionTokens, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	result := &SelectionResult{}
+	first, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.Token).NotTo(BeEmpty())
+
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(SelectionTokenLabel, result.Token)), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(first.GetNetworkServiceEndpoint().GetName()))
+	}
+}
+
+func TestSelectionToken_ExpiredFallsBackToSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionTokens, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	data.nseManager.props.SelectionTokenTTL = 10 * time.Millisecond
+
+	result := &SelectionResult{}
+	first, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	<-time.After(20 * time.Millisecond)
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(SelectionTokenLabel, result.Token)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).NotTo(Equal(first.GetNetworkServiceEndpoint().GetName()))
+}
+
+func TestSelectionToken_InvalidFallsBackToSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionTokens, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	result := &SelectionResult{}
+	first, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(first.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+
+	// Tokens of another NSMgr are signed with another key.
+	foreign := newNseManagerTestData(withSelectionTokens, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name)).nseManager.issueSelectionToken(networkServiceName, first.GetNetworkServiceEndpoint())
+	selected := []string{}
+	for _, token := range []string{"garbage", result.Token + "x", foreign} {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(SelectionTokenLabel, token)), nil)
+		g.Expect(err).To(BeNil())
+		selected = append(selected, endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	g.Expect(selected).To(Equal([]string{nse2Name, nse3Name, nse1Name}))
+}
+
+func TestSelectionToken_RemovedEndpointFallsBackToSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionTokens, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	result := &SelectionResult{}
+	first, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(first.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	data.setDiscoveredEndpoints(data.createEndpoint(nse2Name, remoteNSMName))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(SelectionTokenLabel, result.Token)), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse2Name))
+}
+
+func TestSelectionToken_FilteredOutEndpointFallsBackToSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSelectionTokens, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	result := &SelectionResult{}
+	first, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+
+	// Token does not bring back endpoint which is quarantined, e.g. as a black hole.
+	data.nseManager.quarantine.add(data.nseManager.identity.Key(first.GetNetworkServiceEndpoint(), first.GetNetworkServiceManager()), time.Hour)
+	for i := 0; i < 3; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withLabel(SelectionTokenLabel, result.Token)), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).NotTo(Equal(first.GetNetworkServiceEndpoint().GetName()))
+	}
+}
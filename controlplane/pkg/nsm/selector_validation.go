@@ -0,0 +1,143 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// selectorValidationRequestsPerEndpoint - synthetic requests made per candidate endpoint, so a fair selector
+// is expected to select every candidate a few times.
+const selectorValidationRequestsPerEndpoint = 4
+
+// ValidationReport - outcome of selector dry-run against current endpoints of network service.
+type ValidationReport struct {
+	Service string
+	// Candidates - endpoints selector was choosing from.
+	Candidates int
+	// Requests - synthetic requests selector was asked to serve.
+	Requests int
+	// Selections - selections by endpoint name, endpoints never selected have zero count.
+	Selections map[string]int
+	// Failures - requests selector selected nothing for.
+	Failures int
+	// Skew - ratio of the most selected endpoint selections to the average selections per endpoint.
+	Skew float64
+	// Issues - human readable problems found, empty if selector passed validation.
+	Issues []string
+}
+
+// Passed - returns true if no issues were found.
+func (r *ValidationReport) Passed() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidateSelectorForService - runs selector in dry-run over synthetic requests against live discovery of network
+// service and reports coverage and skew issues, to catch misconfigured selector before it serves the service.
+// Dry-run has no side effects on the manager: nothing is reserved, recorded or connected, the only state
+// advanced is the state of validated selector itself.
+func (nsem *nseManager) ValidateSelectorForService(ctx context.Context, serviceName string, s selector.Selector) (*ValidationReport, error) {
+	span := spanhelper.FromContext(ctx, "ValidateSelectorForService")
+	defer span.Finish()
+	span.LogValue("service", serviceName)
+
+	endpointResponse, err := nsem.findNetworkService(span.Context(), span, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := nsem.filterEndpoints(newValidationRequest(serviceName, 0), endpointResponse.GetNetworkServiceEndpoints(), endpointResponse.GetNetworkServiceManagers(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, errors.Errorf("NetworkService %s has no endpoints to validate selector against", serviceName)
+	}
+
+	report := &ValidationReport{
+		Service:    serviceName,
+		Candidates: len(candidates),
+		Requests:   len(candidates) * selectorValidationRequestsPerEndpoint,
+	}
+	counts := &skewWindow{counts: map[string]int{}}
+	for _, candidate := range candidates {
+		counts.counts[candidate.GetName()] = 0
+	}
+	selectFn := func(requestConnection *connection.Connection, ns *registry.NetworkService, endpoints []*registry.NetworkServiceEndpoint,
+		managers map[string]*registry.NetworkServiceManager) *registry.NetworkServiceEndpoint {
+		return nsem.selectCandidate(s, requestConnection, ns, endpoints, managers)
+	}
+	for i := 0; i < report.Requests; i++ {
+		endpoint, _, err := nsem.selectEndpoint(nil, newValidationRequest(serviceName, i), endpointResponse, nil, selectFn)
+		if err != nil {
+			span.Logger().Infof("Validation request %d: %v", i, err)
+			report.Failures++
+			continue
+		}
+		counts.counts[endpoint.GetName()]++
+		counts.total++
+	}
+	report.Selections = counts.counts
+	if counts.total > 0 {
+		report.Skew = counts.skew()
+	}
+	report.Issues = nsem.validationIssues(report)
+	span.LogObject("report", report)
+	return report, nil
+}
+
+func newValidationRequest(serviceName string, i int) *connection.Connection {
+	return &connection.Connection{
+		Id:             fmt.Sprintf("selector-validation-%d", i),
+		NetworkService: serviceName,
+	}
+}
+
+func (nsem *nseManager) validationIssues(report *ValidationReport) []string {
+	var issues []string
+	if report.Failures == report.Requests {
+		return append(issues, "selector selected no endpoint")
+	}
+	if report.Failures > 0 {
+		issues = append(issues, fmt.Sprintf("selector selected no endpoint for %d of %d requests", report.Failures, report.Requests))
+	}
+	if report.Candidates < 2 {
+		return issues
+	}
+	unselected := 0
+	for _, count := range report.Selections {
+		if count == 0 {
+			unselected++
+		}
+	}
+	if unselected == report.Candidates-1 {
+		issues = append(issues, "selector always selects the same endpoint")
+	} else if unselected > 0 {
+		issues = append(issues, fmt.Sprintf("selector never selected %d of %d endpoints", unselected, report.Candidates))
+	}
+	if max := nsem.props.SelectorValidationMaxSkew; max > 0 && report.Skew > max {
+		issues = append(issues, fmt.Sprintf("selection skew %.2f exceeds %.2f", report.Skew, max))
+	}
+	return issues
+}
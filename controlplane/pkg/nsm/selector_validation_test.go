@@ -0,0 +1,63 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+func TestValidateSelectorForService_Healthy(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name), withSelectionHistory(16))
+
+	report, err := data.nseManager.ValidateSelectorForService(context.Background(), networkServiceName, selector.NewRoundRobinSelector())
+	g.Expect(err).To(BeNil())
+	g.Expect(report.Passed()).To(BeTrue(), "%v", report.Issues)
+	g.Expect(report.Candidates).To(Equal(3))
+	g.Expect(report.Requests).To(Equal(3 * selectorValidationRequestsPerEndpoint))
+	g.Expect(report.Failures).To(BeZero())
+	g.Expect(report.Skew).To(Equal(1.0))
+	g.Expect(report.Selections).To(Equal(map[string]int{
+		nse1Name: selectorValidationRequestsPerEndpoint,
+		nse2Name: selectorValidationRequestsPerEndpoint,
+		nse3Name: selectorValidationRequestsPerEndpoint,
+	}))
+	g.Expect(data.nseManager.SelectionHistory("selector-validation-0")).To(BeEmpty())
+}
+
+func TestValidateSelectorForService_SameEndpoint(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	report, err := data.nseManager.ValidateSelectorForService(context.Background(), networkServiceName, &selectorStub{
+		scores: map[string]float64{nse2Name: 1},
+	})
+	g.Expect(err).To(BeNil())
+	g.Expect(report.Passed()).To(BeFalse())
+	g.Expect(report.Selections[nse2Name]).To(Equal(report.Requests))
+	g.Expect(report.Issues).To(ConsistOf(
+		"selector always selects the same endpoint",
+		"selection skew 3.00 exceeds 2.00"))
+}
+
+func TestValidateSelectorForService_SelectsNothing(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	report, err := data.nseManager.ValidateSelectorForService(context.Background(), networkServiceName, &selectorStub{pick: noEndpoint})
+	g.Expect(err).To(BeNil())
+	g.Expect(report.Failures).To(Equal(report.Requests))
+	g.Expect(report.Issues).To(ConsistOf("selector selected no endpoint"))
+}
+
+func TestValidateSelectorForService_NoEndpoints(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints()
+
+	_, err := data.nseManager.ValidateSelectorForService(context.Background(), networkServiceName, selector.NewRoundRobinSelector())
+	g.Expect(err).NotTo(BeNil())
+}
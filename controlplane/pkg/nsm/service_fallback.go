@@ -0,0 +1,61 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+// GetEndpointForServices - selects endpoint of the first network service of fallback chain which has a selectable
+// one, services are tried in order with network service of requestConnection replaced. Returned registration
+// tells which network service was selected. If none of services has selectable endpoint, error lists services
+// tried with the reason each of them failed.
+func (nsem *nseManager) GetEndpointForServices(ctx context.Context, requestConnection *connection.Connection, networkServices []string,
+	ignoreEndpoints map[registry.EndpointNSMName]*registry.NSERegistration) (*registry.NSERegistration, error) {
+	span := spanhelper.FromContext(ctx, "GetEndpointForServices")
+	defer span.Finish()
+	span.LogObject("networkServices", networkServices)
+
+	if len(networkServices) == 0 {
+		err := errors.New("no network services to select endpoint for")
+		span.LogError(err)
+		return nil, err
+	}
+	failures := make([]string, 0, len(networkServices))
+	for _, networkService := range networkServices {
+		serviceConnection := requestConnection.Clone()
+		serviceConnection.NetworkService = networkService
+		endpoint, err := nsem.GetEndpoint(span.Context(), serviceConnection, ignoreEndpoints)
+		if err == nil {
+			span.LogValue("networkService", networkService)
+			return endpoint, nil
+		}
+		span.Logger().Infof("No endpoint for NetworkService %s, trying next one: %v", networkService, err)
+		failures = append(failures, fmt.Sprintf("%s: %v", networkService, err))
+	}
+	err := errors.Errorf("failed to find NSE for any of NetworkServices %v: %s", networkServices, strings.Join(failures, "; "))
+	span.LogError(err)
+	return nil, err
+}
@@ -0,0 +1,45 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetEndpointForServices_Fallback(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	endpoint, err := data.nseManager.GetEndpointForServices(context.Background(), newTestRequestConnection(), []string{"unknown", networkServiceName}, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse1Name))
+	g.Expect(endpoint.GetNetworkService().GetName()).To(Equal(networkServiceName))
+}
+
+func TestGetEndpointForServices_Priority(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+	requestConnection := newTestRequestConnection()
+
+	_, err := data.nseManager.GetEndpointForServices(context.Background(), requestConnection, []string{networkServiceName, "unknown"}, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(data.serviceRegistry.discoveryClient.calls).To(Equal(1))
+	g.Expect(requestConnection.GetNetworkService()).To(Equal(networkServiceName))
+}
+
+func TestGetEndpointForServices_AllFailed(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	_, err := data.nseManager.GetEndpointForServices(context.Background(), newTestRequestConnection(), []string{"unknown", "other"}, nil)
+	g.Expect(err).NotTo(BeNil())
+	g.Expect(err.Error()).To(HavePrefix("failed to find NSE for any of NetworkServices [unknown other]: unknown: "))
+	g.Expect(err.Error()).To(ContainSubstring("; other: "))
+
+	_, err = data.nseManager.GetEndpointForServices(context.Background(), newTestRequestConnection(), nil, nil)
+	g.Expect(err).NotTo(BeNil())
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"context"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+	"github.com/networkservicemesh/networkservicemesh/pkg/tools/spanhelper"
+)
+
+type stickyEndpoint struct {
+	endpoint string
+	manager  string
+}
+
+// sessionAffinity - endpoints connections were last routed to by namespaced connection id, binding of connection
+// is dropped when it is deleted from model.
+type sessionAffinity struct {
+	model.ListenerImpl
+	sync.Mutex
+	namespace ConnectionNamespace
+	endpoints map[string]stickyEndpoint
+}
+
+func newSessionAffinity(m model.Model, namespace ConnectionNamespace) *sessionAffinity {
+	affinity := &sessionAffinity{
+		namespace: namespace,
+		endpoints: map[string]stickyEndpoint{},
+	}
+	m.AddListener(affinity)
+	return affinity
+}
+
+func (a *sessionAffinity) get(key string) (stickyEndpoint, bool) {
+	a.Lock()
+	defer a.Unlock()
+	sticky, ok := a.endpoints[key]
+	return sticky, ok
+}
+
+func (a *sessionAffinity) stick(key string, endpoint *registry.NetworkServiceEndpoint) {
+	a.Lock()
+	defer a.Unlock()
+	a.endpoints[key] = stickyEndpoint{
+		endpoint: endpoint.GetName(),
+		manager:  endpoint.GetNetworkServiceManagerName(),
+	}
+}
+
+// ClientConnectionDeleted - drops binding of closed connection.
+func (a *sessionAffinity) ClientConnectionDeleted(ctx context.Context, clientConnection *model.ClientConnection) {
+	a.Lock()
+	defer a.Unlock()
+	delete(a.endpoints, namespacedID(a.namespace(clientConnection.Request.GetConnection()), clientConnection.GetID()))
+}
+
+// endpointFromAffinity - returns endpoint connection was last routed to with properties.SessionAffinity, nil if
+// connection was not routed yet or the endpoint is gone or filtered out.
+func (nsem *nseManager) endpointFromAffinity(span spanhelper.SpanHelper, requestConnection *connection.Connection,
+	candidates func() []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	if !nsem.props.SessionAffinity || requestConnection.GetId() == "" {
+		return nil
+	}
+	sticky, ok := nsem.affinity.get(nsem.connectionKey(requestConnection))
+	if !ok {
+		return nil
+	}
+	endpoint := findEndpoint(candidates(), sticky.endpoint, sticky.manager)
+	if endpoint == nil {
+		span.LogValue("sessionAffinity", "moved")
+		return nil
+	}
+	span.LogValue("sessionAffinity", "sticky")
+	return endpoint
+}
+
+// stickEndpoint - binds connection to endpoint it is routed to with properties.SessionAffinity.
+func (nsem *nseManager) stickEndpoint(requestConnection *connection.Connection, endpoint *registry.NetworkServiceEndpoint) {
+	if nsem.props.SessionAffinity && requestConnection.GetId() != "" {
+		nsem.affinity.stick(nsem.connectionKey(requestConnection), endpoint)
+	}
+}
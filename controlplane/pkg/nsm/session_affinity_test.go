@@ -0,0 +1,97 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/model"
+)
+
+const stickyConnectionID = "sticky-connection"
+
+func withSessionAffinity(data *nseManagerTestData) {
+	data.nseManager.props.SessionAffinity = true
+}
+
+func (data *nseManagerTestData) stickySelection() (string, error) {
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(stickyConnectionID)), nil)
+	return endpoint.GetNetworkServiceEndpoint().GetName(), err
+}
+
+func TestSessionAffinity_Sticks(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSessionAffinity, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name), withSelectionHistory(16))
+
+	first, err := data.stickySelection()
+	g.Expect(err).To(BeNil())
+	for i := 0; i < 3; i++ {
+		g.Expect(data.stickySelection()).To(Equal(first))
+	}
+	g.Expect(data.nseManager.SelectionHistory(stickyConnectionID)[1].Reason).To(Equal(SelectionReasonSticky))
+	g.Expect(data.selectedNames(2)).To(ContainElement(Not(Equal(first))))
+}
+
+func TestSessionAffinity_Overridden(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSessionAffinity, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	nse1 := data.createEndpoint(nse1Name, remoteNSMName)
+
+	g.Expect(data.stickySelection()).To(Equal(nse1Name))
+
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(withID(stickyConnectionID)), data.ignores(nse1))
+	g.Expect(err).To(BeNil())
+	moved := endpoint.GetNetworkServiceEndpoint().GetName()
+	g.Expect(moved).NotTo(Equal(nse1Name))
+	g.Expect(data.stickySelection()).To(Equal(moved))
+
+	data.setDiscoveredEndpoints(nse1)
+	g.Expect(data.stickySelection()).To(Equal(nse1Name))
+}
+
+func TestSessionAffinity_EvictedOnClose(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSessionAffinity, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	g.Expect(data.stickySelection()).To(Equal(nse1Name))
+	data.model.AddClientConnection(context.Background(), &model.ClientConnection{ConnectionID: stickyConnectionID})
+	data.model.DeleteClientConnection(context.Background(), stickyConnectionID)
+	g.Eventually(func() bool {
+		_, ok := data.nseManager.affinity.get(stickyConnectionID)
+		return ok
+	}).Should(BeFalse())
+	g.Expect(data.stickySelection()).To(Equal(nse2Name))
+}
+
+func TestSessionAffinity_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+
+	g.Expect(data.stickySelection()).To(Equal(nse1Name))
+	g.Expect(data.stickySelection()).To(Equal(nse2Name))
+}
+
+func TestSessionAffinity_DoesNotOverrideFilters(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSessionAffinity, withEndpoints(remoteNSMName, nse1Name, nse2Name, nse3Name))
+	nse1 := data.createEndpoint(nse1Name, localNSMName)
+	nse2 := data.createEndpoint(nse2Name, localNSMName)
+	for _, nse := range []*registry.NSERegistration{nse1, nse2} {
+		data.model.AddEndpoint(context.Background(), &model.Endpoint{Endpoint: nse})
+	}
+	data.setDiscoveredEndpoints(nse1, nse2)
+
+	g.Expect(data.stickySelection()).To(Equal(nse1Name))
+	g.Expect(data.nseManager.DrainEndpoint(nse1Name)).To(BeNil())
+	g.Expect(data.stickySelection()).To(Equal(nse2Name))
+
+	// Connection bound to endpoint hosted by a manager it does not allow is moved too.
+	data.setDiscoveredEndpoints(nse2, data.createEndpoint(nse3Name, remoteNSMName))
+	request := newTestRequestConnection(withID(stickyConnectionID))
+	request.Labels = map[string]string{AllowedManagersLabel: remoteNSMName}
+	endpoint, err := data.nseManager.GetEndpoint(context.Background(), request, nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(nse3Name))
+}
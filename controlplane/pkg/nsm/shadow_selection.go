@@ -0,0 +1,51 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/metrics"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/selector"
+)
+
+// WithShadowSelector - run shadow selector alongside model selector on every selection to evaluate it before
+// promoting. Its choice is reported in SelectionResult.ShadowEndpoint and counted as agreed or diverged, but
+// routing always uses model selector.
+func WithShadowSelector(shadow selector.Selector) NseManagerOption {
+	return func(nsem *nseManager) {
+		nsem.shadowSelector = shadow
+	}
+}
+
+// shadowSelect - selects endpoint with shadow selector among candidates active selector chose from, returns its name.
+func (nsem *nseManager) shadowSelect(requestConnection *connection.Connection, ns *registry.NetworkService, candidates []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager, active *registry.NetworkServiceEndpoint) string {
+	if nsem.shadowSelector == nil {
+		return ""
+	}
+	shadow := nsem.selectCandidate(nsem.shadowSelector, requestConnection, ns, candidates, managers)
+	outcome := metrics.ShadowAgreed
+	if shadow != active {
+		outcome = metrics.ShadowDiverged
+		logrus.Infof("Shadow selector diverged for %s: active %v, shadow %v", ns.GetName(), active.GetName(), shadow.GetName())
+	}
+	nsem.shadowCounter.WithLabelValues(ns.GetName(), outcome).Inc()
+	return shadow.GetName()
+}
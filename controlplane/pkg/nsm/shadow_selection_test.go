@@ -0,0 +1,48 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/pkg/metrics"
+)
+
+func (data *nseManagerTestData) shadowCount(outcome string) float64 {
+	return testutil.ToFloat64(data.nseManager.shadowCounter.WithLabelValues(networkServiceName, outcome))
+}
+
+func TestShadowSelection(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	WithShadowSelector(&selectorStub{
+		scores: map[string]float64{nse1Name: 1, nse2Name: 2},
+	})(data.nseManager)
+	data.setDiscoveredEndpoints(
+		data.createEndpoint(nse1Name, remoteNSMName),
+		data.createEndpoint(nse2Name, remoteNSMName))
+	agreed, diverged := data.shadowCount(metrics.ShadowAgreed), data.shadowCount(metrics.ShadowDiverged)
+
+	for _, active := range []string{nse1Name, nse2Name} {
+		result := &SelectionResult{}
+		endpoint, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+		g.Expect(err).To(BeNil())
+		g.Expect(endpoint.GetNetworkServiceEndpoint().GetName()).To(Equal(active))
+		g.Expect(result.ShadowEndpoint).To(Equal(nse2Name))
+	}
+	g.Expect(data.shadowCount(metrics.ShadowAgreed) - agreed).To(Equal(1.0))
+	g.Expect(data.shadowCount(metrics.ShadowDiverged) - diverged).To(Equal(1.0))
+}
+
+func TestShadowSelection_Disabled(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData()
+	data.setDiscoveredEndpoints(data.createEndpoint(nse1Name, remoteNSMName))
+
+	result := &SelectionResult{}
+	_, err := data.nseManager.GetEndpoint(WithSelectionResult(context.Background(), result), newTestRequestConnection(), nil)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.ShadowEndpoint).To(BeEmpty())
+}
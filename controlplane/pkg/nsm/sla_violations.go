@@ -0,0 +1,110 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package nsm
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// slaViolations - recent SLA violations reported per network service and endpoint identity, zero value is ready
+// to use.
+type slaViolations struct {
+	sync.Mutex
+	reports map[string][]time.Time
+}
+
+// add - records violation, violations reported before decay are forgotten.
+func (v *slaViolations) add(key string, decay time.Duration) {
+	v.Lock()
+	defer v.Unlock()
+	if v.reports == nil {
+		v.reports = map[string][]time.Time{}
+	}
+	v.reports[key] = append(v.prune(key, decay), time.Now())
+}
+
+// count - returns count of violations reported within decay, older ones are forgotten.
+func (v *slaViolations) count(key string, decay time.Duration) int {
+	v.Lock()
+	defer v.Unlock()
+	return len(v.prune(key, decay))
+}
+
+// prune - forgets violations reported before decay and returns the rest, must be called under lock.
+func (v *slaViolations) prune(key string, decay time.Duration) []time.Time {
+	reports := v.reports[key]
+	deadline := time.Now().Add(-decay)
+	i := 0
+	for i < len(reports) && !reports[i].After(deadline) {
+		i++
+	}
+	if i == len(reports) {
+		delete(v.reports, key)
+		return nil
+	}
+	v.reports[key] = reports[i:]
+	return reports[i:]
+}
+
+func slaViolationKey(service, endpointKey string) string {
+	return service + "|" + endpointKey
+}
+
+// ReportSLAViolation - records that connection routed to endpoint violates its SLA. Endpoint with
+// properties.SLAViolationThreshold violations within properties.SLAViolationDecay is avoided by selections for
+// the same network service while there are other endpoints. Reports are dropped if SLA violation tracking is disabled.
+func (nsem *nseManager) ReportSLAViolation(endpointName, connectionID string) {
+	if nsem.props.SLAViolationThreshold <= 0 {
+		return
+	}
+	clientConnection := nsem.model.GetClientConnection(connectionID)
+	if clientConnection == nil {
+		logrus.Warnf("SLA violation of endpoint %s is reported for unknown connection %s", endpointName, connectionID)
+		return
+	}
+	endpoint := clientConnection.Endpoint
+	if endpoint.GetNetworkServiceEndpoint().GetName() != endpointName {
+		logrus.Warnf("SLA violation of endpoint %s is reported for connection %s routed to %s", endpointName, connectionID, endpoint.GetEndpointNSMName())
+		return
+	}
+	key := nsem.identity.Key(endpoint.GetNetworkServiceEndpoint(), endpoint.GetNetworkServiceManager())
+	nsem.slaViolations.add(slaViolationKey(endpoint.GetNetworkService().GetName(), key), nsem.props.SLAViolationDecay)
+}
+
+// filterSLAViolations - drops endpoints violating SLA too often, if all of them do they are kept.
+func (nsem *nseManager) filterSLAViolations(service string, endpoints []*registry.NetworkServiceEndpoint,
+	managers map[string]*registry.NetworkServiceManager) []*registry.NetworkServiceEndpoint {
+	if nsem.props.SLAViolationThreshold <= 0 {
+		return endpoints
+	}
+	result := []*registry.NetworkServiceEndpoint{}
+	for _, candidate := range endpoints {
+		key := nsem.identity.Key(candidate, managers[candidate.GetNetworkServiceManagerName()])
+		if nsem.slaViolations.count(slaViolationKey(service, key), nsem.props.SLAViolationDecay) < nsem.props.SLAViolationThreshold {
+			result = append(result, candidate)
+		}
+	}
+	if len(result) == 0 {
+		return endpoints
+	}
+	return result
+}
@@ -0,0 +1,84 @@
+package nsm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func withSLAViolations(data *nseManagerTestData) {
+	data.nseManager.props.SLAViolationThreshold = 2
+	data.nseManager.props.SLAViolationDecay = 100 * time.Millisecond
+}
+
+func (data *nseManagerTestData) selectedNames(count int) []string {
+	names := []string{}
+	for i := 0; i < count; i++ {
+		endpoint, err := data.nseManager.GetEndpoint(context.Background(), newTestRequestConnection(), nil)
+		if err != nil {
+			return append(names, err.Error())
+		}
+		names = append(names, endpoint.GetNetworkServiceEndpoint().GetName())
+	}
+	return names
+}
+
+func TestSLAViolations_AvoidedThenReEligible(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSLAViolations, withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection("1"))
+
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	g.Expect(data.selectedNames(2)).To(Equal([]string{nse2Name, nse2Name}))
+
+	<-time.After(200 * time.Millisecond)
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestSLAViolations_Ignored(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSLAViolations, withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection("1"))
+
+	for i := 0; i < 3; i++ {
+		data.nseManager.ReportSLAViolation(nse2Name, "1")
+		data.nseManager.ReportSLAViolation(nse1Name, "unknown")
+	}
+	g.Expect(data.selectedNames(2)).To(ConsistOf(nse1Name, nse2Name))
+}
+
+func TestSLAViolations_AllViolating(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSLAViolations, withEndpoints(remoteNSMName, nse1Name, nse2Name), withClientConnection("1"))
+	nse1 := data.endpoints[0]
+	data.setDiscoveredEndpoints(nse1)
+
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	g.Expect(data.selectedNames(1)).To(Equal([]string{nse1Name}))
+}
+
+func TestSLAViolations_NotRecordedByDefault(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withEndpoints(remoteNSMName, nse1Name), withClientConnection("1"))
+
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	g.Expect(data.nseManager.slaViolations.reports).To(BeEmpty())
+}
+
+func TestSLAViolations_DecayedForgottenOnReport(t *testing.T) {
+	g := NewWithT(t)
+	data := newNseManagerTestData(withSLAViolations, withEndpoints(remoteNSMName, nse1Name), withClientConnection("1"))
+
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	<-time.After(200 * time.Millisecond)
+	data.nseManager.ReportSLAViolation(nse1Name, "1")
+	for _, reports := range data.nseManager.slaViolations.reports {
+		g.Expect(reports).To(HaveLen(1))
+	}
+	g.Expect(data.nseManager.slaViolations.reports).To(HaveLen(1))
+}
@@ -42,12 +42,282 @@ type Properties struct {
 	HealRetryDelay                 time.Duration
 	HealRequestConnectCheckTimeout time.Duration
 	HealForwarderTimeout           time.Duration
+	// HealCheckJitter - fraction of HealRequestConnectCheckTimeout randomly added to it, to spread heal checks of
+	// NSMgrs restarted together, 0 disables jitter.
+	HealCheckJitter float64
+	// HealCheckConcurrency - how many endpoints CheckUpdateNSEBatch checks at once, bounds open connections.
+	HealCheckConcurrency int
+	// HealCheckBudget - overall time CheckUpdateNSEBatch has to check its endpoints, each check gets the budget left
+	// divided by rounds of concurrent checks left, no more than HealRequestConnectCheckTimeout and no less than
+	// HealCheckMinTimeout. 0 gives each check HealRequestConnectCheckTimeout.
+	HealCheckBudget     time.Duration
+	HealCheckMinTimeout time.Duration
+	// ReuseAssignedEndpoint - re-request of connection already routed to remote endpoint it targets keeps the
+	// endpoint without discovery, if NSE client to it could be created within HealRequestConnectCheckTimeout.
+	ReuseAssignedEndpoint bool
 
 	// Total DST heal timeout is 20 seconds.
 	HealDSTNSEWaitTimeout time.Duration
 	HealDSTNSEWaitTick    time.Duration
 
 	HealEnabled bool
+
+	// LatencyClasses - maximum endpoint RTT for each latency class a request can ask for with nsm/latency-class label.
+	LatencyClasses map[string]time.Duration
+	// LatencyClassRelaxed - select among all endpoints instead of failing when none meets requested latency class.
+	LatencyClassRelaxed bool
+
+	// SelectionSkewThreshold - ratio of the most selected endpoint selections to the average per endpoint within
+	// SelectionSkewWindow to report selection skew, 0 disables skew alerts.
+	SelectionSkewThreshold float64
+	SelectionSkewWindow    time.Duration
+	// SelectorValidationMaxSkew - selection skew above which selector validation reports an issue, 0 disables
+	// the skew check.
+	SelectorValidationMaxSkew float64
+
+	// DeterministicSelection - same request and endpoints always give the same endpoint: endpoints are ordered by
+	// identity, selector picks by connection id hash instead of its state or randomness and chaos delays are
+	// disabled. Selectors which could not guarantee it fail selection.
+	DeterministicSelection bool
+
+	// OrderedFallback - select the best scored endpoint instead of the one chosen by selector, so heal retries
+	// ignoring each returned endpoint walk candidates in descending score order. Only affects scoring selectors.
+	OrderedFallback bool
+
+	// CapabilityNegotiationAttempts - how many endpoints to try when negotiated capabilities do not satisfy request
+	// or data path probe fails.
+	CapabilityNegotiationAttempts int
+
+	// ConnectAttempts - how many endpoints ConnectToAnyEndpoint tries to connect to before giving up.
+	ConnectAttempts int
+
+	// Shares of request deadline given to endpoint selection phases, 0 means a phase is limited by request deadline only.
+	DiscoveryBudgetShare  float64
+	ValidationBudgetShare float64
+	SelectionBudgetShare  float64
+	// DialBudgetShare - share of request deadline ConnectToAnyEndpoint reserves for connecting to selected endpoint,
+	// selection phases share the rest. 0 gives all of it to selection.
+	DialBudgetShare float64
+
+	// DiscoveryRetryCount - how many times discovery failed with transient error is retried, DiscoveryRetryDelay -
+	// delay before the first retry, doubled for each next one. 0 disables retries.
+	DiscoveryRetryCount int
+	DiscoveryRetryDelay time.Duration
+	// DiscoveryRequestTimeout - timeout of a single discovery request to registry independent of request deadline,
+	// 0 means discovery request is limited by request deadline only.
+	DiscoveryRequestTimeout time.Duration
+
+	// PrecheckEndpoints - check selected endpoint is reachable before returning it from GetEndpoint and reselect
+	// ignoring unreachable one, at most DiscoveryRetryCount times. Fails fast instead of on connect, at the cost of
+	// a connection attempt per selection.
+	PrecheckEndpoints bool
+
+	// DiscoveryCacheTTL - how long discovered endpoints of network service are reused without asking registry,
+	// 0 disables caching. Cache of network service is dropped when connecting to its endpoint fails.
+	DiscoveryCacheTTL time.Duration
+
+	// DeduplicateDiscovery - concurrent GetEndpoint calls for the same network service ignoring the same endpoints
+	// share one discovery lookup, each still selecting on its own.
+	DeduplicateDiscovery bool
+
+	// CaseInsensitiveNetworkServices - lowercase network service names of requests before discovery and match them
+	// to names of local endpoints case-insensitively. Registry matches names exactly, so network services should be
+	// registered with lowercase names. Names are trimmed regardless.
+	CaseInsensitiveNetworkServices bool
+
+	// DiscoveryFailOpen - when discovery fails, select among endpoints of network service registered at local NSM
+	// instead of failing. Remote endpoints and endpoints registered since registry went down are not known.
+	DiscoveryFailOpen bool
+
+	// DiscoveryTimeouts - discovery timeout for network services backed by slower or faster registries, overrides
+	// discovery share of request deadline for services listed.
+	DiscoveryTimeouts map[string]time.Duration
+
+	// SelectionScoresSampleEvery - export scores of every Nth selection, 0 exports only requests labeled with
+	// nsm/debug-selection=true. SelectionScoresTopK - how many best scored candidates to export.
+	// SelectionScoresHeapThreshold - how many candidates make best scored ones found with a bounded heap instead of
+	// sorting all of them.
+	SelectionScoresSampleEvery   int
+	SelectionScoresTopK          int
+	SelectionScoresHeapThreshold int
+
+	// FallbackNetworkService - network service, e.g. a sink one, endpoint is selected from when requested network
+	// service has no selectable endpoints. Discovery errors are not masked by fallback. Empty disables fallback.
+	FallbackNetworkService string
+
+	// SelectionRejectionReport - record why each discovered endpoint was filtered out, log it to the span and attach it
+	// to endpoint not found errors. Debug only, report is allocated for every selection.
+	SelectionRejectionReport bool
+
+	// MaxConcurrentSelections - how many GetEndpoint calls for the same network service may be in flight at once,
+	// excess calls wait until their context is done. 0 is unlimited.
+	MaxConcurrentSelections int
+
+	// SelectionPolicies - selection policy declared by network service, e.g. round-robin, random or first-match,
+	// keyed by network service name. Endpoints of network services not listed are selected with model selector.
+	SelectionPolicies map[string]string
+
+	// ManagerFairSelection - balance selections across NSMgrs hosting endpoints before selecting endpoint of chosen
+	// NSMgr with model selector, for network services not declaring selection policy.
+	ManagerFairSelection bool
+
+	// TopologyAwareSelection - select with model selector only among endpoints of NSMgrs nearest to local NSMgr by
+	// ManagerLocations: same zone, then same region, then other regions, for network services not declaring selection
+	// policy. Takes precedence over ManagerFairSelection.
+	TopologyAwareSelection bool
+	// ManagerLocations - location of NSMgrs, local one included, keyed by NSMgr name, as region or region/zone, e.g.
+	// eu-west/eu-west-1a.
+	ManagerLocations map[string]string
+
+	// ExportedEndpointLabels - allow-list of endpoint labels returned with selection as metadata, e.g. backend id.
+	// Labels not listed are never exported.
+	ExportedEndpointLabels []string
+
+	// SelectionTokenTTL - validity window of selection tokens, 0 disables issuing them.
+	SelectionTokenTTL time.Duration
+
+	// QuorumCheckConcurrency - how many endpoints are checked for reachability at once when network service
+	// requires a quorum of reachable endpoints.
+	QuorumCheckConcurrency int
+
+	// SelectionHistorySize - how many last endpoint selections to keep per connection, 0 disables history.
+	// SelectionHistoryMaxConnections - of how many connections history could be kept at once, history of connection
+	// selected for least recently is dropped to keep another one, 0 means no limit.
+	SelectionHistorySize           int
+	SelectionHistoryMaxConnections int
+
+	// PreferLocalEndpoints - select among endpoints hosted by local NSM, remote ones are selected only if no local
+	// endpoint is left after filtering.
+	PreferLocalEndpoints bool
+
+	// MatchEndpointLabels - select only endpoints whose labels match labels of request connection, a label both of
+	// them have must have the same value.
+	MatchEndpointLabels bool
+
+	// SelectUpgradingEndpoints - select among upgrading endpoints when all endpoints of network service are
+	// upgrading instead of failing.
+	SelectUpgradingEndpoints bool
+
+	// MaxSelectionCandidates - how many of filtered endpoints are evaluated by selector, in discovery order,
+	// 0 evaluates all. Requests could override it with nsm/max-candidates label.
+	MaxSelectionCandidates int
+
+	// BlackholeQuarantine - how long endpoint failed data path probe is not selected.
+	BlackholeQuarantine time.Duration
+
+	// RetryBudget - how many retries of GetEndpoint, requests with ignored endpoints, are allowed per network
+	// service in a burst, 0 disables the budget. Budget is refilled by one retry every RetryBudgetRefill.
+	RetryBudget       int
+	RetryBudgetRefill time.Duration
+
+	// MaxIgnoredEndpoints - how many ignored endpoints GetEndpoint accepts before giving up healing with
+	// ErrIgnoresExhausted, 0 means no limit.
+	MaxIgnoredEndpoints int
+
+	// SLAViolationThreshold - how many SLA violations reported within SLAViolationDecay make endpoint avoided by
+	// selection, 0 disables avoiding endpoints.
+	SLAViolationThreshold int
+	SLAViolationDecay     time.Duration
+
+	// FailureRateThreshold - share of NSE client creations failed within FailureRateWindow, e.g. 0.5, above which
+	// endpoint is not selected, unless all endpoints fail that often, 0 disables tracking. Endpoints with fewer than
+	// FailureRateMinSamples outcomes within the window are not judged.
+	FailureRateThreshold  float64
+	FailureRateWindow     time.Duration
+	FailureRateMinSamples int
+
+	// EndpointMinAge - how long after it was registered endpoint is not selected, unless all endpoints are too young,
+	// 0 disables the check. Registration time is taken from nsm/registered-at endpoint label.
+	EndpointMinAge time.Duration
+
+	// SelectionCooldown - how long endpoint is not selected for new connections after it was selected, unless all
+	// endpoints are cooling down, 0 disables cooldown. Endpoints could override it with nsm/cooldown label.
+	SelectionCooldown time.Duration
+
+	// ReservationTTL - how long a slot reserved on selected endpoint counts as its connection until NSE client to it
+	// is created, 0 disables reservations.
+	ReservationTTL time.Duration
+
+	// LocalEndpointQuarantine - how long local endpoint connection failed to is not selected instead of being removed
+	// from model, 0 removes it right away. Endpoint is removed once it fails LocalEndpointFailureThreshold times in
+	// a row.
+	LocalEndpointQuarantine       time.Duration
+	LocalEndpointFailureThreshold int
+
+	// UnreachableQuarantine - how long endpoint found unreachable by RefreshServiceHealth is not selected, unless
+	// a later refresh finds it reachable.
+	UnreachableQuarantine time.Duration
+
+	// EndpointBlacklistCooldown - how long remote endpoint client could not be created for is not selected,
+	// 0 disables blacklisting.
+	EndpointBlacklistCooldown time.Duration
+
+	// ManagerBreakerThreshold - how many consecutive failed dials of remote network service manager make
+	// CreateNSEClient fail right away for endpoints of it during ManagerBreakerCooldown, 0 disables the breaker.
+	// Once cooldown passes a single dial is let through to probe the manager.
+	ManagerBreakerThreshold int
+	ManagerBreakerCooldown  time.Duration
+
+	// RemoteClientIdleTimeout - how long connection to remote network service manager is kept for reuse after
+	// its last client was cleaned up, 0 disables pooling and each client dials connection of its own.
+	RemoteClientIdleTimeout time.Duration
+
+	// RemoteKeepaliveTime - how long connection to remote network service manager may be idle before it is pinged
+	// with gRPC keepalive, RemoteKeepaliveTimeout - how long to wait for ping ack before connection is closed, 0 is
+	// gRPC default, RemoteKeepalivePermitWithoutStream - ping connections without active streams too. 0 time disables keepalive.
+	// gRPC does not ping more often than every 10 seconds, and remote NSMgr must permit pings that frequent.
+	RemoteKeepaliveTime                time.Duration
+	RemoteKeepaliveTimeout             time.Duration
+	RemoteKeepalivePermitWithoutStream bool
+
+	// PreemptRemoteDials - cancel dial of remote endpoint by CreateNSEClient when ready local endpoint of the same
+	// network service is added to model, CreateNSEClient fails with nsm.ErrRemoteDialPreempted then.
+	PreemptRemoteDials bool
+
+	// EvictionDelay - delay between evictions of endpoints evicted together, e.g. endpoints of dead NSM, so their
+	// connections are re-homed gradually, 0 evicts all at once.
+	EvictionDelay time.Duration
+
+	// LocalEndpointCacheTTL - how long local endpoints looked up in model by name are cached for, 0 disables caching.
+	LocalEndpointCacheTTL time.Duration
+
+	// SessionAffinity - route connection to the endpoint it was last routed to while that endpoint is discovered
+	// and not ignored, instead of selecting again on heal and re-request.
+	SessionAffinity bool
+
+	// FairnessShare - fraction of new connections of network service routed to its endpoints which were candidates
+	// but were never selected within the last FairnessInterval, e.g. because of affinity. Connections bound to
+	// endpoint with SessionAffinity are never rerouted. 0 disables the fairness guard.
+	FairnessShare    float64
+	FairnessInterval time.Duration
+
+	// DataLocalityTTL - how long endpoint stays bound to data locality hint after it was last used, 0 disables binding.
+	// DataLocalityMaxBindings - how many hints could be bound at once, binding expiring first is dropped to bind
+	// another one, 0 means no limit.
+	DataLocalityTTL         time.Duration
+	DataLocalityMaxBindings int
+
+	// ChaosEnabled - inject failures and delays into endpoint selection for resilience testing, never enable
+	// in production. Rates are probabilities from 0 to 1 of failing discovery, failing NSE client creation
+	// and delaying selection by ChaosSelectionDelay.
+	ChaosEnabled              bool
+	ChaosDiscoveryFailureRate float64
+	ChaosClientFailureRate    float64
+	ChaosSelectionDelayRate   float64
+	ChaosSelectionDelay       time.Duration
+
+	// ApprovalTimeout - how long to wait for approval of selected endpoint, not approved in time are denied.
+	ApprovalTimeout time.Duration
+	// ApprovalDenialCacheTTL - how long denied endpoint is skipped for the same client without asking approval
+	// gate again, zero disables caching of denials.
+	ApprovalDenialCacheTTL time.Duration
+
+	// AllowUninitializedNSM - select endpoints treating all of them as remote while local NSM is not initialized,
+	// instead of failing with ErrNSMNotInitialized.
+	AllowUninitializedNSM bool
+
+	// SelectionLatencyReservoirSize - how many recent GetEndpoint durations are kept for latency percentiles.
+	SelectionLatencyReservoirSize int
 }
 
 // NewNsmProperties creates NsmProperties with defined default values and reading values from environment variables
@@ -59,6 +329,8 @@ func NewNsmProperties() *Properties {
 		HealRequestConnectTimeout:      time.Second * 15,
 		HealRequestConnectCheckTimeout: time.Second * 1,
 		HealForwarderTimeout:           time.Minute * 1,
+		HealCheckConcurrency:           8,
+		HealCheckMinTimeout:            time.Millisecond * 100,
 		HealRetryCount:                 10,
 		HealRetryDelay:                 time.Second * 5,
 
@@ -66,6 +338,29 @@ func NewNsmProperties() *Properties {
 		HealDSTNSEWaitTimeout: time.Second * 30,       // Maximum time to wait for NSMD/NSE to re-appear
 		HealDSTNSEWaitTick:    500 * time.Millisecond, // Wait timeout to appear of NSE
 		HealEnabled:           true,
+
+		SelectionSkewWindow:            time.Minute * 5,
+		FairnessInterval:               time.Minute,
+		SelectorValidationMaxSkew:      2,
+		CapabilityNegotiationAttempts:  3,
+		ConnectAttempts:                3,
+		DiscoveryRetryDelay:            time.Millisecond * 100,
+		SelectionScoresTopK:            5,
+		SelectionScoresHeapThreshold:   256,
+		QuorumCheckConcurrency:         8,
+		SelectionHistoryMaxConnections: 4096,
+		BlackholeQuarantine:            time.Minute * 1,
+		DataLocalityMaxBindings:        4096,
+		ManagerBreakerCooldown:         time.Second * 30,
+		RetryBudgetRefill:              time.Second * 1,
+		SLAViolationDecay:              time.Minute * 1,
+		FailureRateWindow:              time.Minute * 1,
+		FailureRateMinSamples:          5,
+		UnreachableQuarantine:          time.Second * 30,
+		LocalEndpointFailureThreshold:  3,
+		ApprovalTimeout:                time.Second * 5,
+		ApprovalDenialCacheTTL:         time.Second * 10,
+		SelectionLatencyReservoirSize:  1024,
 	}
 
 	// Parse few Environment variables.
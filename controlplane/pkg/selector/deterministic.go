@@ -0,0 +1,52 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"hash/fnv"
+	"math"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// DeterministicSelector - a selector choosing endpoint only by its inputs, without any state or randomness, so the
+// same request and endpoints always give the same endpoint.
+type DeterministicSelector interface {
+	SelectDeterministic(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint
+}
+
+// connectionHash - maps connection id to [0, 1).
+func connectionHash(requestConnection *connection.Connection) float64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(requestConnection.GetId()))
+	// fnv high bits barely change for short ids differing in the last bytes, mix them as splitmix64 does
+	x := h.Sum64()
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11) / float64(uint64(1)<<53)
+}
+
+// pickHashed - picks endpoint by connection id hash, nil if there are no endpoints.
+func pickHashed(requestConnection *connection.Connection, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	if len(networkServiceEndpoints) == 0 {
+		return nil
+	}
+	idx := int(math.Floor(connectionHash(requestConnection) * float64(len(networkServiceEndpoints))))
+	return networkServiceEndpoints[idx]
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"math"
+	"sort"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// CandidateSelector - a selector choosing from endpoints with their runtime state.
+type CandidateSelector interface {
+	SelectCandidate(requestConnection *connection.Connection, ns *registry.NetworkService, candidates []*Candidate) *Candidate
+}
+
+// Criterion - named criterion of LexicographicSelector, candidates with lower value are preferred.
+type Criterion struct {
+	Name  string
+	Value func(requestConnection *connection.Connection, candidate *Candidate) float64
+}
+
+// LabelMatchCriterion - prefers endpoints having label with the same value as request, e.g. same zone.
+func LabelMatchCriterion(label string) Criterion {
+	return Criterion{
+		Name: "label-match:" + label,
+		Value: func(requestConnection *connection.Connection, candidate *Candidate) float64 {
+			value, ok := requestConnection.GetLabels()[label]
+			if ok && candidate.Endpoint.GetLabels()[label] == value {
+				return 0
+			}
+			return 1
+		},
+	}
+}
+
+// LeastConnectionsCriterion - prefers endpoints with less client connections.
+func LeastConnectionsCriterion() Criterion {
+	return Criterion{
+		Name: "least-connections",
+		Value: func(requestConnection *connection.Connection, candidate *Candidate) float64 {
+			return float64(candidate.Connections)
+		},
+	}
+}
+
+// LowestRTTCriterion - prefers endpoints with lower RTT, endpoints without measured RTT are the least preferred.
+func LowestRTTCriterion() Criterion {
+	return Criterion{
+		Name: "lowest-rtt",
+		Value: func(requestConnection *connection.Connection, candidate *Candidate) float64 {
+			if !candidate.RTTMeasured {
+				return math.Inf(1)
+			}
+			return float64(candidate.RTT)
+		},
+	}
+}
+
+// LeastLoadedCriterion - prefers endpoints with lower CPU utilization, endpoints without known load are the least
+// preferred.
+func LeastLoadedCriterion() Criterion {
+	return Criterion{
+		Name: "least-loaded",
+		Value: func(requestConnection *connection.Connection, candidate *Candidate) float64 {
+			if candidate.Load == nil {
+				return math.Inf(1)
+			}
+			return candidate.Load.CPUUtilization
+		},
+	}
+}
+
+// ShortestQueueCriterion - prefers endpoints with less queued requests, endpoints without known load are the least
+// preferred.
+func ShortestQueueCriterion() Criterion {
+	return Criterion{
+		Name: "shortest-queue",
+		Value: func(requestConnection *connection.Connection, candidate *Candidate) float64 {
+			if candidate.Load == nil {
+				return math.Inf(1)
+			}
+			return float64(candidate.Load.QueueDepth)
+		},
+	}
+}
+
+// LexicographicSelector - orders candidates by criteria in strict priority, later criteria only break ties of
+// earlier ones. Candidates tied on all criteria keep their order.
+type LexicographicSelector struct {
+	criteria []Criterion
+}
+
+// NewLexicographicSelector - creates LexicographicSelector with criteria ordered from the most important.
+func NewLexicographicSelector(criteria ...Criterion) *LexicographicSelector {
+	return &LexicographicSelector{
+		criteria: criteria,
+	}
+}
+
+// SelectEndpoint - selects endpoint without runtime state, only criteria based on endpoint itself are effective.
+func (s *LexicographicSelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	candidates := make([]*Candidate, 0, len(networkServiceEndpoints))
+	for _, endpoint := range networkServiceEndpoints {
+		candidates = append(candidates, &Candidate{Endpoint: endpoint})
+	}
+	if candidate := s.SelectCandidate(requestConnection, ns, candidates); candidate != nil {
+		return candidate.Endpoint
+	}
+	return nil
+}
+
+// SelectDeterministic - same as SelectEndpoint, lexicographic order depends only on its inputs.
+func (s *LexicographicSelector) SelectDeterministic(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return s.SelectEndpoint(requestConnection, ns, networkServiceEndpoints)
+}
+
+// SelectCandidate - selects the first of candidates in lexicographic order.
+func (s *LexicographicSelector) SelectCandidate(requestConnection *connection.Connection, ns *registry.NetworkService, candidates []*Candidate) *Candidate {
+	if len(candidates) == 0 {
+		return nil
+	}
+	values := s.values(requestConnection, candidates)
+	best := 0
+	for i := 1; i < len(candidates); i++ {
+		if compareValues(values[i], values[best]) < 0 {
+			best = i
+		}
+	}
+	return candidates[best]
+}
+
+// ScoreCandidates - scores candidates by their rank in lexicographic order: the first scores 1, the second 1/2 and
+// so on, tied candidates share the rank.
+func (s *LexicographicSelector) ScoreCandidates(requestConnection *connection.Connection, ns *registry.NetworkService, candidates []*Candidate) []float64 {
+	values := s.values(requestConnection, candidates)
+	order := make([]int, len(candidates))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return compareValues(values[order[i]], values[order[j]]) < 0
+	})
+
+	scores := make([]float64, len(candidates))
+	rank := 0
+	for i, idx := range order {
+		if i > 0 && compareValues(values[order[i-1]], values[idx]) != 0 {
+			rank++
+		}
+		scores[idx] = 1 / float64(rank+1)
+	}
+	return scores
+}
+
+func (s *LexicographicSelector) values(requestConnection *connection.Connection, candidates []*Candidate) [][]float64 {
+	values := make([][]float64, len(candidates))
+	for i, candidate := range candidates {
+		values[i] = make([]float64, len(s.criteria))
+		for j, criterion := range s.criteria {
+			values[i][j] = criterion.Value(requestConnection, candidate)
+		}
+	}
+	return values
+}
+
+func compareValues(a, b []float64) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Cisco Systems, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func newZoneCandidate(name, zone string, connections int, rtt time.Duration) *Candidate {
+	return &Candidate{
+		Endpoint: &registry.NetworkServiceEndpoint{
+			Name:   name,
+			Labels: map[string]string{"zone": zone},
+		},
+		Connections: connections,
+		RTT:         rtt,
+		RTTMeasured: rtt > 0,
+	}
+}
+
+func TestLexicographicSelector_SelectCandidate(t *testing.T) {
+	request := &connection.Connection{
+		Labels: map[string]string{"zone": "a"},
+	}
+	lexicographic := NewLexicographicSelector(LabelMatchCriterion("zone"), LeastConnectionsCriterion(), LowestRTTCriterion())
+	tests := []struct {
+		name       string
+		candidates []*Candidate
+		want       string
+	}{
+		{
+			name: "zone match wins over connections and rtt",
+			candidates: []*Candidate{
+				newZoneCandidate("nse-1", "b", 0, time.Millisecond),
+				newZoneCandidate("nse-2", "a", 10, time.Second),
+			},
+			want: "nse-2",
+		},
+		{
+			name: "connections break zone tie",
+			candidates: []*Candidate{
+				newZoneCandidate("nse-1", "a", 5, time.Millisecond),
+				newZoneCandidate("nse-2", "a", 1, time.Second),
+			},
+			want: "nse-2",
+		},
+		{
+			name: "rtt breaks zone and connections tie",
+			candidates: []*Candidate{
+				newZoneCandidate("nse-1", "a", 1, time.Second),
+				newZoneCandidate("nse-2", "a", 1, time.Millisecond),
+				newZoneCandidate("nse-3", "a", 1, 0),
+			},
+			want: "nse-2",
+		},
+		{
+			name: "full tie keeps order",
+			candidates: []*Candidate{
+				newZoneCandidate("nse-1", "b", 1, 0),
+				newZoneCandidate("nse-2", "b", 1, 0),
+			},
+			want: "nse-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lexicographic.SelectCandidate(request, nil, tt.candidates); got.Endpoint.GetName() != tt.want {
+				t.Errorf("LexicographicSelector.SelectCandidate() = %v, want %v", got.Endpoint.GetName(), tt.want)
+			}
+		})
+	}
+}
+
+func TestLexicographicSelector_ScoreCandidates(t *testing.T) {
+	lexicographic := NewLexicographicSelector(LeastConnectionsCriterion(), LowestRTTCriterion())
+	candidates := []*Candidate{
+		newZoneCandidate("nse-1", "a", 2, time.Millisecond),
+		newZoneCandidate("nse-2", "a", 1, time.Second),
+		newZoneCandidate("nse-3", "a", 2, time.Millisecond),
+		newZoneCandidate("nse-4", "a", 1, time.Millisecond),
+	}
+	want := []float64{1.0 / 3, 1.0 / 2, 1.0 / 3, 1}
+	if got := lexicographic.ScoreCandidates(&connection.Connection{}, nil, candidates); !reflect.DeepEqual(got, want) {
+		t.Errorf("LexicographicSelector.ScoreCandidates() = %v, want %v", got, want)
+	}
+}
+
+func TestLexicographicSelector_SelectEndpoint(t *testing.T) {
+	request := &connection.Connection{
+		Labels: map[string]string{"zone": "a"},
+	}
+	lexicographic := NewLexicographicSelector(LabelMatchCriterion("zone"))
+	endpoints := []*registry.NetworkServiceEndpoint{
+		{Name: "nse-1", Labels: map[string]string{"zone": "b"}},
+		{Name: "nse-2", Labels: map[string]string{"zone": "a"}},
+	}
+	if got := lexicographic.SelectEndpoint(request, nil, endpoints); got != endpoints[1] {
+		t.Errorf("LexicographicSelector.SelectEndpoint() = %v, want %v", got, endpoints[1])
+	}
+	if got := lexicographic.SelectEndpoint(request, nil, nil); got != nil {
+		t.Errorf("LexicographicSelector.SelectEndpoint() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+type managerFairSelector struct {
+	sync.Mutex
+	endpointSelector Selector
+	next             map[string]int
+}
+
+// NewManagerFairSelector - creates selector balancing selections of network service across NSMgrs hosting its
+// endpoints in round robin, so one NSMgr hosting many endpoints is not selected more often than others. Endpoint of
+// chosen NSMgr is selected by endpointSelector, given network service named "<network service>/<NSMgr>" so state it
+// keeps by network service, e.g. round robin position, is kept for each NSMgr.
+func NewManagerFairSelector(endpointSelector Selector) Selector {
+	return &managerFairSelector{
+		endpointSelector: endpointSelector,
+		next:             map[string]int{},
+	}
+}
+
+func (s *managerFairSelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	if len(networkServiceEndpoints) == 0 {
+		return nil
+	}
+	byManager := map[string][]*registry.NetworkServiceEndpoint{}
+	for _, endpoint := range networkServiceEndpoints {
+		name := endpoint.GetNetworkServiceManagerName()
+		byManager[name] = append(byManager[name], endpoint)
+	}
+	managers := make([]string, 0, len(byManager))
+	for name := range byManager {
+		managers = append(managers, name)
+	}
+	sort.Strings(managers)
+
+	s.Lock()
+	manager := managers[s.next[ns.GetName()]%len(managers)]
+	s.next[ns.GetName()]++
+	s.Unlock()
+	managerNs := &registry.NetworkService{
+		Name:    ns.GetName() + "/" + manager,
+		Payload: ns.GetPayload(),
+		Matches: ns.GetMatches(),
+	}
+	return s.endpointSelector.SelectEndpoint(requestConnection, managerNs, byManager[manager])
+}
@@ -0,0 +1,45 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"testing"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestManagerFairSelector(t *testing.T) {
+	endpoints := []*registry.NetworkServiceEndpoint{
+		{Name: "nse-1", NetworkServiceManagerName: "nsm-1"},
+		{Name: "nse-2", NetworkServiceManagerName: "nsm-1"},
+		{Name: "nse-3", NetworkServiceManagerName: "nsm-1"},
+		{Name: "nse-4", NetworkServiceManagerName: "nsm-2"},
+	}
+	selected := selectNames(NewManagerFairSelector(NewRoundRobinSelector()), endpoints, 6)
+	want := map[string]int{"nse-1": 1, "nse-2": 1, "nse-3": 1, "nse-4": 3}
+	if len(selected) != len(want) {
+		t.Fatalf("unexpected selections %v", selected)
+	}
+	for name, count := range want {
+		if selected[name] != count {
+			t.Errorf("unexpected selections %v", selected)
+		}
+	}
+	if NewManagerFairSelector(NewRoundRobinSelector()).SelectEndpoint(nil, nil, nil) != nil {
+		t.Errorf("selected endpoint from none")
+	}
+}
@@ -31,6 +31,8 @@ type matchSelector struct {
 	roundRobin Selector
 }
 
+type pickFunc func(ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint
+
 // NewMatchSelector creates a new
 func NewMatchSelector() Selector {
 	return &matchSelector{
@@ -54,7 +56,7 @@ func isSubset(a, b, nsLabels map[string]string) bool {
 	return true
 }
 
-func (m *matchSelector) matchEndpoint(nsLabels map[string]string, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+func (m *matchSelector) matchEndpoint(nsLabels map[string]string, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint, pick pickFunc) *registry.NetworkServiceEndpoint {
 	logrus.Infof("Matching endpoint for labels %v", nsLabels)
 
 	matchedNonEmptySelector := false
@@ -87,7 +89,7 @@ func (m *matchSelector) matchEndpoint(nsLabels map[string]string, ns *registry.N
 
 		if len(nseCandidates) > 0 {
 			// We found candidates. Use RoundRobin to select one
-			return m.roundRobin.SelectEndpoint(nil, ns, nseCandidates)
+			return pick(ns, nseCandidates)
 		}
 	}
 	return nil
@@ -95,11 +97,35 @@ func (m *matchSelector) matchEndpoint(nsLabels map[string]string, ns *registry.N
 
 func (m *matchSelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
 	logrus.Infof("Selecting endpoint for %s with %d matches.", requestConnection.GetNetworkService(), len(ns.GetMatches()))
+	return m.selectWith(requestConnection, ns, networkServiceEndpoints, m.selectRoundRobin)
+}
+
+// PeekEndpoint - returns endpoint SelectEndpoint would return next without advancing round robin position.
+func (m *matchSelector) PeekEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return m.selectWith(requestConnection, ns, networkServiceEndpoints, m.peekRoundRobin)
+}
+
+// SelectDeterministic - matches endpoints as SelectEndpoint does, picks among matched ones by connection id hash.
+func (m *matchSelector) SelectDeterministic(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return m.selectWith(requestConnection, ns, networkServiceEndpoints, func(ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+		return pickHashed(requestConnection, networkServiceEndpoints)
+	})
+}
+
+func (m *matchSelector) selectWith(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint, pick pickFunc) *registry.NetworkServiceEndpoint {
 	if len(ns.GetMatches()) == 0 {
-		return m.roundRobin.SelectEndpoint(nil, ns, networkServiceEndpoints)
+		return pick(ns, networkServiceEndpoints)
 	}
 
-	return m.matchEndpoint(requestConnection.GetLabels(), ns, networkServiceEndpoints)
+	return m.matchEndpoint(requestConnection.GetLabels(), ns, networkServiceEndpoints, pick)
+}
+
+func (m *matchSelector) selectRoundRobin(ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return m.roundRobin.SelectEndpoint(nil, ns, networkServiceEndpoints)
+}
+
+func (m *matchSelector) peekRoundRobin(ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return m.roundRobin.(Peeker).PeekEndpoint(nil, ns, networkServiceEndpoints)
 }
 
 // ProcessLabels generates matches based on destination label selectors that specify templating.
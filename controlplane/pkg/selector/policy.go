@@ -0,0 +1,112 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// Names of selection policies known to PolicyRegistry created with NewPolicyRegistry.
+const (
+	PolicyRoundRobin = "round-robin"
+	PolicyRandom     = "random"
+	PolicyFirstMatch = "first-match"
+	PolicyWeighted   = "weighted"
+	// PolicyManagerFair - round robin across NSMgrs hosting endpoints, then across endpoints of chosen NSMgr.
+	PolicyManagerFair = "manager-fair"
+	// PolicyPriority - round robin across endpoints of the highest priority present.
+	PolicyPriority = "priority"
+)
+
+// PolicyRegistry - selectors of named selection policies, network services declaring a policy are selected for with
+// its selector. Safe for concurrent use.
+type PolicyRegistry struct {
+	sync.RWMutex
+	selectors map[string]Selector
+}
+
+// NewPolicyRegistry - creates registry of round-robin, random, first-match, weighted, manager-fair and priority
+// policies.
+func NewPolicyRegistry() *PolicyRegistry {
+	return NewPolicyRegistryWithSource(newTimeSeededSource())
+}
+
+// NewPolicyRegistryWithSource - NewPolicyRegistry with random policy drawing from source, e.g. a fixed seed one to
+// make selections reproducible.
+func NewPolicyRegistryWithSource(source rand.Source) *PolicyRegistry {
+	return &PolicyRegistry{
+		selectors: map[string]Selector{
+			PolicyRoundRobin:  NewRoundRobinSelector(),
+			PolicyRandom:      NewRandomSelectorWithSource(source),
+			PolicyFirstMatch:  NewMatchSelector(),
+			PolicyWeighted:    NewWeightedSelector(),
+			PolicyManagerFair: NewManagerFairSelector(NewRoundRobinSelector()),
+			PolicyPriority:    NewPrioritySelector(NewRoundRobinSelector()),
+		},
+	}
+}
+
+// Register - selects with selector for network services declaring policy, replaces selector registered before.
+func (r *PolicyRegistry) Register(policy string, selector Selector) {
+	r.Lock()
+	defer r.Unlock()
+	r.selectors[policy] = selector
+}
+
+// Selector - returns selector of policy, false if policy is not registered.
+func (r *PolicyRegistry) Selector(policy string) (Selector, bool) {
+	r.RLock()
+	defer r.RUnlock()
+	selector, ok := r.selectors[policy]
+	return selector, ok
+}
+
+type randomSelector struct {
+	sync.Mutex
+	random *rand.Rand
+}
+
+// NewRandomSelector - creates selector choosing endpoint uniformly at random.
+func NewRandomSelector() Selector {
+	return NewRandomSelectorWithSource(newTimeSeededSource())
+}
+
+// NewRandomSelectorWithSource - creates selector choosing endpoint uniformly at random drawn from source, selector
+// serializes access to it.
+func NewRandomSelectorWithSource(source rand.Source) Selector {
+	return &randomSelector{
+		random: rand.New(source), // #nosec G404 - selection does not need secure random
+	}
+}
+
+func newTimeSeededSource() rand.Source {
+	return rand.NewSource(time.Now().UnixNano()) // #nosec G404 - selection does not need secure random
+}
+
+func (s *randomSelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	if len(networkServiceEndpoints) == 0 {
+		return nil
+	}
+	s.Lock()
+	defer s.Unlock()
+	return networkServiceEndpoints[s.random.Intn(len(networkServiceEndpoints))]
+}
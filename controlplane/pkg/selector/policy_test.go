@@ -0,0 +1,70 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func TestPolicyRegistry(t *testing.T) {
+	policies := NewPolicyRegistry()
+	for _, policy := range []string{PolicyRoundRobin, PolicyRandom, PolicyFirstMatch, PolicyWeighted, PolicyManagerFair} {
+		if _, ok := policies.Selector(policy); !ok {
+			t.Errorf("policy %s is not registered", policy)
+		}
+	}
+	if _, ok := policies.Selector("unknown"); ok {
+		t.Errorf("unknown policy is registered")
+	}
+
+	random := NewRandomSelector()
+	policies.Register(PolicyRoundRobin, random)
+	if s, _ := policies.Selector(PolicyRoundRobin); s != random {
+		t.Errorf("policy is not replaced")
+	}
+}
+
+func TestRandomSelector(t *testing.T) {
+	endpoints := []*registry.NetworkServiceEndpoint{
+		newWeightedEndpoint("nse-1", nil),
+		newWeightedEndpoint("nse-2", nil),
+	}
+	selected := selectNames(NewRandomSelector(), endpoints, 100)
+	if selected["nse-1"] == 0 || selected["nse-2"] == 0 || len(selected) != 2 {
+		t.Errorf("unexpected selections %v", selected)
+	}
+	if NewRandomSelector().SelectEndpoint(nil, nil, nil) != nil {
+		t.Errorf("selected endpoint from none")
+	}
+}
+
+func TestRandomSelector_WithSource(t *testing.T) {
+	endpoints := []*registry.NetworkServiceEndpoint{
+		newWeightedEndpoint("nse-1", nil),
+		newWeightedEndpoint("nse-2", nil),
+		newWeightedEndpoint("nse-3", nil),
+	}
+	first, second := NewRandomSelectorWithSource(rand.NewSource(42)), NewRandomSelectorWithSource(rand.NewSource(42))
+	for i := 0; i < 20; i++ {
+		if a, b := first.SelectEndpoint(nil, nil, endpoints), second.SelectEndpoint(nil, nil, endpoints); a != b {
+			t.Fatalf("selection %d differs for the same seed: %s and %s", i, a.GetName(), b.GetName())
+		}
+	}
+}
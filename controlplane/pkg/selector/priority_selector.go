@@ -0,0 +1,65 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"strconv"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// PriorityLabel - endpoint label with integer priority of endpoint, higher priority endpoints are preferred.
+const PriorityLabel = "priority"
+
+type prioritySelector struct {
+	endpointSelector Selector
+}
+
+// NewPrioritySelector - creates selector selecting only among endpoints of the highest priority present, by
+// endpointSelector. Endpoints without valid priority label have lower priority than any labeled endpoint.
+func NewPrioritySelector(endpointSelector Selector) Selector {
+	return &prioritySelector{
+		endpointSelector: endpointSelector,
+	}
+}
+
+// endpointPriority - returns priority of endpoint and whether it has valid priority label.
+func endpointPriority(endpoint *registry.NetworkServiceEndpoint) (int64, bool) {
+	priority, err := strconv.ParseInt(endpoint.GetLabels()[PriorityLabel], 10, 64)
+	return priority, err == nil
+}
+
+func (s *prioritySelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	var highest []*registry.NetworkServiceEndpoint
+	var highestPriority int64
+	highestLabeled := false
+	for _, endpoint := range networkServiceEndpoints {
+		priority, labeled := endpointPriority(endpoint)
+		switch {
+		case len(highest) == 0, labeled && !highestLabeled, labeled && priority > highestPriority:
+			highest = []*registry.NetworkServiceEndpoint{endpoint}
+			highestPriority, highestLabeled = priority, labeled
+		case labeled == highestLabeled && (!labeled || priority == highestPriority):
+			highest = append(highest, endpoint)
+		}
+	}
+	if len(highest) == 0 {
+		return nil
+	}
+	return s.endpointSelector.SelectEndpoint(requestConnection, ns, highest)
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"testing"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func priorityEndpoint(name, priority string) *registry.NetworkServiceEndpoint {
+	endpoint := &registry.NetworkServiceEndpoint{Name: name}
+	if priority != "" {
+		endpoint.Labels = map[string]string{PriorityLabel: priority}
+	}
+	return endpoint
+}
+
+func TestPrioritySelector_SelectEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []*registry.NetworkServiceEndpoint
+		want      map[string]int
+	}{
+		{
+			name: "mixed",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				priorityEndpoint("secondary", "1"),
+				priorityEndpoint("primary-1", "10"),
+				priorityEndpoint("unlabeled", ""),
+				priorityEndpoint("primary-2", "10"),
+				priorityEndpoint("negative", "-5"),
+			},
+			want: map[string]int{"primary-1": 2, "primary-2": 2},
+		},
+		{
+			name: "all equal",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				priorityEndpoint("nse-1", "3"),
+				priorityEndpoint("nse-2", "3"),
+			},
+			want: map[string]int{"nse-1": 2, "nse-2": 2},
+		},
+		{
+			name: "missing is lowest",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				priorityEndpoint("unlabeled", ""),
+				priorityEndpoint("malformed", "high"),
+				priorityEndpoint("negative", "-5"),
+			},
+			want: map[string]int{"negative": 4},
+		},
+		{
+			name: "all missing",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				priorityEndpoint("unlabeled", ""),
+				priorityEndpoint("malformed", "high"),
+			},
+			want: map[string]int{"unlabeled": 2, "malformed": 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			selected := selectNames(NewPrioritySelector(NewRoundRobinSelector()), test.endpoints, 4)
+			if len(selected) != len(test.want) {
+				t.Fatalf("unexpected selections %v", selected)
+			}
+			for name, count := range test.want {
+				if selected[name] != count {
+					t.Errorf("unexpected selections %v", selected)
+				}
+			}
+		})
+	}
+	if NewPrioritySelector(NewRoundRobinSelector()).SelectEndpoint(nil, nil, nil) != nil {
+		t.Errorf("selected endpoint from none")
+	}
+}
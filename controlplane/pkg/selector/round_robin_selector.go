@@ -38,3 +38,21 @@ func (rr *roundRobinSelector) SelectEndpoint(requestConnection *connection.Conne
 	logrus.Infof("RoundRobin selected %v", endpoint)
 	return endpoint
 }
+
+// SelectDeterministic - picks endpoint by connection id hash instead of round robin position.
+func (rr *roundRobinSelector) SelectDeterministic(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	return pickHashed(requestConnection, networkServiceEndpoints)
+}
+
+// PeekEndpoint - returns endpoint SelectEndpoint would return next, round robin position is not changed.
+func (rr *roundRobinSelector) PeekEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	if rr == nil {
+		return nil
+	}
+	if len(networkServiceEndpoints) == 0 {
+		return nil
+	}
+	rr.Lock()
+	defer rr.Unlock()
+	return networkServiceEndpoints[rr.roundRobin[ns.GetName()]%len(networkServiceEndpoints)]
+}
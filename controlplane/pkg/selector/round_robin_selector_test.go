@@ -231,3 +231,30 @@ func Test_roundRobinSelector_SelectEndpoint(t *testing.T) {
 		})
 	}
 }
+
+func Test_roundRobinSelector_PeekEndpoint(t *testing.T) {
+	ns := &registry.NetworkService{
+		Name: "network-service-1",
+	}
+	endpoints := []*registry.NetworkServiceEndpoint{
+		{
+			Name: "NSE-1",
+		},
+		{
+			Name: "NSE-2",
+		},
+	}
+	rr := NewRoundRobinSelector()
+
+	for i := 0; i < 3; i++ {
+		if got := rr.(Peeker).PeekEndpoint(nil, ns, endpoints); got.GetName() != "NSE-1" {
+			t.Errorf("roundRobinSelector.PeekEndpoint() = %v, want NSE-1", got)
+		}
+	}
+	if got := rr.SelectEndpoint(nil, ns, endpoints); got.GetName() != "NSE-1" {
+		t.Errorf("roundRobinSelector.SelectEndpoint() = %v, want NSE-1", got)
+	}
+	if got := rr.(Peeker).PeekEndpoint(nil, ns, endpoints); got.GetName() != "NSE-2" {
+		t.Errorf("roundRobinSelector.PeekEndpoint() = %v, want NSE-2", got)
+	}
+}
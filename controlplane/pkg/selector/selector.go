@@ -1,6 +1,8 @@
 package selector
 
 import (
+	"time"
+
 	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
 	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
 )
@@ -8,3 +10,41 @@ import (
 type Selector interface {
 	SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint
 }
+
+// Peeker - a selector able to tell which endpoint it would select next without advancing its internal state.
+type Peeker interface {
+	PeekEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint
+}
+
+// Scorer - a selector able to tell how good each of endpoints is for request, higher score is better.
+type Scorer interface {
+	ScoreEndpoints(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) []float64
+}
+
+// Candidate - endpoint with its runtime state known to NSMgr, gathered once per selection for scoring selectors.
+type Candidate struct {
+	Endpoint *registry.NetworkServiceEndpoint
+	Manager  *registry.NetworkServiceManager
+	// Connections - client connections routed to the endpoint.
+	Connections int
+	// Healing - client connections routed to the endpoint which are being healed.
+	Healing int
+	// RTT - last measured round trip time to the endpoint, zero if RTTMeasured is false.
+	RTT         time.Duration
+	RTTMeasured bool
+	// Load - last load reported by the endpoint, nil if not known.
+	Load *LoadReport
+}
+
+// LoadReport - load of endpoint backend, as reported in ORCA-style load reports.
+type LoadReport struct {
+	// CPUUtilization - CPU utilization from 0 to 1.
+	CPUUtilization float64
+	// QueueDepth - requests waiting to be served.
+	QueueDepth int
+}
+
+// CandidateScorer - a selector scoring endpoints using their runtime state, higher score is better.
+type CandidateScorer interface {
+	ScoreCandidates(requestConnection *connection.Connection, ns *registry.NetworkService, candidates []*Candidate) []float64
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+// Topology distances between network service managers, nearer managers have smaller distance.
+const (
+	DistanceSameZone = iota
+	DistanceSameRegion
+	DistanceCrossRegion
+	// DistanceUnknown - location of one of managers is not known.
+	DistanceUnknown
+)
+
+// Location - region and zone within it network service manager runs in, zone is optional.
+type Location struct {
+	Region string
+	Zone   string
+}
+
+// Distance - returns topology distance from location to other location.
+func (l Location) Distance(other Location) int {
+	switch {
+	case l.Region == "" || other.Region == "":
+		return DistanceUnknown
+	case l.Region != other.Region:
+		return DistanceCrossRegion
+	case l.Zone != "" && l.Zone == other.Zone:
+		return DistanceSameZone
+	default:
+		return DistanceSameRegion
+	}
+}
+
+// Locator - tells locations of network service managers, false if location is not known.
+type Locator interface {
+	// LocalLocation - returns location of local network service manager.
+	LocalLocation() (Location, bool)
+	// Location - returns location of network service manager with name.
+	Location(managerName string) (Location, bool)
+}
+
+type topologySelector struct {
+	locator          Locator
+	endpointSelector Selector
+}
+
+// NewTopologySelector - creates selector selecting only among endpoints hosted by managers nearest to local manager,
+// by endpointSelector. Endpoints of managers with unknown location are the farthest, if location of local manager is
+// not known all endpoints are selected among.
+func NewTopologySelector(locator Locator, endpointSelector Selector) Selector {
+	return &topologySelector{
+		locator:          locator,
+		endpointSelector: endpointSelector,
+	}
+}
+
+func (s *topologySelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	local, ok := s.locator.LocalLocation()
+	if !ok {
+		return s.endpointSelector.SelectEndpoint(requestConnection, ns, networkServiceEndpoints)
+	}
+	var nearest []*registry.NetworkServiceEndpoint
+	nearestDistance := DistanceUnknown
+	for _, endpoint := range networkServiceEndpoints {
+		distance := DistanceUnknown
+		if location, ok := s.locator.Location(endpoint.GetNetworkServiceManagerName()); ok {
+			distance = local.Distance(location)
+		}
+		switch {
+		case len(nearest) == 0 || distance < nearestDistance:
+			nearest, nearestDistance = []*registry.NetworkServiceEndpoint{endpoint}, distance
+		case distance == nearestDistance:
+			nearest = append(nearest, endpoint)
+		}
+	}
+	if len(nearest) == 0 {
+		return nil
+	}
+	return s.endpointSelector.SelectEndpoint(requestConnection, ns, nearest)
+}
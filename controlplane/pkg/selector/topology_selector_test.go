@@ -0,0 +1,138 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"testing"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+const localManager = "local"
+
+type locatorStub map[string]Location
+
+func (l locatorStub) LocalLocation() (Location, bool) {
+	return l.Location(localManager)
+}
+
+func (l locatorStub) Location(managerName string) (Location, bool) {
+	location, ok := l[managerName]
+	return location, ok
+}
+
+func topologyEndpoint(name, manager string) *registry.NetworkServiceEndpoint {
+	return &registry.NetworkServiceEndpoint{Name: name, NetworkServiceManagerName: manager}
+}
+
+func TestLocation_Distance(t *testing.T) {
+	tests := []struct {
+		from, to Location
+		want     int
+	}{
+		{Location{"eu", "eu-1"}, Location{"eu", "eu-1"}, DistanceSameZone},
+		{Location{"eu", "eu-1"}, Location{"eu", "eu-2"}, DistanceSameRegion},
+		{Location{"eu", ""}, Location{"eu", ""}, DistanceSameRegion},
+		{Location{"eu", "eu-1"}, Location{"us", "eu-1"}, DistanceCrossRegion},
+		{Location{"eu", "eu-1"}, Location{}, DistanceUnknown},
+	}
+	for _, test := range tests {
+		if got := test.from.Distance(test.to); got != test.want {
+			t.Errorf("distance from %v to %v is %d, expected %d", test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestTopologySelector_SelectEndpoint(t *testing.T) {
+	locator := locatorStub{
+		localManager: {Region: "eu", Zone: "eu-1"},
+		"zone":       {Region: "eu", Zone: "eu-1"},
+		"region":     {Region: "eu", Zone: "eu-2"},
+		"remote":     {Region: "us", Zone: "us-1"},
+	}
+	tests := []struct {
+		name      string
+		locator   Locator
+		endpoints []*registry.NetworkServiceEndpoint
+		want      map[string]int
+	}{
+		{
+			name: "same zone",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				topologyEndpoint("remote", "remote"),
+				topologyEndpoint("zone-1", "zone"),
+				topologyEndpoint("region", "region"),
+				topologyEndpoint("zone-2", "zone"),
+			},
+			want: map[string]int{"zone-1": 2, "zone-2": 2},
+		},
+		{
+			name: "same region",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				topologyEndpoint("remote", "remote"),
+				topologyEndpoint("region", "region"),
+				topologyEndpoint("unknown", "unknown"),
+			},
+			want: map[string]int{"region": 4},
+		},
+		{
+			name: "cross region",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				topologyEndpoint("unknown", "unknown"),
+				topologyEndpoint("remote", "remote"),
+			},
+			want: map[string]int{"remote": 4},
+		},
+		{
+			name: "unknown managers",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				topologyEndpoint("unknown-1", "unknown"),
+				topologyEndpoint("unknown-2", "other"),
+			},
+			want: map[string]int{"unknown-1": 2, "unknown-2": 2},
+		},
+		{
+			name:    "local location unknown",
+			locator: locatorStub{"zone": {Region: "eu", Zone: "eu-1"}},
+			endpoints: []*registry.NetworkServiceEndpoint{
+				topologyEndpoint("zone", "zone"),
+				topologyEndpoint("remote", "remote"),
+			},
+			want: map[string]int{"zone": 2, "remote": 2},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			testLocator := test.locator
+			if testLocator == nil {
+				testLocator = locator
+			}
+			selected := selectNames(NewTopologySelector(testLocator, NewRoundRobinSelector()), test.endpoints, 4)
+			if len(selected) != len(test.want) {
+				t.Fatalf("unexpected selections %v", selected)
+			}
+			for name, count := range test.want {
+				if selected[name] != count {
+					t.Errorf("%s selected %d times, expected %d: %v", name, selected[name], count, selected)
+				}
+			}
+		})
+	}
+	if NewTopologySelector(locator, NewRoundRobinSelector()).SelectEndpoint(nil, nil, nil) != nil {
+		t.Errorf("selected endpoint from none")
+	}
+}
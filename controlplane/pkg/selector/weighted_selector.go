@@ -0,0 +1,159 @@
+// Copyright (c) 2020 Cisco and/or its affiliates.
+//
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+const (
+	// CapacityLabel - endpoint label with capacity of endpoint, in any units common for endpoints of network service.
+	CapacityLabel = "capacity"
+	// LoadLabel - endpoint label with current load of endpoint in units of CapacityLabel, weight of endpoint is its
+	// remaining capacity.
+	LoadLabel = "load"
+)
+
+type weightedSelector struct {
+	sync.Mutex
+	current    map[string]map[string]float64
+	roundRobin Selector
+}
+
+// NewWeightedSelector - creates selector distributing selections among endpoints proportionally to their remaining
+// capacity, using smooth weighted round robin. Endpoints without capacity label are not selected while other
+// endpoints have it, if none has it or all are fully loaded selection falls back to round robin.
+func NewWeightedSelector() Selector {
+	return &weightedSelector{
+		current:    map[string]map[string]float64{},
+		roundRobin: NewRoundRobinSelector(),
+	}
+}
+
+// endpointWeight - returns remaining capacity of endpoint and whether it has capacity label.
+func endpointWeight(endpoint *registry.NetworkServiceEndpoint) (float64, bool) {
+	labels := endpoint.GetLabels()
+	capacity, err := strconv.ParseFloat(labels[CapacityLabel], 64)
+	if err != nil {
+		return 0, false
+	}
+	if load, err := strconv.ParseFloat(labels[LoadLabel], 64); err == nil {
+		capacity -= load
+	}
+	if capacity < 0 {
+		return 0, true
+	}
+	return capacity, true
+}
+
+func weights(networkServiceEndpoints []*registry.NetworkServiceEndpoint) ([]float64, float64) {
+	result := make([]float64, len(networkServiceEndpoints))
+	total := 0.0
+	for i, endpoint := range networkServiceEndpoints {
+		result[i], _ = endpointWeight(endpoint)
+		total += result[i]
+	}
+	return result, total
+}
+
+func (s *weightedSelector) SelectEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	endpointWeights, total := weights(networkServiceEndpoints)
+	if total <= 0 {
+		return s.roundRobin.SelectEndpoint(requestConnection, ns, networkServiceEndpoints)
+	}
+	s.Lock()
+	defer s.Unlock()
+	current := s.currentWeights(ns, networkServiceEndpoints)
+	endpoint := pickWeighted(current, networkServiceEndpoints, endpointWeights, total)
+	s.current[ns.GetName()] = current
+	logrus.Infof("Weighted selected %v", endpoint)
+	return endpoint
+}
+
+// PeekEndpoint - returns endpoint SelectEndpoint would return next, weighted round robin state is not changed.
+func (s *weightedSelector) PeekEndpoint(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	endpointWeights, total := weights(networkServiceEndpoints)
+	if total <= 0 {
+		return s.roundRobin.(Peeker).PeekEndpoint(requestConnection, ns, networkServiceEndpoints)
+	}
+	s.Lock()
+	defer s.Unlock()
+	return pickWeighted(s.currentWeights(ns, networkServiceEndpoints), networkServiceEndpoints, endpointWeights, total)
+}
+
+// currentWeights - returns copy of current weights of network service endpoints, weights of endpoints which are
+// gone are dropped.
+func (s *weightedSelector) currentWeights(ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) map[string]float64 {
+	current := map[string]float64{}
+	for _, endpoint := range networkServiceEndpoints {
+		if weight, ok := s.current[ns.GetName()][endpoint.GetName()]; ok {
+			current[endpoint.GetName()] = weight
+		}
+	}
+	return current
+}
+
+// SelectDeterministic - picks endpoint by connection id hash, proportionally to remaining capacity.
+func (s *weightedSelector) SelectDeterministic(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) *registry.NetworkServiceEndpoint {
+	endpointWeights, total := weights(networkServiceEndpoints)
+	if total <= 0 {
+		return pickHashed(requestConnection, networkServiceEndpoints)
+	}
+	point := connectionHash(requestConnection) * total
+	for i, endpoint := range networkServiceEndpoints {
+		if point < endpointWeights[i] {
+			return endpoint
+		}
+		point -= endpointWeights[i]
+	}
+	// Rounding left point past the last weighted endpoint.
+	for i := len(networkServiceEndpoints) - 1; i >= 0; i-- {
+		if endpointWeights[i] > 0 {
+			return networkServiceEndpoints[i]
+		}
+	}
+	return nil
+}
+
+// ScoreEndpoints - scores endpoints by their remaining capacity.
+func (s *weightedSelector) ScoreEndpoints(requestConnection *connection.Connection, ns *registry.NetworkService, networkServiceEndpoints []*registry.NetworkServiceEndpoint) []float64 {
+	endpointWeights, _ := weights(networkServiceEndpoints)
+	return endpointWeights
+}
+
+// pickWeighted - smooth weighted round robin step: every endpoint gains its weight, the one with the most gained
+// is picked and loses total weight.
+func pickWeighted(current map[string]float64, networkServiceEndpoints []*registry.NetworkServiceEndpoint, endpointWeights []float64, total float64) *registry.NetworkServiceEndpoint {
+	best := -1
+	for i, endpoint := range networkServiceEndpoints {
+		if endpointWeights[i] <= 0 {
+			continue
+		}
+		current[endpoint.GetName()] += endpointWeights[i]
+		if best < 0 || current[endpoint.GetName()] > current[networkServiceEndpoints[best].GetName()] {
+			best = i
+		}
+	}
+	current[networkServiceEndpoints[best].GetName()] -= total
+	return networkServiceEndpoints[best]
+}
@@ -0,0 +1,134 @@
+// Copyright 2020 Cisco Systems, Inc.
+// SPDX-License-Identifier: Apache-2.0
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at:
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package selector
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/connection"
+	"github.com/networkservicemesh/networkservicemesh/controlplane/api/registry"
+)
+
+func newWeightedEndpoint(name string, labels map[string]string) *registry.NetworkServiceEndpoint {
+	return &registry.NetworkServiceEndpoint{
+		Name:   name,
+		Labels: labels,
+	}
+}
+
+func selectNames(s Selector, endpoints []*registry.NetworkServiceEndpoint, count int) map[string]int {
+	ns := &registry.NetworkService{Name: "network-service"}
+	result := map[string]int{}
+	for i := 0; i < count; i++ {
+		result[s.SelectEndpoint(nil, ns, endpoints).GetName()]++
+	}
+	return result
+}
+
+func TestWeightedSelector_SelectEndpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoints []*registry.NetworkServiceEndpoint
+		want      map[string]int
+	}{
+		{
+			name: "proportional to capacity",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				newWeightedEndpoint("nse-1", map[string]string{CapacityLabel: "30"}),
+				newWeightedEndpoint("nse-2", map[string]string{CapacityLabel: "10"}),
+			},
+			want: map[string]int{"nse-1": 6, "nse-2": 2},
+		},
+		{
+			name: "proportional to remaining capacity",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				newWeightedEndpoint("nse-1", map[string]string{CapacityLabel: "30", LoadLabel: "20"}),
+				newWeightedEndpoint("nse-2", map[string]string{CapacityLabel: "10"}),
+			},
+			want: map[string]int{"nse-1": 4, "nse-2": 4},
+		},
+		{
+			name: "endpoints without capacity are not selected",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				newWeightedEndpoint("nse-1", map[string]string{CapacityLabel: "10"}),
+				newWeightedEndpoint("nse-2", nil),
+			},
+			want: map[string]int{"nse-1": 8},
+		},
+		{
+			name: "round robin without capacity labels",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				newWeightedEndpoint("nse-1", nil),
+				newWeightedEndpoint("nse-2", nil),
+			},
+			want: map[string]int{"nse-1": 4, "nse-2": 4},
+		},
+		{
+			name: "round robin with zero total weight",
+			endpoints: []*registry.NetworkServiceEndpoint{
+				newWeightedEndpoint("nse-1", map[string]string{CapacityLabel: "0"}),
+				newWeightedEndpoint("nse-2", map[string]string{CapacityLabel: "10", LoadLabel: "12"}),
+			},
+			want: map[string]int{"nse-1": 4, "nse-2": 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := selectNames(NewWeightedSelector(), tt.endpoints, 8); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("weightedSelector.SelectEndpoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWeightedSelector_PeekEndpoint(t *testing.T) {
+	ns := &registry.NetworkService{Name: "network-service"}
+	endpoints := []*registry.NetworkServiceEndpoint{
+		newWeightedEndpoint("nse-1", map[string]string{CapacityLabel: "10"}),
+		newWeightedEndpoint("nse-2", map[string]string{CapacityLabel: "20"}),
+	}
+	s := NewWeightedSelector()
+	for i := 0; i < 3; i++ {
+		peeked := s.(Peeker).PeekEndpoint(nil, ns, endpoints)
+		if got := s.SelectEndpoint(nil, ns, endpoints); got != peeked {
+			t.Errorf("weightedSelector.PeekEndpoint() = %v, want %v", peeked, got)
+		}
+	}
+}
+
+func TestWeightedSelector_SelectDeterministic(t *testing.T) {
+	ns := &registry.NetworkService{Name: "network-service"}
+	endpoints := []*registry.NetworkServiceEndpoint{
+		newWeightedEndpoint("nse-1", map[string]string{CapacityLabel: "0"}),
+		newWeightedEndpoint("nse-2", map[string]string{CapacityLabel: "10"}),
+		newWeightedEndpoint("nse-3", map[string]string{CapacityLabel: "30"}),
+	}
+	s := NewWeightedSelector().(DeterministicSelector)
+	counts := map[string]int{}
+	for i := 0; i < 400; i++ {
+		request := &connection.Connection{Id: strconv.Itoa(i)}
+		selected := s.SelectDeterministic(request, ns, endpoints)
+		if again := s.SelectDeterministic(request, ns, endpoints); again != selected {
+			t.Errorf("weightedSelector.SelectDeterministic() = %v, then %v", selected.GetName(), again.GetName())
+		}
+		counts[selected.GetName()]++
+	}
+	if counts["nse-1"] != 0 || counts["nse-2"] == 0 || counts["nse-3"] < 2*counts["nse-2"] {
+		t.Errorf("weightedSelector.SelectDeterministic() distribution = %v", counts)
+	}
+}
@@ -38,7 +38,8 @@ type ServiceRegistry interface {
 	ForwarderConnection(ctx context.Context, forwarder *model.Forwarder) (forwarderapi.ForwarderClient, *grpc.ClientConn, error)
 
 	EndpointConnection(ctx context.Context, endpoint *model.Endpoint) (networkservice.NetworkServiceClient, *grpc.ClientConn, error)
-	RemoteNetworkServiceClient(ctx context.Context, nsm *registry.NetworkServiceManager) (networkservice.NetworkServiceClient, *grpc.ClientConn, error)
+	// RemoteNetworkServiceClient - dials remote network service manager, opts are applied on top of default ones.
+	RemoteNetworkServiceClient(ctx context.Context, nsm *registry.NetworkServiceManager, opts ...grpc.DialOption) (networkservice.NetworkServiceClient, *grpc.ClientConn, error)
 
 	WaitForForwarderAvailable(ctx context.Context, model model.Model, timeout time.Duration) error
 
@@ -189,7 +189,7 @@ func (impl *nsmdTestServiceRegistry) WorkspaceName(endpoint *registry.NSERegistr
 	return ""
 }
 
-func (impl *nsmdTestServiceRegistry) RemoteNetworkServiceClient(ctx context.Context, nsm *registry.NetworkServiceManager) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
+func (impl *nsmdTestServiceRegistry) RemoteNetworkServiceClient(ctx context.Context, nsm *registry.NetworkServiceManager, opts ...grpc.DialOption) (networkservice.NetworkServiceClient, *grpc.ClientConn, error) {
 	span := spanhelper.FromContext(ctx, "RemoteNetworkServiceClient")
 	defer span.Finish()
 	err := tools.WaitForPortAvailable(span.Context(), "tcp", nsm.Url, 100*time.Millisecond)
@@ -198,7 +198,7 @@ func (impl *nsmdTestServiceRegistry) RemoteNetworkServiceClient(ctx context.Cont
 	}
 
 	span.Logger().Info("Remote Network Service is available, attempting to connect...")
-	conn, err := tools.DialContextTCP(span.Context(), nsm.GetUrl())
+	conn, err := tools.DialContextTCP(span.Context(), nsm.GetUrl(), opts...)
 	span.LogError(err)
 	if err != nil {
 		span.Logger().Errorf("Failed to dial Network Service Registry at %s: %s", nsm.Url, err)